@@ -9,6 +9,8 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
 )
 
 // API wires all Forge-style HTTP handlers together for the Weave system.
@@ -37,6 +39,9 @@ func (a *API) RegisterRoutes(router forge.Router) {
 	a.registerCollectionRoutes(router)
 	a.registerDocumentRoutes(router)
 	a.registerRetrievalRoutes(router)
+	a.registerTrashRoutes(router)
+	a.registerQuotaRoutes(router)
+	a.registerUsageRoutes(router)
 }
 
 // registerCollectionRoutes registers collection management routes.
@@ -71,7 +76,7 @@ func (a *API) registerCollectionRoutes(router forge.Router) {
 
 	_ = g.DELETE("/collections/:collectionId", a.deleteCollection, //nolint:errcheck // route registration
 		forge.WithSummary("Delete collection"),
-		forge.WithDescription("Deletes a collection and all its documents and chunks."),
+		forge.WithDescription("Moves a collection to the trash. It is excluded from retrieval until restored and purged after the configured delay."),
 		forge.WithOperationID("deleteCollection"),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -116,6 +121,15 @@ func (a *API) registerDocumentRoutes(router forge.Router) {
 		forge.WithErrorResponses(),
 	)
 
+	_ = g.POST("/collections/:collectionId/preview", a.previewIngest, //nolint:errcheck // route registration
+		forge.WithSummary("Preview ingestion"),
+		forge.WithDescription("Runs the loader and chunker with the collection's settings and returns the chunks and estimated embedding cost without storing or embedding anything."),
+		forge.WithOperationID("previewIngest"),
+		forge.WithRequestSchema(PreviewIngestRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Ingestion preview", &engine.PreviewResult{}),
+		forge.WithErrorResponses(),
+	)
+
 	_ = g.GET("/collections/:collectionId/documents", a.listDocuments, //nolint:errcheck // route registration
 		forge.WithSummary("List documents"),
 		forge.WithDescription("Returns documents in a collection with optional state filter."),
@@ -135,7 +149,7 @@ func (a *API) registerDocumentRoutes(router forge.Router) {
 
 	_ = g.DELETE("/documents/:documentId", a.deleteDocument, //nolint:errcheck // route registration
 		forge.WithSummary("Delete document"),
-		forge.WithDescription("Deletes a document and its chunks from both metadata and vector stores."),
+		forge.WithDescription("Moves a document to the trash. It is excluded from retrieval until restored and purged after the configured delay."),
 		forge.WithOperationID("deleteDocument"),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -164,3 +178,99 @@ func (a *API) registerRetrievalRoutes(router forge.Router) {
 		forge.WithErrorResponses(),
 	)
 }
+
+// registerTrashRoutes registers trash listing, restore, and purge routes.
+func (a *API) registerTrashRoutes(router forge.Router) {
+	g := router.Group("/v1", forge.WithGroupTags("trash"))
+
+	_ = g.GET("/trash", a.listTrash, //nolint:errcheck // route registration
+		forge.WithSummary("List trash"),
+		forge.WithDescription("Returns soft-deleted collections and documents."),
+		forge.WithOperationID("listTrash"),
+		forge.WithResponseSchema(http.StatusOK, "Trashed items", &engine.TrashResult{}),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.POST("/trash/collections/:collectionId/restore", a.restoreCollection, //nolint:errcheck // route registration
+		forge.WithSummary("Restore collection"),
+		forge.WithDescription("Moves a collection out of the trash."),
+		forge.WithOperationID("restoreCollection"),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.DELETE("/trash/collections/:collectionId", a.purgeCollection, //nolint:errcheck // route registration
+		forge.WithSummary("Purge collection"),
+		forge.WithDescription("Permanently deletes a trashed collection and all its documents and chunks."),
+		forge.WithOperationID("purgeCollection"),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.POST("/trash/documents/:documentId/restore", a.restoreDocument, //nolint:errcheck // route registration
+		forge.WithSummary("Restore document"),
+		forge.WithDescription("Moves a document out of the trash. Fails if its collection is still trashed."),
+		forge.WithOperationID("restoreDocument"),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.DELETE("/trash/documents/:documentId", a.purgeDocument, //nolint:errcheck // route registration
+		forge.WithSummary("Purge document"),
+		forge.WithDescription("Permanently deletes a trashed document and its chunks from both metadata and vector stores."),
+		forge.WithOperationID("purgeDocument"),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	)
+}
+
+// registerQuotaRoutes registers tenant quota administration routes.
+func (a *API) registerQuotaRoutes(router forge.Router) {
+	g := router.Group("/v1", forge.WithGroupTags("quotas"))
+
+	_ = g.GET("/quotas", a.listQuotas, //nolint:errcheck // route registration
+		forge.WithSummary("List quotas"),
+		forge.WithDescription("Returns all tenant-specific quotas."),
+		forge.WithOperationID("listQuotas"),
+		forge.WithResponseSchema(http.StatusOK, "Quota list", []*quota.Quota{}),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.GET("/quotas/:tenantId", a.getQuota, //nolint:errcheck // route registration
+		forge.WithSummary("Get quota"),
+		forge.WithDescription("Returns the limits in effect for a tenant and its current usage."),
+		forge.WithOperationID("getQuota"),
+		forge.WithResponseSchema(http.StatusOK, "Tenant quota", &engine.TenantQuota{}),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.PUT("/quotas/:tenantId", a.setQuota, //nolint:errcheck // route registration
+		forge.WithSummary("Set quota"),
+		forge.WithDescription("Creates or replaces a tenant's quota, overriding the default limits."),
+		forge.WithOperationID("setQuota"),
+		forge.WithRequestSchema(SetQuotaRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Tenant quota", &quota.Quota{}),
+		forge.WithErrorResponses(),
+	)
+
+	_ = g.DELETE("/quotas/:tenantId", a.deleteQuota, //nolint:errcheck // route registration
+		forge.WithSummary("Delete quota"),
+		forge.WithDescription("Removes a tenant's quota so the default limits apply again."),
+		forge.WithOperationID("deleteQuota"),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	)
+}
+
+// registerUsageRoutes registers embedding usage reporting routes.
+func (a *API) registerUsageRoutes(router forge.Router) {
+	g := router.Group("/v1", forge.WithGroupTags("usage"))
+
+	_ = g.GET("/usage", a.queryUsage, //nolint:errcheck // route registration
+		forge.WithSummary("Query usage"),
+		forge.WithDescription("Returns embedding token usage aggregated by day or month, per tenant, app, collection, operation, and model."),
+		forge.WithOperationID("queryUsage"),
+		forge.WithResponseSchema(http.StatusOK, "Usage summaries", []*usage.Summary{}),
+		forge.WithErrorResponses(),
+	)
+}
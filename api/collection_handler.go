@@ -24,11 +24,13 @@ func (a *API) createCollection(ctx forge.Context, req *CreateCollectionRequest)
 		ChunkStrategy:  req.ChunkStrategy,
 		ChunkSize:      req.ChunkSize,
 		ChunkOverlap:   req.ChunkOverlap,
+		Language:       req.Language,
+		EmbedTemplate:  req.EmbedTemplate,
 		Metadata:       req.Metadata,
 	}
 
 	if err := a.eng.CreateCollection(ctx.Context(), col); err != nil {
-		return nil, fmt.Errorf("create collection: %w", err)
+		return nil, mapStoreError(fmt.Errorf("create collection: %w", err))
 	}
 
 	return col, ctx.JSON(http.StatusCreated, col)
@@ -17,8 +17,9 @@ func (a *API) ingestDocument(ctx forge.Context, req *IngestDocumentRequest) (*en
 		return nil, forge.BadRequest(fmt.Sprintf("invalid collection ID: %v", err))
 	}
 
-	if req.Content == "" {
-		return nil, forge.BadRequest("content is required")
+	content, err := requestContent(req.Content, req.ContentBase64)
+	if err != nil {
+		return nil, forge.BadRequest(err.Error())
 	}
 
 	result, err := a.eng.Ingest(ctx.Context(), &engine.IngestInput{
@@ -26,11 +27,11 @@ func (a *API) ingestDocument(ctx forge.Context, req *IngestDocumentRequest) (*en
 		Title:        req.Title,
 		Source:       req.Source,
 		SourceType:   req.SourceType,
-		Content:      req.Content,
+		Content:      content,
 		Metadata:     req.Metadata,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("ingest document: %w", err)
+		return nil, mapStoreError(fmt.Errorf("ingest document: %w", err))
 	}
 
 	return result, ctx.JSON(http.StatusCreated, result)
@@ -44,27 +45,54 @@ func (a *API) ingestBatch(ctx forge.Context, req *IngestBatchRequest) ([]*engine
 
 	inputs := make([]*engine.IngestInput, len(req.Documents))
 	for i, doc := range req.Documents {
-		if doc.Content == "" {
-			return nil, forge.BadRequest(fmt.Sprintf("document %d: content is required", i))
+		content, err := requestContent(doc.Content, doc.ContentBase64)
+		if err != nil {
+			return nil, forge.BadRequest(fmt.Sprintf("document %d: %v", i, err))
 		}
 		inputs[i] = &engine.IngestInput{
 			CollectionID: colID,
 			Title:        doc.Title,
 			Source:       doc.Source,
 			SourceType:   doc.SourceType,
-			Content:      doc.Content,
+			Content:      content,
 			Metadata:     doc.Metadata,
 		}
 	}
 
 	results, err := a.eng.IngestBatch(ctx.Context(), inputs)
 	if err != nil {
-		return results, fmt.Errorf("ingest batch: %w", err)
+		return results, mapStoreError(fmt.Errorf("ingest batch: %w", err))
 	}
 
 	return results, ctx.JSON(http.StatusCreated, results)
 }
 
+func (a *API) previewIngest(ctx forge.Context, req *PreviewIngestRequest) (*engine.PreviewResult, error) {
+	colID, err := id.ParseCollectionID(ctx.Param("collectionId"))
+	if err != nil {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid collection ID: %v", err))
+	}
+	content, err := requestContent(req.Content, req.ContentBase64)
+	if err != nil {
+		return nil, forge.BadRequest(err.Error())
+	}
+
+	var opts []engine.PreviewOption
+	if req.Enrich {
+		opts = append(opts, engine.WithPreviewEnricher())
+	}
+	result, err := a.eng.PreviewIngest(ctx.Context(), &engine.IngestInput{
+		CollectionID: colID,
+		SourceType:   req.SourceType,
+		Content:      content,
+	}, opts...)
+	if err != nil {
+		return nil, mapStoreError(fmt.Errorf("preview ingest: %w", err))
+	}
+
+	return result, ctx.JSON(http.StatusOK, result)
+}
+
 func (a *API) getDocument(ctx forge.Context, _ *GetDocumentRequest) (*document.Document, error) {
 	docID, err := id.ParseDocumentID(ctx.Param("documentId"))
 	if err != nil {
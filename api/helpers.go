@@ -1,11 +1,15 @@
 package api
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/xraph/forge"
 
 	"github.com/xraph/weave"
+	"github.com/xraph/weave/quota"
 )
 
 // mapStoreError maps domain errors to Forge HTTP errors.
@@ -16,11 +20,54 @@ func mapStoreError(err error) error {
 	if isNotFound(err) {
 		return forge.NotFound(err.Error())
 	}
+	if errors.Is(err, weave.ErrInvalidTemplate) {
+		return forge.BadRequest(err.Error())
+	}
+	if isConflict(err) {
+		return forge.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		// Periodic limits reset, so the client may retry later; the others
+		// need an explicit quota change.
+		if exceeded.Resource.Periodic() {
+			return forge.NewHTTPError(http.StatusTooManyRequests, err.Error())
+		}
+		return forge.NewHTTPError(http.StatusForbidden, err.Error())
+	}
 	return err
 }
 
+// requestContent returns the document content of a request, given as text
+// or as base64 for binary formats. Exactly one of the two must be set.
+func requestContent(content, contentBase64 string) (string, error) {
+	switch {
+	case content != "" && contentBase64 != "":
+		return "", errors.New("set either content or content_base64, not both")
+	case contentBase64 != "":
+		raw, err := base64.StdEncoding.DecodeString(contentBase64)
+		if err != nil {
+			return "", fmt.Errorf("invalid content_base64: %w", err)
+		}
+		if len(raw) == 0 {
+			return "", errors.New("content is required")
+		}
+		return string(raw), nil
+	case content == "":
+		return "", errors.New("content is required")
+	}
+	return content, nil
+}
+
 func isNotFound(err error) bool {
 	return errors.Is(err, weave.ErrCollectionNotFound) ||
 		errors.Is(err, weave.ErrDocumentNotFound) ||
-		errors.Is(err, weave.ErrChunkNotFound)
+		errors.Is(err, weave.ErrChunkNotFound) ||
+		errors.Is(err, weave.ErrQuotaNotFound)
+}
+
+func isConflict(err error) bool {
+	return errors.Is(err, weave.ErrCollectionTrashed) ||
+		errors.Is(err, weave.ErrDocumentTrashed) ||
+		errors.Is(err, weave.ErrNotTrashed)
 }
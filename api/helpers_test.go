@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave/quota"
+)
+
+func TestMapStoreErrorQuota(t *testing.T) {
+	tests := []struct {
+		resource quota.Resource
+		want     int
+	}{
+		{quota.ResourceEmbeddingTokens, http.StatusTooManyRequests},
+		{quota.ResourceCollections, http.StatusForbidden},
+		{quota.ResourceDocuments, http.StatusForbidden},
+		{quota.ResourceChunks, http.StatusForbidden},
+		{quota.ResourceStoredBytes, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		exceeded := &quota.ExceededError{TenantID: "t", Resource: tt.resource, Limit: 1, Used: 1, Requested: 1}
+		// Engine errors may wrap the quota error.
+		err := mapStoreError(fmt.Errorf("weave: ingest failed: %w", exceeded))
+
+		var httpErr interface{ StatusCode() int }
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("%s: got %v, want an HTTP error", tt.resource, err)
+		}
+		if got := httpErr.StatusCode(); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.resource, got, tt.want)
+		}
+	}
+}
+
+func TestMapStoreErrorPassesThroughOthers(t *testing.T) {
+	err := fmt.Errorf("weave: store: %w", errors.New("boom"))
+	if got := mapStoreError(err); got != err {
+		t.Errorf("got %v, want the error unchanged", got)
+	}
+}
+
+func TestRequestContent(t *testing.T) {
+	// Bytes that are not valid UTF-8 survive base64 unchanged.
+	binary := "PK\x03\x04\xff\xfe"
+	got, err := requestContent("", base64.StdEncoding.EncodeToString([]byte(binary)))
+	if err != nil || got != binary {
+		t.Errorf("base64 content = %q, %v", got, err)
+	}
+	if got, err := requestContent("plain text", ""); err != nil || got != "plain text" {
+		t.Errorf("text content = %q, %v", got, err)
+	}
+	for _, tt := range []struct{ content, b64 string }{
+		{"", ""},
+		{"text", "dGV4dA=="},
+		{"", "not base64!"},
+	} {
+		if _, err := requestContent(tt.content, tt.b64); err == nil {
+			t.Errorf("requestContent(%q, %q) succeeded", tt.content, tt.b64)
+		}
+	}
+}
+
+func TestUsageRange(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		from, to   string
+		start, end time.Time
+		err        string
+	}{
+		{"", "", time.Time{}, time.Time{}, ""},
+		{"2024-03-01", "", march, time.Time{}, ""},
+		{"", "2024-03-01T00:00:00Z", time.Time{}, march, ""},
+		{"2024-02-29T23:00:00-01:00", "2024-03-02", march, march.AddDate(0, 0, 1), ""},
+		{"yesterday", "", time.Time{}, time.Time{}, "invalid from"},
+		{"", "2024-13-01", time.Time{}, time.Time{}, "invalid to"},
+		{"2024-03-01", "2024-03-01", time.Time{}, time.Time{}, "invalid range"},
+		{"2024-03-02", "2024-03-01", time.Time{}, time.Time{}, "invalid range"},
+	}
+	for _, tt := range tests {
+		start, end, err := usageRange(tt.from, tt.to)
+		if tt.err != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("usageRange(%q, %q) error = %v, want %q", tt.from, tt.to, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("usageRange(%q, %q): %v", tt.from, tt.to, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("usageRange(%q, %q) = %v, %v, want %v, %v", tt.from, tt.to, start, end, tt.start, tt.end)
+		}
+	}
+}
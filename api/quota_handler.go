@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/xraph/forge"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/quota"
+)
+
+func (a *API) listQuotas(ctx forge.Context, _ *ListQuotasRequest) ([]*quota.Quota, error) {
+	quotas, err := a.eng.ListTenantQuotas(ctx.Context())
+	if err != nil {
+		return nil, fmt.Errorf("list quotas: %w", err)
+	}
+
+	return quotas, ctx.JSON(http.StatusOK, quotas)
+}
+
+func (a *API) getQuota(ctx forge.Context, _ *GetQuotaRequest) (*engine.TenantQuota, error) {
+	tq, err := a.eng.GetTenantQuota(ctx.Context(), ctx.Param("tenantId"))
+	if err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return tq, ctx.JSON(http.StatusOK, tq)
+}
+
+func (a *API) setQuota(ctx forge.Context, req *SetQuotaRequest) (*quota.Quota, error) {
+	tenantID := ctx.Param("tenantId")
+	if tenantID == "" {
+		return nil, forge.BadRequest("tenant ID is required")
+	}
+
+	limits := quota.Limits{
+		MaxCollections:             req.MaxCollections,
+		MaxDocuments:               req.MaxDocuments,
+		MaxChunks:                  req.MaxChunks,
+		MaxStoredBytes:             req.MaxStoredBytes,
+		MaxEmbeddingTokensPerMonth: req.MaxEmbeddingTokensPerMonth,
+	}
+	if limits.MaxCollections < 0 || limits.MaxDocuments < 0 || limits.MaxChunks < 0 ||
+		limits.MaxStoredBytes < 0 || limits.MaxEmbeddingTokensPerMonth < 0 {
+		return nil, forge.BadRequest("limits must not be negative")
+	}
+
+	q := &quota.Quota{
+		Entity:   weave.NewEntity(),
+		TenantID: tenantID,
+		Limits:   limits,
+	}
+	if err := a.eng.SetTenantQuota(ctx.Context(), q); err != nil {
+		return nil, fmt.Errorf("set quota: %w", err)
+	}
+
+	return q, ctx.JSON(http.StatusOK, q)
+}
+
+func (a *API) deleteQuota(ctx forge.Context, _ *DeleteQuotaRequest) (*struct{}, error) {
+	if err := a.eng.DeleteTenantQuota(ctx.Context(), ctx.Param("tenantId")); err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return nil, ctx.NoContent(http.StatusNoContent)
+}
@@ -17,6 +17,8 @@ type CreateCollectionRequest struct {
 	ChunkStrategy  string            `json:"chunk_strategy,omitempty" description:"Chunking strategy (recursive, fixed, etc.)"`
 	ChunkSize      int               `json:"chunk_size,omitempty" description:"Target chunk size in tokens"`
 	ChunkOverlap   int               `json:"chunk_overlap,omitempty" description:"Overlap between chunks in tokens"`
+	Language       string            `json:"language,omitempty" description:"Content language for sentence segmentation (e.g. en, de, zh, ja, th)"`
+	EmbedTemplate  string            `json:"embed_template,omitempty" description:"Go text/template rendered in front of each chunk when embedding"`
 	Metadata       map[string]string `json:"metadata,omitempty" description:"Custom metadata"`
 }
 
@@ -52,26 +54,37 @@ type ReindexCollectionRequest struct {
 
 // IngestDocumentRequest is the request body for ingesting a document.
 type IngestDocumentRequest struct {
-	CollectionID string            `path:"collectionId" description:"Collection ID"`
-	Title        string            `json:"title,omitempty" description:"Document title"`
-	Source       string            `json:"source,omitempty" description:"Source identifier (URL, path, etc.)"`
-	SourceType   string            `json:"source_type,omitempty" description:"MIME type or format hint"`
-	Content      string            `json:"content" description:"Document text content"`
-	Metadata     map[string]string `json:"metadata,omitempty" description:"Custom metadata"`
+	CollectionID  string            `path:"collectionId" description:"Collection ID"`
+	Title         string            `json:"title,omitempty" description:"Document title"`
+	Source        string            `json:"source,omitempty" description:"Source identifier (URL, path, etc.)"`
+	SourceType    string            `json:"source_type,omitempty" description:"MIME type or format hint"`
+	Content       string            `json:"content,omitempty" description:"Document text content"`
+	ContentBase64 string            `json:"content_base64,omitempty" description:"Base64-encoded document bytes for binary formats such as PDF or DOCX, instead of content"`
+	Metadata      map[string]string `json:"metadata,omitempty" description:"Custom metadata"`
 }
 
 // IngestBatchRequest is the request body for batch document ingestion.
 type IngestBatchRequest struct {
 	CollectionID string `path:"collectionId" description:"Collection ID"`
 	Documents    []struct {
-		Title      string            `json:"title,omitempty" description:"Document title"`
-		Source     string            `json:"source,omitempty" description:"Source identifier"`
-		SourceType string            `json:"source_type,omitempty" description:"MIME type or format"`
-		Content    string            `json:"content" description:"Document text content"`
-		Metadata   map[string]string `json:"metadata,omitempty" description:"Custom metadata"`
+		Title         string            `json:"title,omitempty" description:"Document title"`
+		Source        string            `json:"source,omitempty" description:"Source identifier"`
+		SourceType    string            `json:"source_type,omitempty" description:"MIME type or format"`
+		Content       string            `json:"content,omitempty" description:"Document text content"`
+		ContentBase64 string            `json:"content_base64,omitempty" description:"Base64-encoded document bytes, instead of content"`
+		Metadata      map[string]string `json:"metadata,omitempty" description:"Custom metadata"`
 	} `json:"documents" description:"Documents to ingest"`
 }
 
+// PreviewIngestRequest is the request body for a dry-run ingestion preview.
+type PreviewIngestRequest struct {
+	CollectionID  string `path:"collectionId" description:"Collection ID"`
+	SourceType    string `json:"source_type,omitempty" description:"MIME type or format hint"`
+	Content       string `json:"content,omitempty" description:"Document text content"`
+	ContentBase64 string `json:"content_base64,omitempty" description:"Base64-encoded document bytes, instead of content"`
+	Enrich        bool   `json:"enrich,omitempty" description:"Run the engine's enricher, which may call a language model"`
+}
+
 // GetDocumentRequest is the request for getting a document by ID.
 type GetDocumentRequest struct {
 	DocumentID string `path:"documentId" description:"Document ID"`
@@ -101,6 +114,7 @@ type RetrieveRequest struct {
 	TopK         int     `json:"top_k,omitempty" description:"Maximum number of results"`
 	MinScore     float64 `json:"min_score,omitempty" description:"Minimum relevance score threshold"`
 	Strategy     string  `json:"strategy,omitempty" description:"Retrieval strategy (similarity, mmr, hybrid)"`
+	Parents      bool    `json:"parents,omitempty" description:"Return the parent chunks of matching child chunks"`
 }
 
 // HybridSearchRequest is the request body for hybrid search.
@@ -112,6 +126,76 @@ type HybridSearchRequest struct {
 	MinScore    float64           `json:"min_score,omitempty" description:"Minimum relevance score threshold"`
 }
 
+// ──────────────────────────────────────────────────
+// Trash requests
+// ──────────────────────────────────────────────────
+
+// ListTrashRequest is the request for listing trashed items.
+type ListTrashRequest struct{}
+
+// RestoreCollectionRequest is the request for restoring a trashed collection.
+type RestoreCollectionRequest struct {
+	CollectionID string `path:"collectionId" description:"Collection ID"`
+}
+
+// PurgeCollectionRequest is the request for permanently deleting a trashed collection.
+type PurgeCollectionRequest struct {
+	CollectionID string `path:"collectionId" description:"Collection ID"`
+}
+
+// RestoreDocumentRequest is the request for restoring a trashed document.
+type RestoreDocumentRequest struct {
+	DocumentID string `path:"documentId" description:"Document ID"`
+}
+
+// PurgeDocumentRequest is the request for permanently deleting a trashed document.
+type PurgeDocumentRequest struct {
+	DocumentID string `path:"documentId" description:"Document ID"`
+}
+
+// ──────────────────────────────────────────────────
+// Quota requests
+// ──────────────────────────────────────────────────
+
+// ListQuotasRequest is the request for listing tenant quotas.
+type ListQuotasRequest struct{}
+
+// GetQuotaRequest is the request for getting a tenant's limits and usage.
+type GetQuotaRequest struct {
+	TenantID string `path:"tenantId" description:"Tenant ID"`
+}
+
+// SetQuotaRequest is the request body for setting a tenant's quota.
+// Zero limits are unlimited.
+type SetQuotaRequest struct {
+	TenantID                   string `path:"tenantId" description:"Tenant ID"`
+	MaxCollections             int64  `json:"max_collections,omitempty" description:"Maximum number of collections"`
+	MaxDocuments               int64  `json:"max_documents,omitempty" description:"Maximum number of documents"`
+	MaxChunks                  int64  `json:"max_chunks,omitempty" description:"Maximum number of chunks"`
+	MaxStoredBytes             int64  `json:"max_stored_bytes,omitempty" description:"Maximum stored document content in bytes"`
+	MaxEmbeddingTokensPerMonth int64  `json:"max_embedding_tokens_per_month,omitempty" description:"Maximum embedding tokens per calendar month"`
+}
+
+// DeleteQuotaRequest is the request for removing a tenant's quota.
+type DeleteQuotaRequest struct {
+	TenantID string `path:"tenantId" description:"Tenant ID"`
+}
+
+// ──────────────────────────────────────────────────
+// Usage requests
+// ──────────────────────────────────────────────────
+
+// QueryUsageRequest is the request for aggregated embedding token usage.
+type QueryUsageRequest struct {
+	TenantID     string `query:"tenant_id" description:"Filter by tenant"`
+	AppID        string `query:"app_id" description:"Filter by app"`
+	CollectionID string `query:"collection_id" description:"Filter by collection ID"`
+	Operation    string `query:"operation" description:"Filter by operation (ingest, query, reindex)"`
+	From         string `query:"from" description:"Start of the range, inclusive (RFC 3339 or YYYY-MM-DD)"`
+	To           string `query:"to" description:"End of the range, exclusive (RFC 3339 or YYYY-MM-DD)"`
+	Granularity  string `query:"granularity" description:"Bucket size: day (default) or month"`
+}
+
 // ──────────────────────────────────────────────────
 // Helpers
 // ──────────────────────────────────────────────────
@@ -20,12 +20,16 @@ func (a *API) retrieve(ctx forge.Context, req *RetrieveRequest) ([]engine.Scored
 		return nil, forge.BadRequest("query is required")
 	}
 
-	results, err := a.eng.Retrieve(ctx.Context(), req.Query,
+	opts := []engine.RetrieveOption{
 		engine.WithCollection(colID),
 		engine.WithTopK(req.TopK),
 		engine.WithMinScore(req.MinScore),
 		engine.WithStrategy(req.Strategy),
-	)
+	}
+	if req.Parents {
+		opts = append(opts, engine.WithParents())
+	}
+	results, err := a.eng.Retrieve(ctx.Context(), req.Query, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("retrieve: %w", err)
 	}
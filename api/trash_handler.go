@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/xraph/forge"
+
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+)
+
+func (a *API) listTrash(ctx forge.Context, _ *ListTrashRequest) (*engine.TrashResult, error) {
+	trash, err := a.eng.ListTrash(ctx.Context())
+	if err != nil {
+		return nil, fmt.Errorf("list trash: %w", err)
+	}
+
+	return trash, ctx.JSON(http.StatusOK, trash)
+}
+
+func (a *API) restoreCollection(ctx forge.Context, _ *RestoreCollectionRequest) (*struct{}, error) {
+	colID, err := id.ParseCollectionID(ctx.Param("collectionId"))
+	if err != nil {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid collection ID: %v", err))
+	}
+
+	if err := a.eng.RestoreCollection(ctx.Context(), colID); err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return nil, ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *API) purgeCollection(ctx forge.Context, _ *PurgeCollectionRequest) (*struct{}, error) {
+	colID, err := id.ParseCollectionID(ctx.Param("collectionId"))
+	if err != nil {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid collection ID: %v", err))
+	}
+
+	if err := a.eng.PurgeCollection(ctx.Context(), colID); err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return nil, ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *API) restoreDocument(ctx forge.Context, _ *RestoreDocumentRequest) (*struct{}, error) {
+	docID, err := id.ParseDocumentID(ctx.Param("documentId"))
+	if err != nil {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid document ID: %v", err))
+	}
+
+	if err := a.eng.RestoreDocument(ctx.Context(), docID); err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return nil, ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *API) purgeDocument(ctx forge.Context, _ *PurgeDocumentRequest) (*struct{}, error) {
+	docID, err := id.ParseDocumentID(ctx.Param("documentId"))
+	if err != nil {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid document ID: %v", err))
+	}
+
+	if err := a.eng.PurgeDocument(ctx.Context(), docID); err != nil {
+		return nil, mapStoreError(err)
+	}
+
+	return nil, ctx.NoContent(http.StatusNoContent)
+}
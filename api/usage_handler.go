@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xraph/forge"
+
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/usage"
+)
+
+func (a *API) queryUsage(ctx forge.Context, req *QueryUsageRequest) ([]*usage.Summary, error) {
+	q := &usage.Query{
+		TenantID:    req.TenantID,
+		AppID:       req.AppID,
+		Operation:   usage.Operation(req.Operation),
+		Granularity: usage.Granularity(req.Granularity),
+	}
+	if q.Granularity != "" && !q.Granularity.Valid() {
+		return nil, forge.BadRequest(fmt.Sprintf("invalid granularity %q: must be day or month", req.Granularity))
+	}
+	switch q.Operation {
+	case "", usage.OperationIngest, usage.OperationQuery, usage.OperationReindex:
+	default:
+		return nil, forge.BadRequest(fmt.Sprintf("invalid operation %q", req.Operation))
+	}
+
+	if req.CollectionID != "" {
+		colID, err := id.ParseCollectionID(req.CollectionID)
+		if err != nil {
+			return nil, forge.BadRequest(fmt.Sprintf("invalid collection ID: %v", err))
+		}
+		q.CollectionID = colID
+	}
+
+	var err error
+	if q.From, q.To, err = usageRange(req.From, req.To); err != nil {
+		return nil, forge.BadRequest(err.Error())
+	}
+
+	summaries, err := a.eng.QueryUsage(ctx.Context(), q)
+	if err != nil {
+		return nil, fmt.Errorf("query usage: %w", err)
+	}
+	if summaries == nil {
+		summaries = []*usage.Summary{}
+	}
+
+	return summaries, ctx.JSON(http.StatusOK, summaries)
+}
+
+// usageRange parses the from and to bounds of a usage query. Either may
+// be empty; when both are set, to must be after from.
+func usageRange(from, to string) (time.Time, time.Time, error) {
+	start, err := parseUsageTime(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+	}
+	end, err := parseUsageTime(to)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range: to %s is not after from %s", to, from)
+	}
+	return start, end, nil
+}
+
+// parseUsageTime accepts an RFC 3339 timestamp or a YYYY-MM-DD date.
+// An empty string yields the zero time.
+func parseUsageTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
@@ -36,7 +36,8 @@ func WithTemplate(t *Template) Option {
 	return func(a *Assembler) { a.template = t }
 }
 
-// WithTokenCounter sets a custom token counter.
+// WithTokenCounter sets the token counter, such as a *tokenizer.Encoding
+// for the target model. The default counts cl100k_base tokens.
 func WithTokenCounter(tc TokenCounter) Option {
 	return func(a *Assembler) { a.tokenCounter = tc }
 }
@@ -50,7 +51,7 @@ func WithMaxTokens(limit int) Option {
 func New(opts ...Option) *Assembler {
 	a := &Assembler{
 		template:     DefaultTemplate(),
-		tokenCounter: &SimpleTokenCounter{},
+		tokenCounter: defaultTokenCounter(),
 		maxTokens:    4096,
 	}
 	for _, opt := range opts {
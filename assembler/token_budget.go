@@ -1,5 +1,7 @@
 package assembler
 
+import "github.com/xraph/weave/tokenizer"
+
 // TokenCounter estimates token counts for text.
 type TokenCounter interface {
 	// CountTokens returns the estimated number of tokens in the text.
@@ -7,6 +9,7 @@ type TokenCounter interface {
 }
 
 // SimpleTokenCounter estimates tokens by dividing character count by 4.
+// It is the fallback when no tokenizer vocabulary is available.
 type SimpleTokenCounter struct{}
 
 // CountTokens returns an approximate token count (~4 chars per token).
@@ -14,6 +17,15 @@ func (c *SimpleTokenCounter) CountTokens(text string) int {
 	return len(text) / 4
 }
 
+// defaultTokenCounter counts cl100k_base tokens when its vocabulary is
+// embedded, and estimates them otherwise.
+func defaultTokenCounter() TokenCounter {
+	if enc, err := tokenizer.Get(tokenizer.Cl100kBase); err == nil {
+		return enc
+	}
+	return &SimpleTokenCounter{}
+}
+
 // BudgetManager tracks token consumption against a budget.
 type BudgetManager struct {
 	counter   TokenCounter
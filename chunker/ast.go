@@ -0,0 +1,166 @@
+package chunker
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Symbol kinds reported by language parsers.
+const (
+	SymbolPackage = "package"
+	SymbolImport  = "import"
+	SymbolFunc    = "func"
+	SymbolMethod  = "method"
+	SymbolType    = "type"
+	SymbolConst   = "const"
+	SymbolVar     = "var"
+)
+
+// Symbol is a top-level declaration found by a LanguageParser.
+type Symbol struct {
+	// Kind is the declaration kind, e.g. SymbolFunc.
+	Kind string
+	// Name is the declared name; grouped declarations join their names
+	// with ", ".
+	Name string
+	// Receiver is the receiver type of a method, e.g. "*Engine".
+	Receiver string
+	// Start and End are the byte offsets of the declaration, including
+	// its doc comment.
+	Start, End int
+	// Splits are byte offsets within the declaration where it may be cut
+	// when it does not fit in one chunk, such as statement boundaries.
+	Splits []int
+}
+
+// SourceFile is the parsed outline of a source file.
+type SourceFile struct {
+	// Language is the source language, e.g. "go".
+	Language string
+	// Package is the package or module name, if the language has one.
+	Package string
+	// Symbols are the top-level declarations in source order.
+	Symbols []Symbol
+}
+
+// LanguageParser extracts top-level declarations from source code.
+type LanguageParser interface {
+	// Parse returns the outline of src, or an error if src is not valid
+	// source in the parser's language.
+	Parse(src string) (*SourceFile, error)
+}
+
+// ASTChunker splits source code into one chunk per top-level declaration
+// using a LanguageParser. Declarations larger than the chunk size are cut
+// at the parser's split points. Each chunk records its language, package,
+// symbol, kind, receiver and line range in metadata.
+type ASTChunker struct {
+	// Parser outlines the source.
+	Parser LanguageParser
+	// Fallback chunks text the parser rejects (default CodeChunker).
+	Fallback Chunker
+}
+
+// NewGoChunker creates an ASTChunker for Go source.
+func NewGoChunker() *ASTChunker { return &ASTChunker{Parser: GoParser{}} }
+
+// Chunk splits source code at declaration boundaries.
+func (c *ASTChunker) Chunk(ctx context.Context, text string, opts *Options) ([]ChunkResult, error) {
+	chunkSize := 512
+	if opts != nil && opts.ChunkSize > 0 {
+		chunkSize = opts.ChunkSize
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	file, err := c.Parser.Parse(text)
+	if err != nil {
+		fallback := c.Fallback
+		if fallback == nil {
+			fallback = NewCodeChunker()
+		}
+		return fallback.Chunk(ctx, text, opts)
+	}
+
+	m := newMeasure(text, opts, chunkSize, 0)
+	lines := newLineIndex(text)
+	var results []ChunkResult
+	for _, sym := range file.Symbols {
+		s := trimSpan(text, span{sym.Start, sym.End})
+		if s.len() == 0 {
+			continue
+		}
+
+		spans := []span{s}
+		if !m.fits(s) {
+			spans = m.pack(splitAt(text, s, sym.Splits))
+		}
+
+		for _, sp := range spans {
+			res := m.result(sp, len(results))
+			res.Metadata = map[string]string{
+				MetaLanguage:   file.Language,
+				MetaSymbol:     sym.Name,
+				MetaSymbolKind: sym.Kind,
+				MetaStartLine:  strconv.Itoa(lines.line(sp.start)),
+				MetaEndLine:    strconv.Itoa(lines.line(sp.end - 1)),
+			}
+			if file.Package != "" {
+				res.Metadata[MetaPackage] = file.Package
+			}
+			if sym.Receiver != "" {
+				res.Metadata[MetaReceiver] = sym.Receiver
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+// splitAt cuts s at the given offsets. Pieces start at the beginning of
+// their first non-blank line, so indentation is kept, and blank pieces
+// are dropped.
+func splitAt(text string, s span, offsets []int) []span {
+	points := []int{s.start}
+	for _, o := range offsets {
+		if o > s.start && o < s.end {
+			points = append(points, o)
+		}
+	}
+	sort.Ints(points)
+	points = append(points, s.end)
+
+	var pieces []span
+	for i := 0; i < len(points)-1; i++ {
+		t := trimSpan(text, span{points[i], points[i+1]})
+		if t.len() == 0 {
+			continue
+		}
+		start := max(strings.LastIndexByte(text[:t.start], '\n')+1, points[i])
+		pieces = append(pieces, span{start, t.end})
+	}
+	return pieces
+}
+
+// lineIndex maps byte offsets to 1-based line numbers.
+type lineIndex []int
+
+func newLineIndex(text string) lineIndex {
+	idx := lineIndex{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			idx = append(idx, i+1)
+		}
+	}
+	return idx
+}
+
+// line returns the 1-based line containing offset.
+func (l lineIndex) line(offset int) int {
+	return sort.SearchInts(l, offset+1)
+}
@@ -18,6 +18,44 @@ type ChunkResult struct {
 	TokenCount int `json:"token_count"`
 	// Metadata holds chunker-specific metadata.
 	Metadata map[string]string `json:"metadata,omitempty"`
+	// Children are the smaller chunks a hierarchical chunker split this
+	// chunk into. A chunk with children is stored for context, and only
+	// its children are embedded.
+	Children []ChunkResult `json:"children,omitempty"`
+}
+
+// Metadata keys set by chunkers.
+const (
+	// MetaHeadingPath is the heading hierarchy a chunk belongs to, joined
+	// with " > ", e.g. "Install > Linux > Troubleshooting".
+	MetaHeadingPath = "heading_path"
+	// MetaEmbedPrefix is text prepended to the chunk content when it is
+	// embedded. The stored content is left unchanged.
+	MetaEmbedPrefix = "embed_prefix"
+	// MetaLanguage is the programming language of a code chunk.
+	MetaLanguage = "language"
+	// MetaPackage is the package or module a code chunk belongs to.
+	MetaPackage = "package"
+	// MetaSymbol is the name of the declaration a code chunk holds.
+	MetaSymbol = "symbol"
+	// MetaSymbolKind is the kind of declaration, e.g. "func" or "type".
+	MetaSymbolKind = "symbol_kind"
+	// MetaReceiver is the receiver type of a method.
+	MetaReceiver = "receiver"
+	// MetaStartLine and MetaEndLine are the 1-based source lines a chunk
+	// spans.
+	MetaStartLine = "start_line"
+	MetaEndLine   = "end_line"
+	// MetaRowStart and MetaRowEnd are the 1-based data rows, not counting
+	// the header, a table chunk spans.
+	MetaRowStart = "row_start"
+	MetaRowEnd   = "row_end"
+)
+
+// EmbedText returns the text to embed for a chunk: its content preceded by
+// any MetaEmbedPrefix in its metadata.
+func EmbedText(content string, metadata map[string]string) string {
+	return metadata[MetaEmbedPrefix] + content
 }
 
 // Options configures the chunking behaviour.
@@ -28,6 +66,20 @@ type Options struct {
 	ChunkOverlap int
 	// Strategy is the chunking strategy name (e.g. "recursive", "fixed").
 	Strategy string
+	// Language selects the sentence Segmenter, e.g. "en", "de", "ja" or
+	// "th" (default English rules).
+	Language string
+	// Tokenizer, when set, makes ChunkSize, ChunkOverlap and the reported
+	// TokenCount real token counts. Without it a token is approximated as
+	// four bytes.
+	Tokenizer TokenCounter
+}
+
+// TokenCounter counts the tokens in a text. It matches
+// assembler.TokenCounter and is implemented by *tokenizer.Encoding.
+type TokenCounter interface {
+	// CountTokens returns the number of tokens in the text.
+	CountTokens(text string) int
 }
 
 // Chunker splits text into chunks for embedding.
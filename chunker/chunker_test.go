@@ -0,0 +1,500 @@
+package chunker_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/embedder"
+)
+
+var samples = map[string]string{
+	"ascii":     strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40),
+	"cjk":       strings.Repeat("東京は日本の首都です。人口は約千四百万人です。", 30),
+	"emoji":     strings.Repeat("👨‍👩‍👧‍👦 family 🇯🇵 flag 👍🏽 thumbs! ", 30),
+	"combining": strings.Repeat("élève café naïve. ", 40),
+	"padded":    "\n\n  " + strings.Repeat("Line one.\nLine two!\n\n", 30) + "  \n",
+	"markdown":  "# Install\n\n## Linux\n\nRun the script.\n\n```sh\n# not a heading\ncurl -sSL x | sh\n```\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\nSetext\n------\n\nBody 日本語.\n",
+	"code":      "package main\n\nfunc a() {\n\treturn\n}\n\nfunc b() {\n\t// 日本語\n}\n\ntype T struct{}\n",
+}
+
+// randomText builds text from a mix of ASCII, whitespace, multi-byte and
+// combining runes so chunkers are exercised on awkward boundaries.
+func randomText(r *rand.Rand) string {
+	pieces := []string{"a", "Z", " ", "\n", "\n\n", ". ", "! ", "é", "é", "日", "本", "😀", "👍🏽", "🇫🇷", "\t", "func ", "\nfunc "}
+	var b strings.Builder
+	for n := r.IntN(600); n > 0; n-- {
+		b.WriteString(pieces[r.IntN(len(pieces))])
+	}
+	return b.String()
+}
+
+// topicEmbedder embeds text as counts of a few topic words, so sentence
+// windows about the same topic are close and a topic change is far.
+type topicEmbedder struct{}
+
+var topics = []string{"cat", "rocket", "bread"}
+
+func (topicEmbedder) Embed(_ context.Context, texts []string) ([]embedder.EmbedResult, error) {
+	out := make([]embedder.EmbedResult, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, len(topics)+1)
+		vec[len(topics)] = 0.01
+		for j, topic := range topics {
+			vec[j] = float32(strings.Count(text, topic))
+		}
+		out[i] = embedder.EmbedResult{Vector: vec}
+	}
+	return out, nil
+}
+
+func (topicEmbedder) Dimensions() int { return len(topics) + 1 }
+
+// wordCounter counts whitespace-separated words as tokens.
+type wordCounter struct{}
+
+func (wordCounter) CountTokens(text string) int { return len(strings.Fields(text)) }
+
+func graphemeBoundaries(text string) map[int]bool {
+	b := map[int]bool{0: true}
+	state := -1
+	offset := 0
+	for rest := text; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		offset += len(cluster)
+		b[offset] = true
+	}
+	return b
+}
+
+func TestChunkOffsetsIndexSource(t *testing.T) {
+	chunkers := map[string]chunker.Chunker{
+		"fixed":     chunker.NewFixedChunker(),
+		"sliding":   chunker.NewSlidingChunker(),
+		"recursive": chunker.NewRecursiveChunker(),
+		"semantic":  chunker.NewSemanticChunker(),
+		"embedding": chunker.NewEmbeddingSemanticChunker(topicEmbedder{}),
+		"markdown":  chunker.NewMarkdownChunker(),
+		"go":        chunker.NewGoChunker(),
+		"code":      chunker.NewCodeChunker(),
+	}
+	// Fixed and sliding windows never trim, so every cut must also land on
+	// a grapheme cluster boundary.
+	graphemeAligned := map[string]bool{"fixed": true, "sliding": true}
+
+	texts := make(map[string]string, len(samples)+50)
+	for name, text := range samples {
+		texts[name] = text
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 50 {
+		texts["random-"+string(rune('A'+i%26))+string(rune('a'+i/26))] = randomText(r)
+	}
+
+	optionSets := []*chunker.Options{
+		nil,
+		{ChunkSize: 8},
+		{ChunkSize: 8, ChunkOverlap: 2},
+		{ChunkSize: 3, ChunkOverlap: 5},
+		{ChunkSize: 1},
+		{ChunkSize: 8, Tokenizer: wordCounter{}},
+		{ChunkSize: 6, ChunkOverlap: 2, Tokenizer: wordCounter{}},
+		{ChunkSize: 8, Language: "ja"},
+		{ChunkSize: 8, Language: "th"},
+	}
+
+	for cname, c := range chunkers {
+		for tname, text := range texts {
+			bounds := graphemeBoundaries(text)
+			for _, opts := range optionSets {
+				results, err := c.Chunk(context.Background(), text, opts)
+				if err != nil {
+					t.Fatalf("%s/%s: %v", cname, tname, err)
+				}
+				if strings.TrimSpace(text) != "" && len(results) == 0 {
+					t.Errorf("%s/%s: no chunks for non-empty text", cname, tname)
+				}
+				for i, res := range results {
+					if res.Index != i {
+						t.Errorf("%s/%s: chunk %d has index %d", cname, tname, i, res.Index)
+					}
+					if res.StartOffset < 0 || res.EndOffset > len(text) || res.StartOffset >= res.EndOffset {
+						t.Fatalf("%s/%s: chunk %d has invalid range [%d:%d]", cname, tname, i, res.StartOffset, res.EndOffset)
+					}
+					if got := text[res.StartOffset:res.EndOffset]; got != res.Content {
+						t.Errorf("%s/%s: chunk %d: text[%d:%d] = %q, content = %q",
+							cname, tname, i, res.StartOffset, res.EndOffset, got, res.Content)
+					}
+					if !utf8.ValidString(res.Content) {
+						t.Errorf("%s/%s: chunk %d is not valid UTF-8: %q", cname, tname, i, res.Content)
+					}
+					if graphemeAligned[cname] && (!bounds[res.StartOffset] || !bounds[res.EndOffset]) {
+						t.Errorf("%s/%s: chunk %d [%d:%d] splits a grapheme cluster",
+							cname, tname, i, res.StartOffset, res.EndOffset)
+					}
+					if opts != nil && opts.Tokenizer != nil {
+						if want := opts.Tokenizer.CountTokens(res.Content); res.TokenCount != want {
+							t.Errorf("%s/%s: chunk %d reports %d tokens, want %d", cname, tname, i, res.TokenCount, want)
+						}
+						if graphemeAligned[cname] && res.TokenCount > opts.ChunkSize {
+							t.Errorf("%s/%s: chunk %d has %d tokens, limit %d", cname, tname, i, res.TokenCount, opts.ChunkSize)
+						}
+					}
+					if i > 0 && res.StartOffset < results[i-1].StartOffset {
+						t.Errorf("%s/%s: chunk %d starts before chunk %d", cname, tname, i, i-1)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSemanticChunkerBreaksAtTopicShift(t *testing.T) {
+	var b strings.Builder
+	for _, topic := range topics {
+		for i := range 6 {
+			fmt.Fprintf(&b, "The %s is sentence number %d about the %s. ", topic, i, topic)
+		}
+	}
+	text := b.String()
+
+	c := &chunker.SemanticChunker{Embedder: topicEmbedder{}, BreakpointPercentile: 80, MinChunkSize: 1}
+	results, err := c.Chunk(context.Background(), text, &chunker.Options{ChunkSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(topics) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(results), len(topics), results)
+	}
+	for i, res := range results {
+		for j, topic := range topics {
+			if has := strings.Contains(res.Content, topic); has != (i == j) {
+				t.Errorf("chunk %d mentions %q = %v: %q", i, topic, has, res.Content)
+			}
+		}
+	}
+}
+
+func TestFixedAndSlidingDefaultOverlap(t *testing.T) {
+	text := samples["ascii"]
+	opts := &chunker.Options{ChunkSize: 100}
+	for name, tt := range map[string]struct {
+		c       chunker.Chunker
+		overlap bool
+	}{
+		"fixed":   {chunker.NewFixedChunker(), false},
+		"sliding": {chunker.NewSlidingChunker(), true},
+	} {
+		results, err := tt.c.Chunk(context.Background(), text, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) < 2 {
+			t.Fatalf("%s: %d chunks", name, len(results))
+		}
+		for i := 1; i < len(results); i++ {
+			prev, cur := results[i-1], results[i]
+			if overlaps := cur.StartOffset < prev.EndOffset; overlaps != tt.overlap {
+				t.Errorf("%s: chunk %d starts at %d, previous ends at %d", name, i, cur.StartOffset, prev.EndOffset)
+			}
+		}
+	}
+}
+
+func TestRecursiveChunkerSplitSentences(t *testing.T) {
+	text := strings.Repeat("Sentences keep their words together. ", 12)
+	opts := &chunker.Options{ChunkSize: 15}
+
+	for _, split := range []bool{true, false} {
+		c := &chunker.RecursiveChunker{Separators: []string{"\n\n", "\n", " "}, SplitSentences: split}
+		results, err := c.Chunk(context.Background(), text, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		whole := true
+		for _, res := range results {
+			whole = whole && strings.HasSuffix(res.Content, ".")
+		}
+		if whole != split {
+			t.Errorf("SplitSentences = %v: chunks end at sentences = %v: %+v", split, whole, results)
+		}
+	}
+}
+
+func TestSegmenterSentences(t *testing.T) {
+	tests := []struct {
+		lang, text string
+		want       []string
+	}{
+		{"en", "Dr. Smith paid $3.50 for it, e.g. a coffee. Then he left!", []string{
+			"Dr. Smith paid $3.50 for it, e.g. a coffee.", "Then he left!",
+		}},
+		{"en", "J. R. R. Tolkien wrote it. \"Really?\" she asked.", []string{
+			"J. R. R. Tolkien wrote it.", "\"Really?\"", "she asked.",
+		}},
+		{"de", "Das gilt z.B. für Äpfel. Vgl. Abschnitt 2.", []string{
+			"Das gilt z.B. für Äpfel.", "Vgl. Abschnitt 2.",
+		}},
+		{"ja", "東京は首都です。人口は多い！「本当？」と聞いた。", []string{
+			"東京は首都です。", "人口は多い！", "「本当？」と聞いた。",
+		}},
+		{"th", "ฉันชอบกินข้าว วันนี้อากาศดี", []string{"ฉันชอบกินข้าว", "วันนี้อากาศดี"}},
+	}
+	for _, tt := range tests {
+		got := chunker.NewSegmenter(tt.lang).Sentences(tt.text)
+		if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("%s: Sentences(%q) = %q, want %q", tt.lang, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownChunkerHeadingPaths(t *testing.T) {
+	text := `Intro text.
+
+# Install
+
+## Linux
+
+Use the package manager.
+
+` + "```sh\n# comment, not a heading\napt install weave\n```" + `
+
+### Troubleshooting
+
+| Error | Fix |
+|-------|-----|
+| E1    | Retry |
+
+## macOS
+Use brew.
+
+Usage
+=====
+
+Run it.
+`
+	c := &chunker.MarkdownChunker{PrependBreadcrumb: true}
+	results, err := c.Chunk(context.Background(), text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ path, prefix string }{
+		{"", "Intro text."},
+		{"Install > Linux", "# Install\n\n## Linux"},
+		{"Install > Linux > Troubleshooting", "### Troubleshooting"},
+		{"Install > macOS", "## macOS"},
+		{"Usage", "Usage\n====="},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		res := results[i]
+		if got := res.Metadata[chunker.MetaHeadingPath]; got != w.path {
+			t.Errorf("chunk %d heading path = %q, want %q", i, got, w.path)
+		}
+		if !strings.HasPrefix(res.Content, w.prefix) {
+			t.Errorf("chunk %d content = %q, want prefix %q", i, res.Content, w.prefix)
+		}
+		if w.path != "" && res.Metadata[chunker.MetaEmbedPrefix] != w.path+"\n\n" {
+			t.Errorf("chunk %d embed prefix = %q", i, res.Metadata[chunker.MetaEmbedPrefix])
+		}
+	}
+	if !strings.Contains(results[1].Content, "apt install weave\n```") {
+		t.Errorf("code block split: %q", results[1].Content)
+	}
+
+	// A small chunk size splits sections by line but keeps table rows whole.
+	results, err = c.Chunk(context.Background(), text, &chunker.Options{ChunkSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range results {
+		if strings.Contains(res.Content, "| E1") && !strings.HasSuffix(res.Content, "|") {
+			t.Errorf("table row split: %q", res.Content)
+		}
+	}
+}
+
+func TestHierarchicalChunkerNestsChildren(t *testing.T) {
+	text := samples["ascii"] + samples["cjk"]
+	opts := &chunker.Options{ChunkSize: 16, ChunkOverlap: 4}
+	parents, err := chunker.NewHierarchicalChunker().Chunk(context.Background(), text, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) < 2 {
+		t.Fatalf("got %d parents, want several", len(parents))
+	}
+
+	index := 0
+	for _, p := range parents {
+		if p.Index != index {
+			t.Errorf("parent index = %d, want %d", p.Index, index)
+		}
+		index++
+		if text[p.StartOffset:p.EndOffset] != p.Content {
+			t.Fatalf("parent %d offsets do not index the source", p.Index)
+		}
+		if len(p.Children) == 0 {
+			t.Errorf("parent %d has no children", p.Index)
+		}
+		for _, c := range p.Children {
+			if c.Index != index {
+				t.Errorf("child index = %d, want %d", c.Index, index)
+			}
+			index++
+			if text[c.StartOffset:c.EndOffset] != c.Content {
+				t.Fatalf("child %d offsets do not index the source", c.Index)
+			}
+			if c.StartOffset < p.StartOffset || c.EndOffset > p.EndOffset {
+				t.Errorf("child %d [%d,%d) outside parent [%d,%d)", c.Index, c.StartOffset, c.EndOffset, p.StartOffset, p.EndOffset)
+			}
+			if c.TokenCount > 16 {
+				t.Errorf("child %d has %d tokens, limit 16", c.Index, c.TokenCount)
+			}
+		}
+	}
+}
+
+func TestGoChunkerSymbols(t *testing.T) {
+	text := `// Package demo is a demo.
+package demo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Greeter greets.
+type Greeter struct{ name string }
+
+// Greet returns a greeting.
+func (g *Greeter) Greet() string {
+	return fmt.Sprintf("hello %s", strings.ToUpper(g.name))
+}
+
+func long(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	switch {
+	case total > 10:
+		total--
+	default:
+		total++
+	}
+	return total
+}
+`
+	c := chunker.NewGoChunker()
+	results, err := c.Chunk(context.Background(), text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ symbol, kind, receiver, startLine string }{
+		{"demo", "package", "", "1"},
+		{"fmt, strings", "import", "", "4"},
+		{"Greeter", "type", "", "9"},
+		{"Greet", "method", "*Greeter", "12"},
+		{"long", "func", "", "17"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		md := results[i].Metadata
+		if md[chunker.MetaSymbol] != w.symbol || md[chunker.MetaSymbolKind] != w.kind ||
+			md[chunker.MetaReceiver] != w.receiver || md[chunker.MetaStartLine] != w.startLine {
+			t.Errorf("chunk %d metadata = %v, want %+v", i, md, w)
+		}
+		if md[chunker.MetaPackage] != "demo" || md[chunker.MetaLanguage] != "go" {
+			t.Errorf("chunk %d package/language = %v", i, md)
+		}
+	}
+	if !strings.HasPrefix(results[3].Content, "// Greet returns a greeting.") {
+		t.Errorf("method chunk lost its doc comment: %q", results[3].Content)
+	}
+
+	// An oversized function is split between statements, never mid-line.
+	results, err = c.Chunk(context.Background(), text, &chunker.Options{ChunkSize: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := 0
+	for _, res := range results {
+		if res.Metadata[chunker.MetaSymbol] != "long" {
+			continue
+		}
+		parts++
+		if res.StartOffset > 0 && text[res.StartOffset-1] != '\n' {
+			t.Errorf("part starts mid-line: %q", res.Content)
+		}
+	}
+	if parts < 2 {
+		t.Errorf("long was not split: %d parts", parts)
+	}
+
+	// Text that does not parse falls back to the CodeChunker.
+	results, err = c.Chunk(context.Background(), "def f():\n    return 1\n", nil)
+	if err != nil || len(results) != 1 || results[0].Metadata != nil {
+		t.Errorf("fallback = %+v, %v", results, err)
+	}
+}
+
+func TestTableChunkerRepeatsHeader(t *testing.T) {
+	text := "region | product | units\neu | bolt | 10\neu | nut | 20\neu | gear | 5\nus | bolt | 7\nus | nut | 3\n"
+	c := &chunker.TableChunker{RowsPerChunk: 2, MetadataColumns: []string{"region"}}
+	results, err := c.Chunk(context.Background(), text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ rowStart, rowEnd, region string }{
+		{"1", "2", "eu"}, {"3", "3", "eu"}, {"4", "5", "us"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		res := results[i]
+		if !strings.HasPrefix(res.Content, "region | product | units\n") {
+			t.Errorf("chunk %d lacks header: %q", i, res.Content)
+		}
+		if got := res.Metadata[chunker.MetaRowStart] + "-" + res.Metadata[chunker.MetaRowEnd]; got != w.rowStart+"-"+w.rowEnd {
+			t.Errorf("chunk %d rows = %s, want %s-%s", i, got, w.rowStart, w.rowEnd)
+		}
+		if res.Metadata["region"] != w.region {
+			t.Errorf("chunk %d region = %q, want %q", i, res.Metadata["region"], w.region)
+		}
+		body := strings.TrimPrefix(res.Content, "region | product | units\n")
+		if text[res.StartOffset:res.EndOffset] != body {
+			t.Errorf("chunk %d offsets = %q, want rows %q", i, text[res.StartOffset:res.EndOffset], body)
+		}
+	}
+
+	// Quoted CSV rendered as records.
+	csvText := "name,notes\n\"Smith, J\",\"line one\nline two\"\nDoe,\n"
+	c = &chunker.TableChunker{Separator: ",", Format: chunker.TableFormatRecords}
+	results, err = c.Chunk(context.Background(), csvText, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(results))
+	}
+	wantContent := "name: Smith, J\nnotes: line one\nline two\n\nname: Doe"
+	if results[0].Content != wantContent {
+		t.Errorf("content = %q, want %q", results[0].Content, wantContent)
+	}
+}
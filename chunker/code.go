@@ -18,10 +18,8 @@ func (c *CodeChunker) Chunk(_ context.Context, text string, opts *Options) ([]Ch
 		chunkSize = opts.ChunkSize
 	}
 
-	charSize := chunkSize * 4
-
-	text = strings.TrimSpace(text)
-	if text == "" {
+	s := trimSpan(text, span{0, len(text)})
+	if s.len() == 0 {
 		return nil, nil
 	}
 
@@ -32,68 +30,58 @@ func (c *CodeChunker) Chunk(_ context.Context, text string, opts *Options) ([]Ch
 		"\n\n", "\n",
 	}
 
-	blocks := splitByAny(text, separators)
+	blocks := splitByAny(text, s, separators)
+	m := newMeasure(text, opts, chunkSize, 0)
 
 	var results []ChunkResult
-	var current strings.Builder
-	currentStart := 0
-	chunkIdx := 0
+	var current span
+	hasCurrent := false
 
 	for _, block := range blocks {
-		block = strings.TrimRight(block, " \t")
-		if block == "" {
+		block.end = block.start + len(strings.TrimRight(text[block.start:block.end], " \t"))
+		if block.len() == 0 {
 			continue
 		}
 
-		if current.Len()+len(block) > charSize && current.Len() > 0 {
-			content := current.String()
-			results = append(results, ChunkResult{
-				Content:     content,
-				Index:       chunkIdx,
-				StartOffset: currentStart,
-				EndOffset:   currentStart + len(content),
-				TokenCount:  len(content) / 4,
-			})
-			chunkIdx++
-			currentStart += len(content)
-			current.Reset()
+		if hasCurrent && !m.fits(span{current.start, block.end}) {
+			results = append(results, m.result(current, len(results)))
+			hasCurrent = false
 		}
 
-		current.WriteString(block)
+		if !hasCurrent {
+			current = block
+			hasCurrent = true
+		}
+		current.end = block.end
 	}
 
-	if current.Len() > 0 {
-		content := current.String()
-		results = append(results, ChunkResult{
-			Content:     content,
-			Index:       chunkIdx,
-			StartOffset: currentStart,
-			EndOffset:   currentStart + len(content),
-			TokenCount:  len(content) / 4,
-		})
+	if hasCurrent {
+		results = append(results, m.result(current, len(results)))
 	}
 
 	return results, nil
 }
 
-// splitByAny splits text by the first matching separator from the list.
-func splitByAny(text string, separators []string) []string {
+// splitByAny splits s by the first matching separator from the list. Each
+// block after the first starts at its separator, which is kept as a prefix.
+func splitByAny(text string, s span, separators []string) []span {
 	for _, sep := range separators {
-		if strings.Contains(text, sep) {
-			parts := strings.Split(text, sep)
-			var result []string
-			for i, part := range parts {
-				if i > 0 {
-					part = sep + part // Preserve the separator as prefix.
-				}
-				if strings.TrimSpace(part) != "" {
-					result = append(result, part)
-				}
+		parts := splitSpan(text, s, sep)
+		if len(parts) < 2 {
+			continue
+		}
+		var blocks []span
+		for i, part := range parts {
+			if i > 0 {
+				part.start -= len(sep) // Preserve the separator as prefix.
 			}
-			if len(result) > 1 {
-				return result
+			if strings.TrimSpace(text[part.start:part.end]) != "" {
+				blocks = append(blocks, part)
 			}
 		}
+		if len(blocks) > 1 {
+			return blocks
+		}
 	}
-	return []string{text}
+	return []span{s}
 }
@@ -1,11 +1,10 @@
 package chunker
 
-import (
-	"context"
-	"strings"
-)
+import "context"
 
-// FixedChunker splits text into fixed-size chunks at exact character boundaries.
+// FixedChunker splits text into consecutive fixed-size chunks, cutting only
+// at grapheme cluster boundaries so multi-byte characters are never split.
+// Chunks do not overlap unless Options.ChunkOverlap is set.
 type FixedChunker struct{}
 
 // NewFixedChunker creates a new FixedChunker.
@@ -13,51 +12,5 @@ func NewFixedChunker() *FixedChunker { return &FixedChunker{} }
 
 // Chunk splits text into fixed-size chunks.
 func (c *FixedChunker) Chunk(_ context.Context, text string, opts *Options) ([]ChunkResult, error) {
-	chunkSize := 512
-	overlap := 0
-	if opts != nil {
-		if opts.ChunkSize > 0 {
-			chunkSize = opts.ChunkSize
-		}
-		if opts.ChunkOverlap > 0 {
-			overlap = opts.ChunkOverlap
-		}
-	}
-
-	// Use character count as a proxy for tokens (rough approximation).
-	charSize := chunkSize * 4 // ~4 chars per token
-	charOverlap := overlap * 4
-
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil, nil
-	}
-
-	var results []ChunkResult
-	start := 0
-	idx := 0
-
-	for start < len(text) {
-		end := start + charSize
-		if end > len(text) {
-			end = len(text)
-		}
-
-		content := text[start:end]
-		results = append(results, ChunkResult{
-			Content:     content,
-			Index:       idx,
-			StartOffset: start,
-			EndOffset:   end,
-			TokenCount:  len(content) / 4,
-		})
-
-		idx++
-		start = end - charOverlap
-		if start >= end {
-			break
-		}
-	}
-
-	return results, nil
+	return windowChunks(text, opts, 0), nil
 }
@@ -0,0 +1,159 @@
+package chunker
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// GoParser outlines Go source files with go/parser.
+type GoParser struct{}
+
+// Parse returns the package clause, imports and top-level declarations of
+// a Go source file. Functions may be split between statements at any
+// nesting depth, grouped declarations between their specs.
+func (GoParser) Parse(src string) (*SourceFile, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	tf := fset.File(f.Pos())
+	offset := func(p token.Pos) int { return tf.Offset(p) }
+	// lineStart moves an offset back to the start of its line.
+	lineStart := func(p token.Pos) int {
+		o := offset(p)
+		return strings.LastIndexByte(src[:o], '\n') + 1
+	}
+	start := func(doc *ast.CommentGroup, p token.Pos) int {
+		if doc != nil {
+			return lineStart(doc.Pos())
+		}
+		return lineStart(p)
+	}
+
+	file := &SourceFile{Language: "go", Package: f.Name.Name}
+	// The package symbol starts at the top of the file so build tags and
+	// license headers are kept.
+	file.Symbols = append(file.Symbols, Symbol{
+		Kind:  SymbolPackage,
+		Name:  f.Name.Name,
+		Start: 0,
+		End:   offset(f.Name.End()),
+	})
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			sym := Symbol{
+				Kind:  SymbolFunc,
+				Name:  d.Name.Name,
+				Start: start(d.Doc, d.Pos()),
+				End:   offset(d.End()),
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				sym.Kind = SymbolMethod
+				sym.Receiver = receiverType(d.Recv.List[0].Type)
+			}
+			if d.Body != nil {
+				sym.Splits = statementStarts(d.Body, lineStart)
+			}
+			file.Symbols = append(file.Symbols, sym)
+
+		case *ast.GenDecl:
+			sym := Symbol{
+				Kind:  d.Tok.String(),
+				Start: start(d.Doc, d.Pos()),
+				End:   offset(d.End()),
+			}
+			var names []string
+			for i, spec := range d.Specs {
+				if i > 0 {
+					sym.Splits = append(sym.Splits, start(specDoc(spec), spec.Pos()))
+				}
+				names = append(names, specNames(spec)...)
+			}
+			sym.Name = strings.Join(names, ", ")
+			file.Symbols = append(file.Symbols, sym)
+		}
+	}
+
+	return file, nil
+}
+
+// statementStarts returns the line starts of every statement in body,
+// including statements nested in blocks and case clauses.
+func statementStarts(body *ast.BlockStmt, lineStart func(token.Pos) int) []int {
+	var starts []int
+	add := func(stmts []ast.Stmt) {
+		for _, s := range stmts {
+			starts = append(starts, lineStart(s.Pos()))
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			add(n.List)
+		case *ast.CaseClause:
+			starts = append(starts, lineStart(n.Pos()))
+			add(n.Body)
+		case *ast.CommClause:
+			starts = append(starts, lineStart(n.Pos()))
+			add(n.Body)
+		}
+		return true
+	})
+	sort.Ints(starts)
+	return starts
+}
+
+// receiverType renders a method receiver type such as "*Engine" or
+// "List[T]".
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverType(t.X) + "[" + receiverType(t.Index) + "]"
+	case *ast.IndexListExpr:
+		params := make([]string, len(t.Indices))
+		for i, idx := range t.Indices {
+			params[i] = receiverType(idx)
+		}
+		return receiverType(t.X) + "[" + strings.Join(params, ", ") + "]"
+	default:
+		return ""
+	}
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Doc
+	case *ast.ValueSpec:
+		return s.Doc
+	case *ast.ImportSpec:
+		return s.Doc
+	}
+	return nil
+}
+
+func specNames(spec ast.Spec) []string {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return []string{s.Name.Name}
+	case *ast.ValueSpec:
+		names := make([]string, len(s.Names))
+		for i, n := range s.Names {
+			names[i] = n.Name
+		}
+		return names
+	case *ast.ImportSpec:
+		return []string{strings.Trim(s.Path.Value, "\"`")}
+	}
+	return nil
+}
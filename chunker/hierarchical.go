@@ -0,0 +1,77 @@
+package chunker
+
+import "context"
+
+// HierarchicalChunker splits text into large parent chunks and each parent
+// into small child chunks. Children are embedded and matched precisely at
+// query time, then retriever.ParentRetriever or engine.WithParents swaps
+// them for their parents so the LLM gets the surrounding context.
+//
+// Options.ChunkSize and ChunkOverlap size the children. Indexes run in
+// document order across both levels: each parent is followed by its
+// children.
+type HierarchicalChunker struct {
+	// Parent splits the text into parents (default RecursiveChunker).
+	Parent Chunker
+	// Child splits each parent into children (default RecursiveChunker).
+	Child Chunker
+	// ParentSize is the parent chunk size in tokens (default four times
+	// the child size).
+	ParentSize int
+}
+
+// NewHierarchicalChunker creates a HierarchicalChunker using recursive
+// splitting at both levels.
+func NewHierarchicalChunker() *HierarchicalChunker { return &HierarchicalChunker{} }
+
+// Chunk splits text into parents with nested children.
+func (c *HierarchicalChunker) Chunk(ctx context.Context, text string, opts *Options) ([]ChunkResult, error) {
+	childOpts := Options{ChunkSize: 512}
+	if opts != nil {
+		childOpts = *opts
+		if childOpts.ChunkSize <= 0 {
+			childOpts.ChunkSize = 512
+		}
+	}
+	parentOpts := childOpts
+	parentOpts.ChunkSize = c.ParentSize
+	if parentOpts.ChunkSize <= 0 {
+		parentOpts.ChunkSize = 4 * childOpts.ChunkSize
+	}
+	parentOpts.ChunkOverlap = 0
+
+	parentChunker, childChunker := c.Parent, c.Child
+	if parentChunker == nil {
+		parentChunker = NewRecursiveChunker()
+	}
+	if childChunker == nil {
+		childChunker = NewRecursiveChunker()
+	}
+
+	parents, err := parentChunker.Chunk(ctx, text, &parentOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	index := 0
+	for i := range parents {
+		p := &parents[i]
+		children, err := childChunker.Chunk(ctx, p.Content, &childOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Index = index
+		index++
+		// Child offsets are relative to the parent; shift them so they
+		// index the original text.
+		for j := range children {
+			children[j].Index = index
+			children[j].StartOffset += p.StartOffset
+			children[j].EndOffset += p.StartOffset
+			index++
+		}
+		p.Children = children
+	}
+	return parents, nil
+}
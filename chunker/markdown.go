@@ -0,0 +1,272 @@
+package chunker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// MarkdownChunker splits Markdown along its heading hierarchy. Each section
+// is chunked on its own, code blocks and tables are kept whole unless they
+// exceed the chunk size, and every chunk records its heading path under
+// MetaHeadingPath. Feed it raw Markdown rather than the output of
+// loader.MarkdownLoader, which strips the structure.
+type MarkdownChunker struct {
+	// PrependBreadcrumb sets MetaEmbedPrefix so the heading path is
+	// embedded in front of each chunk, e.g. "Install > Linux\n\n...".
+	PrependBreadcrumb bool
+}
+
+// NewMarkdownChunker creates a new MarkdownChunker.
+func NewMarkdownChunker() *MarkdownChunker { return &MarkdownChunker{} }
+
+var (
+	reMDHeading = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	reMDSetext  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	reMDFence   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// mdBlock is a paragraph, heading, code block or table.
+type mdBlock struct {
+	span
+	// atomic blocks are code blocks and tables, split by line only when
+	// they do not fit in a chunk.
+	atomic bool
+}
+
+// mdHeading is an open heading on the breadcrumb stack.
+type mdHeading struct {
+	level int
+	title string
+}
+
+// mdSection is a heading and the blocks up to the next heading.
+type mdSection struct {
+	span
+	level  int
+	path   []string
+	blocks []mdBlock
+}
+
+// Chunk splits Markdown into heading-scoped chunks.
+func (c *MarkdownChunker) Chunk(_ context.Context, text string, opts *Options) ([]ChunkResult, error) {
+	chunkSize := 512
+	if opts != nil && opts.ChunkSize > 0 {
+		chunkSize = opts.ChunkSize
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	var language string
+	if opts != nil {
+		language = opts.Language
+	}
+	m := newMeasure(text, opts, chunkSize, 0)
+	seg := NewSegmenter(language)
+	var results []ChunkResult
+	for _, sec := range parseMarkdown(text) {
+		var meta map[string]string
+		if len(sec.path) > 0 {
+			path := strings.Join(sec.path, " > ")
+			meta = map[string]string{MetaHeadingPath: path}
+			if c.PrependBreadcrumb {
+				meta[MetaEmbedPrefix] = path + "\n\n"
+			}
+		}
+
+		for _, sp := range c.chunkSection(m, seg, sec) {
+			res := m.result(sp, len(results))
+			if meta != nil {
+				res.Metadata = make(map[string]string, len(meta))
+				for k, v := range meta {
+					res.Metadata[k] = v
+				}
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+// chunkSection packs a section's blocks into chunks. Blocks that do not fit
+// on their own are split into lines (code, tables) or sentences (prose).
+func (c *MarkdownChunker) chunkSection(m *measure, seg *Segmenter, sec mdSection) []span {
+	if m.fits(sec.span) {
+		return []span{sec.span}
+	}
+
+	var parts []span
+	for _, b := range sec.blocks {
+		switch {
+		case m.fits(b.span):
+			parts = append(parts, b.span)
+		case b.atomic:
+			parts = append(parts, lineSpans(m.text, b.span)...)
+		default:
+			parts = append(parts, seg.spans(m.text, b.span)...)
+		}
+	}
+	return m.pack(parts)
+}
+
+// parseMarkdown splits text into sections at ATX and setext headings,
+// ignoring heading-like lines inside fenced code blocks.
+func parseMarkdown(text string) []mdSection {
+	var (
+		sections []mdSection
+		current  = mdSection{span: span{0, 0}}
+		stack    []mdHeading // open headings, for the breadcrumb path
+		block    *mdBlock    // open paragraph or table
+		isTable  bool
+		paraLine int // lines in the open paragraph
+		fence    string
+	)
+
+	closeBlock := func() {
+		if block != nil {
+			if b := trimSpan(text, block.span); b.len() > 0 {
+				current.blocks = append(current.blocks, mdBlock{span: b, atomic: block.atomic})
+			}
+			block = nil
+		}
+	}
+	startSection := func(start, level int, title string) {
+		current.end = start
+		if s := trimSpan(text, current.span); s.len() > 0 {
+			current.span = s
+			sections = append(sections, current)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, mdHeading{level: level, title: title})
+		path := make([]string, len(stack))
+		for i, h := range stack {
+			path[i] = h.title
+		}
+
+		current = mdSection{span: span{start, start}, level: level, path: path}
+	}
+
+	for start := 0; start < len(text); {
+		end := strings.IndexByte(text[start:], '\n')
+		next := len(text)
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += start
+			next = end + 1
+		}
+		line := strings.TrimRight(text[start:end], "\r")
+
+		switch {
+		case fence != "":
+			block.end = end
+			if strings.HasPrefix(strings.TrimSpace(line), fence) && strings.Trim(strings.TrimSpace(line), fence[:1]) == "" {
+				fence = ""
+				closeBlock()
+			}
+
+		case reMDFence.MatchString(line):
+			closeBlock()
+			fence = reMDFence.FindStringSubmatch(line)[1]
+			block = &mdBlock{span: span{start, end}, atomic: true}
+
+		case reMDHeading.MatchString(line):
+			closeBlock()
+			sm := reMDHeading.FindStringSubmatch(line)
+			startSection(start, len(sm[1]), strings.TrimSpace(sm[2]))
+			current.blocks = append(current.blocks, mdBlock{span: trimSpan(text, span{start, end})})
+
+		case block != nil && !isTable && paraLine == 1 && reMDSetext.MatchString(line):
+			// A single paragraph line underlined with = or - is a heading.
+			level := 2
+			if strings.Contains(line, "=") {
+				level = 1
+			}
+			title := strings.TrimSpace(text[block.start:block.end])
+			headStart := block.start
+			block = nil
+			startSection(headStart, level, title)
+			current.blocks = append(current.blocks, mdBlock{span: span{headStart, end}})
+
+		case strings.TrimSpace(line) == "":
+			closeBlock()
+
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			if block == nil || !isTable {
+				closeBlock()
+				block = &mdBlock{span: span{start, end}, atomic: true}
+				isTable = true
+			}
+			block.end = end
+
+		default:
+			if block != nil && isTable {
+				closeBlock()
+			}
+			if block == nil {
+				block = &mdBlock{span: span{start, end}}
+				isTable = false
+				paraLine = 0
+			}
+			block.end = end
+			paraLine++
+		}
+
+		start = next
+	}
+	closeBlock()
+	current.end = len(text)
+	if s := trimSpan(text, current.span); s.len() > 0 {
+		current.span = s
+		sections = append(sections, current)
+	}
+
+	return mergeEmptySections(sections)
+}
+
+// mergeEmptySections folds a heading with no body into the subsection that
+// follows it, so "# Install" directly above "## Linux" does not become a
+// chunk of its own.
+func mergeEmptySections(sections []mdSection) []mdSection {
+	out := sections[:0]
+	var pending *mdSection
+	for i := range sections {
+		sec := sections[i]
+		empty := sec.level > 0 && len(sec.blocks) == 1
+		if pending != nil {
+			sec.start = pending.start
+			sec.blocks = append(pending.blocks, sec.blocks...)
+			pending = nil
+		}
+		if empty && i+1 < len(sections) && sections[i+1].level > sec.level {
+			pending = &sec
+			continue
+		}
+		out = append(out, sec)
+	}
+	return out
+}
+
+// lineSpans returns the non-blank lines within s.
+func lineSpans(text string, s span) []span {
+	var lines []span
+	for start := s.start; start < s.end; {
+		end := strings.IndexByte(text[start:s.end], '\n')
+		if end < 0 {
+			end = s.end
+		} else {
+			end += start
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			lines = append(lines, span{start, end})
+		}
+		start = end + 1
+	}
+	return lines
+}
@@ -5,18 +5,51 @@ import (
 	"strings"
 )
 
-// RecursiveChunker splits text using hierarchical separators, trying
-// the largest separator first and falling back to smaller ones.
+// RecursiveChunker splits text using hierarchical separators, trying the
+// largest separator first and falling back to smaller ones.
 type RecursiveChunker struct {
 	// Separators in priority order (largest to smallest).
 	Separators []string
+
+	// SplitSentences splits parts that are still too large after the
+	// line-break separators at the sentence boundaries found by the
+	// Segmenter for Options.Language, before trying the remaining
+	// separators.
+	SplitSentences bool
 }
 
-// NewRecursiveChunker creates a new RecursiveChunker with default separators.
+// NewRecursiveChunker creates a new RecursiveChunker with default separators
+// and sentence splitting.
 func NewRecursiveChunker() *RecursiveChunker {
 	return &RecursiveChunker{
-		Separators: []string{"\n\n", "\n", ". ", " "},
+		Separators:     []string{"\n\n", "\n", " "},
+		SplitSentences: true,
+	}
+}
+
+// separator is one level of the split hierarchy: either a literal
+// separator or a split at sentence boundaries.
+type separator struct {
+	text      string
+	sentences bool
+}
+
+// levels returns the split hierarchy, with the sentence level placed after
+// the separators that contain a line break.
+func (c *RecursiveChunker) levels() []separator {
+	levels := make([]separator, 0, len(c.Separators)+1)
+	pending := c.SplitSentences
+	for _, sep := range c.Separators {
+		if pending && !strings.Contains(sep, "\n") {
+			levels = append(levels, separator{sentences: true})
+			pending = false
+		}
+		levels = append(levels, separator{text: sep})
+	}
+	if pending {
+		levels = append(levels, separator{sentences: true})
 	}
+	return levels
 }
 
 // Chunk splits text recursively using hierarchical separators.
@@ -32,115 +65,104 @@ func (c *RecursiveChunker) Chunk(_ context.Context, text string, opts *Options)
 		}
 	}
 
-	charSize := chunkSize * 4
-	charOverlap := overlap * 4
-
-	text = strings.TrimSpace(text)
-	if text == "" {
+	s := trimSpan(text, span{0, len(text)})
+	if s.len() == 0 {
 		return nil, nil
 	}
 
-	chunks := c.splitRecursive(text, c.Separators, charSize, charOverlap)
+	var language string
+	if opts != nil {
+		language = opts.Language
+	}
+	m := newMeasure(text, opts, chunkSize, overlap)
+	spans := c.splitRecursive(m, NewSegmenter(language), s, c.levels())
 
-	results := make([]ChunkResult, len(chunks))
-	offset := 0
-	for i, ch := range chunks {
-		start := strings.Index(text[offset:], ch)
-		if start == -1 {
-			start = 0
-		} else {
-			start += offset
-		}
-		end := start + len(ch)
-
-		results[i] = ChunkResult{
-			Content:     ch,
-			Index:       i,
-			StartOffset: start,
-			EndOffset:   end,
-			TokenCount:  len(ch) / 4,
-		}
-		offset = start
+	results := make([]ChunkResult, len(spans))
+	for i, sp := range spans {
+		results[i] = m.result(sp, i)
 	}
 
 	return results, nil
 }
 
-func (c *RecursiveChunker) splitRecursive(text string, separators []string, chunkSize, overlap int) []string {
-	if len(text) <= chunkSize {
-		return []string{text}
+// splitRecursive splits s at the first separator, merging adjacent parts
+// while they fit in the chunk limit. Merged chunks span the original text
+// between their first and last part, so separators are kept verbatim. Parts
+// that are still too large are split again with the next separator.
+func (c *RecursiveChunker) splitRecursive(m *measure, seg *Segmenter, s span, separators []separator) []span {
+	if m.fits(s) {
+		return []span{s}
 	}
 
 	if len(separators) == 0 {
 		// Last resort: split at chunkSize boundaries.
-		return c.splitAtSize(text, chunkSize, overlap)
+		return m.windows(s)
 	}
 
-	sep := separators[0]
-	parts := strings.Split(text, sep)
+	var chunks []span
+	flush := func(cur span) {
+		if !m.fits(cur) {
+			// Recursively split with next separator.
+			chunks = append(chunks, c.splitRecursive(m, seg, cur, separators[1:])...)
+		} else {
+			chunks = append(chunks, cur)
+		}
+	}
 
-	var chunks []string
-	var current strings.Builder
+	var parts []span
+	if separators[0].sentences {
+		parts = seg.spans(m.text, s)
+	} else {
+		parts = splitSpan(m.text, s, separators[0].text)
+	}
 
+	var current span
+	hasCurrent := false
 	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
+		part = trimSpan(m.text, part)
+		if part.len() == 0 {
 			continue
 		}
 
-		if current.Len()+len(part)+len(sep) > chunkSize && current.Len() > 0 {
-			chunk := strings.TrimSpace(current.String())
-			if chunk != "" {
-				if len(chunk) > chunkSize {
-					// Recursively split with next separator.
-					sub := c.splitRecursive(chunk, separators[1:], chunkSize, overlap)
-					chunks = append(chunks, sub...)
-				} else {
-					chunks = append(chunks, chunk)
+		if hasCurrent && !m.fits(span{current.start, part.end}) {
+			flush(current)
+			hasCurrent = false
+			// Start the next chunk with the tail of the previous one when
+			// both still fit.
+			if last := chunks[len(chunks)-1]; m.overlap > 0 && m.size(last) > m.overlap {
+				tail := trimSpan(m.text, span{m.tailStart(last.start, last.end, m.overlap), last.end})
+				if tail.len() > 0 && m.fits(span{tail.start, part.end}) {
+					current = span{tail.start, part.end}
+					hasCurrent = true
 				}
 			}
-			current.Reset()
-			// Add overlap from previous chunk.
-			if overlap > 0 && len(chunk) > overlap {
-				current.WriteString(chunk[len(chunk)-overlap:])
-				current.WriteString(sep)
-			}
 		}
 
-		if current.Len() > 0 {
-			current.WriteString(sep)
+		if !hasCurrent {
+			current = part
+			hasCurrent = true
 		}
-		current.WriteString(part)
+		current.end = part.end
 	}
 
-	if current.Len() > 0 {
-		chunk := strings.TrimSpace(current.String())
-		if chunk != "" {
-			if len(chunk) > chunkSize {
-				sub := c.splitRecursive(chunk, separators[1:], chunkSize, overlap)
-				chunks = append(chunks, sub...)
-			} else {
-				chunks = append(chunks, chunk)
-			}
-		}
+	if hasCurrent {
+		flush(current)
 	}
 
 	return chunks
 }
 
-func (c *RecursiveChunker) splitAtSize(text string, chunkSize, overlap int) []string {
-	var chunks []string
-	start := 0
-	for start < len(text) {
-		end := start + chunkSize
-		if end > len(text) {
-			end = len(text)
-		}
-		chunks = append(chunks, text[start:end])
-		start = end - overlap
-		if start >= end {
+// splitSpan returns the pieces of s between occurrences of sep.
+func splitSpan(text string, s span, sep string) []span {
+	var parts []span
+	start := s.start
+	for {
+		i := strings.Index(text[start:s.end], sep)
+		if i < 0 {
 			break
 		}
+		parts = append(parts, span{start, start + i})
+		start += i + len(sep)
 	}
-	return chunks
+	return append(parts, span{start, s.end})
 }
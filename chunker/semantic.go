@@ -2,87 +2,195 @@ package chunker
 
 import (
 	"context"
-	"regexp"
-	"strings"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/xraph/weave/embedder"
 )
 
-// SemanticChunker groups text by sentence boundaries to create
-// semantically coherent chunks.
-type SemanticChunker struct{}
+// SemanticChunker groups sentences into chunks. With an Embedder it places
+// chunk boundaries where the meaning of the text shifts: each sentence is
+// embedded together with its neighbours, and boundaries go where the
+// distance between adjacent windows is in the top percentile. Without an
+// Embedder sentences are packed greedily up to the chunk size.
+type SemanticChunker struct {
+	// Embedder embeds sentence windows for breakpoint detection. Nil packs
+	// sentences by size only.
+	Embedder embedder.Embedder
+	// BufferSize is the number of neighbouring sentences on each side
+	// embedded with every sentence (default 1).
+	BufferSize int
+	// BreakpointPercentile is the percentile of adjacent-window distances
+	// above which a boundary is placed (default 95).
+	BreakpointPercentile float64
+	// MinChunkSize is the smallest chunk, in tokens, a breakpoint may
+	// close (default an eighth of the chunk size).
+	MinChunkSize int
+}
 
-// NewSemanticChunker creates a new SemanticChunker.
+// NewSemanticChunker creates a new SemanticChunker that packs sentences by
+// size.
 func NewSemanticChunker() *SemanticChunker { return &SemanticChunker{} }
 
-var reSentence = regexp.MustCompile(`[.!?]+\s+`)
+// NewEmbeddingSemanticChunker creates a SemanticChunker that detects topic
+// shifts with emb.
+func NewEmbeddingSemanticChunker(emb embedder.Embedder) *SemanticChunker {
+	return &SemanticChunker{Embedder: emb}
+}
+
+// embedBatchSize caps the number of sentence windows sent per Embed call.
+const embedBatchSize = 64
 
 // Chunk splits text at sentence boundaries, grouping sentences into
 // chunks that don't exceed the target size.
-func (c *SemanticChunker) Chunk(_ context.Context, text string, opts *Options) ([]ChunkResult, error) {
+func (c *SemanticChunker) Chunk(ctx context.Context, text string, opts *Options) ([]ChunkResult, error) {
 	chunkSize := 512
 	if opts != nil && opts.ChunkSize > 0 {
 		chunkSize = opts.ChunkSize
 	}
+	minSize := c.MinChunkSize
+	if minSize <= 0 {
+		minSize = chunkSize / 8
+	}
 
-	charSize := chunkSize * 4
-
-	text = strings.TrimSpace(text)
-	if text == "" {
+	var language string
+	if opts != nil {
+		language = opts.Language
+	}
+	sentences := NewSegmenter(language).spans(text, span{0, len(text)})
+	if len(sentences) == 0 {
 		return nil, nil
 	}
 
-	// Split into sentences.
-	indices := reSentence.FindAllStringIndex(text, -1)
-	var sentences []string
-	start := 0
-	for _, idx := range indices {
-		end := idx[1]
-		sentences = append(sentences, strings.TrimSpace(text[start:end]))
-		start = end
-	}
-	if start < len(text) {
-		remaining := strings.TrimSpace(text[start:])
-		if remaining != "" {
-			sentences = append(sentences, remaining)
+	var breaks []bool
+	if c.Embedder != nil && len(sentences) > 2 {
+		var err error
+		breaks, err = c.breakpoints(ctx, text, sentences)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// Group sentences into chunks.
-	var results []ChunkResult
-	var current strings.Builder
-	currentStart := 0
-	chunkIdx := 0
-
-	for _, sentence := range sentences {
-		if current.Len()+len(sentence)+1 > charSize && current.Len() > 0 {
-			content := strings.TrimSpace(current.String())
-			results = append(results, ChunkResult{
-				Content:     content,
-				Index:       chunkIdx,
-				StartOffset: currentStart,
-				EndOffset:   currentStart + len(content),
-				TokenCount:  len(content) / 4,
-			})
-			chunkIdx++
-			currentStart += len(content)
-			current.Reset()
+	// Group sentences into chunks, closing a chunk when the next sentence
+	// would not fit or at a breakpoint once the chunk is large enough.
+	m := newMeasure(text, opts, chunkSize, 0)
+	minUnits := m.units(minSize)
+	var spans []span
+	var current span
+	hasCurrent := false
+
+	for i, sentence := range sentences {
+		if hasCurrent && !m.fits(span{current.start, sentence.end}) {
+			spans = append(spans, current)
+			hasCurrent = false
 		}
 
-		if current.Len() > 0 {
-			current.WriteString(" ")
+		if !hasCurrent {
+			if !m.fits(sentence) {
+				spans = append(spans, m.windows(sentence)...)
+				continue
+			}
+			current = sentence
+			hasCurrent = true
+		}
+		current.end = sentence.end
+
+		if breaks != nil && breaks[i] && m.size(current) >= minUnits {
+			spans = append(spans, current)
+			hasCurrent = false
 		}
-		current.WriteString(sentence)
 	}
 
-	if current.Len() > 0 {
-		content := strings.TrimSpace(current.String())
-		results = append(results, ChunkResult{
-			Content:     content,
-			Index:       chunkIdx,
-			StartOffset: currentStart,
-			EndOffset:   currentStart + len(content),
-			TokenCount:  len(content) / 4,
-		})
+	if hasCurrent {
+		// Fold a short tail into the previous chunk when it fits.
+		if n := len(spans); n > 0 && m.size(current) < minUnits && m.fits(span{spans[n-1].start, current.end}) {
+			spans[n-1].end = current.end
+		} else {
+			spans = append(spans, current)
+		}
 	}
 
+	results := make([]ChunkResult, len(spans))
+	for i, sp := range spans {
+		results[i] = m.result(sp, i)
+	}
 	return results, nil
 }
+
+// breakpoints reports, for each sentence, whether a chunk boundary should
+// follow it.
+func (c *SemanticChunker) breakpoints(ctx context.Context, text string, sentences []span) ([]bool, error) {
+	buffer := c.BufferSize
+	if buffer <= 0 {
+		buffer = 1
+	}
+	percentile := c.BreakpointPercentile
+	if percentile <= 0 || percentile > 100 {
+		percentile = 95
+	}
+
+	windows := make([]string, len(sentences))
+	for i := range sentences {
+		lo, hi := max(i-buffer, 0), min(i+buffer, len(sentences)-1)
+		windows[i] = text[sentences[lo].start:sentences[hi].end]
+	}
+
+	vectors := make([][]float32, 0, len(windows))
+	for start := 0; start < len(windows); start += embedBatchSize {
+		batch := windows[start:min(start+embedBatchSize, len(windows))]
+		results, err := c.Embedder.Embed(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("weave: semantic chunker embed: %w", err)
+		}
+		if len(results) != len(batch) {
+			return nil, fmt.Errorf("weave: semantic chunker embed: got %d vectors for %d sentences", len(results), len(batch))
+		}
+		for _, r := range results {
+			vectors = append(vectors, r.Vector)
+		}
+	}
+
+	distances := make([]float64, len(vectors)-1)
+	for i := range distances {
+		distances[i] = 1 - cosine(vectors[i], vectors[i+1])
+	}
+	threshold := percentileOf(distances, percentile)
+
+	breaks := make([]bool, len(sentences))
+	for i, d := range distances {
+		breaks[i] = d > threshold
+	}
+	return breaks, nil
+}
+
+// percentileOf returns the p-th percentile of values using linear
+// interpolation between closest ranks.
+func percentileOf(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
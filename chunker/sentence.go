@@ -0,0 +1,162 @@
+package chunker
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Segmenter splits text into sentences. It follows the Unicode sentence
+// boundary rules (UAX #29), which handle full-width terminators such as
+// "。" and "！", closing quotes and brackets after a terminator, and
+// decimals, and adds language rules on top: no break after a known
+// abbreviation ("e.g.", "Dr.", "z.B.") or a single-letter initial, and for
+// Thai and Lao, which have no sentence punctuation, a break at spaces.
+type Segmenter struct {
+	// Language is an ISO 639-1 code such as "en" or "de". Region subtags,
+	// as in "en-US", are ignored.
+	Language string
+	// Abbreviations are lowercase words, without their final period, that
+	// do not end a sentence. NewSegmenter fills them in for the language.
+	Abbreviations map[string]bool
+}
+
+// NewSegmenter creates a Segmenter for language, using English
+// abbreviations for languages without a list of their own.
+func NewSegmenter(language string) *Segmenter {
+	lang := baseLanguage(language)
+	abbrevs, ok := abbreviations[lang]
+	if !ok {
+		abbrevs = abbreviations["en"]
+	}
+	return &Segmenter{Language: lang, Abbreviations: abbrevs}
+}
+
+// abbreviations lists, per language, words that are followed by a period
+// but rarely end a sentence. Words that often do, such as "etc.", are left
+// out.
+var abbreviations = map[string]map[string]bool{
+	"en": wordSet("mr mrs ms dr prof sr jr st mt rev gen col capt lt sgt vs e.g i.e cf al approx dept fig vol pp jan feb apr jun jul aug sep sept oct nov dec"),
+	"de": wordSet("dr prof hr fr nr str s vgl z.b d.h u.a bzw ca evtl ggf inkl bspw sog abs jan feb apr jun jul aug sep sept okt nov dez"),
+	"fr": wordSet("mme mlle dr pr me p.ex cf env av bd st ste janv févr avr juil sept oct nov déc"),
+	"es": wordSet("sr sra srta dr dra ud uds p.ej pág núm aprox av ene feb abr jun jul ago sept oct nov dic"),
+	"it": wordSet("sig sigg dott dr prof ing avv p.es ecc gen feb apr giu lug ago sett ott nov dic"),
+	"pt": wordSet("sr sra dr dra prof p.ex av jan fev abr jun jul ago set out nov dez"),
+	"nl": wordSet("dhr mevr dr prof bijv d.w.z o.a m.b.t jan feb apr jun jul aug sep sept okt nov dec"),
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// baseLanguage lowercases a language tag and drops any region or script
+// subtag.
+func baseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// Sentences returns the sentences in text with surrounding whitespace
+// removed.
+func (seg *Segmenter) Sentences(text string) []string {
+	spans := seg.spans(text, span{0, len(text)})
+	out := make([]string, len(spans))
+	for i, s := range spans {
+		out[i] = text[s.start:s.end]
+	}
+	return out
+}
+
+// spans returns the trimmed, non-empty sentences within s.
+func (seg *Segmenter) spans(text string, s span) []span {
+	// Unicode sentence boundaries, excluding the end of s.
+	var cuts []int
+	pos, state := s.start, -1
+	for rest := text[s.start:s.end]; rest != ""; {
+		var sentence string
+		sentence, rest, state = uniseg.FirstSentenceInString(rest, state)
+		pos += len(sentence)
+		if rest != "" {
+			cuts = append(cuts, pos)
+		}
+	}
+
+	var sentences []span
+	add := func(sp span) {
+		if seg.Language == "th" || seg.Language == "lo" {
+			for _, part := range splitSpan(text, sp, " ") {
+				if part = trimSpan(text, part); part.len() > 0 {
+					sentences = append(sentences, part)
+				}
+			}
+			return
+		}
+		if sp = trimSpan(text, sp); sp.len() > 0 {
+			sentences = append(sentences, sp)
+		}
+	}
+
+	start := s.start
+	for _, cut := range cuts {
+		// An opening bracket or quote directly after a terminator, as in
+		// "！「", starts the next sentence.
+		for cut > start {
+			r, size := utf8.DecodeLastRuneInString(text[start:cut])
+			if !unicode.In(r, unicode.Ps, unicode.Pi) {
+				break
+			}
+			cut -= size
+		}
+		if cut <= start || seg.continues(text, span{start, cut}) || quoteContinues(text, cut) {
+			continue
+		}
+		add(span{start, cut})
+		start = cut
+	}
+	add(span{start, s.end})
+	return sentences
+}
+
+// quoteContinues reports whether the text after a closing quote or bracket
+// at cut carries on the same sentence without a space, as in
+// "「本当？」と聞いた。".
+func quoteContinues(text string, cut int) bool {
+	if cut >= len(text) {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:cut])
+	after, _ := utf8.DecodeRuneInString(text[cut:])
+	closing := unicode.In(before, unicode.Pe, unicode.Pf) || before == '"' || before == '\''
+	return closing && !unicode.IsSpace(after)
+}
+
+// continues reports whether the sentence boundary at the end of s should
+// be suppressed because s ends with an abbreviation or an initial.
+func (seg *Segmenter) continues(text string, s span) bool {
+	t := trimSpan(text, s)
+	if t.len() == 0 || text[t.end-1] != '.' {
+		return false
+	}
+	word := text[t.start : t.end-1]
+	if i := strings.LastIndexFunc(word, unicode.IsSpace); i >= 0 {
+		_, size := utf8.DecodeRuneInString(word[i:])
+		word = word[i+size:]
+	}
+	word = strings.TrimLeft(word, "([{\"'“‘«")
+	if word == "" {
+		return false
+	}
+	if r, size := utf8.DecodeRuneInString(word); size == len(word) && unicode.IsUpper(r) {
+		return true
+	}
+	return seg.Abbreviations[strings.ToLower(word)]
+}
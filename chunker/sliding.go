@@ -1,11 +1,10 @@
 package chunker
 
-import (
-	"context"
-	"strings"
-)
+import "context"
 
-// SlidingChunker splits text using a sliding window with overlap.
+// SlidingChunker splits text using a sliding window with overlap. Each
+// window starts Options.ChunkOverlap (default 50) tokens before the
+// previous one ended.
 type SlidingChunker struct{}
 
 // NewSlidingChunker creates a new SlidingChunker.
@@ -13,54 +12,5 @@ func NewSlidingChunker() *SlidingChunker { return &SlidingChunker{} }
 
 // Chunk splits text using a sliding window.
 func (c *SlidingChunker) Chunk(_ context.Context, text string, opts *Options) ([]ChunkResult, error) {
-	chunkSize := 512
-	overlap := 50
-	if opts != nil {
-		if opts.ChunkSize > 0 {
-			chunkSize = opts.ChunkSize
-		}
-		if opts.ChunkOverlap > 0 {
-			overlap = opts.ChunkOverlap
-		}
-	}
-
-	charSize := chunkSize * 4
-	charOverlap := overlap * 4
-
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil, nil
-	}
-
-	var results []ChunkResult
-	start := 0
-	idx := 0
-	step := charSize - charOverlap
-	if step <= 0 {
-		step = 1
-	}
-
-	for start < len(text) {
-		end := start + charSize
-		if end > len(text) {
-			end = len(text)
-		}
-
-		content := text[start:end]
-		results = append(results, ChunkResult{
-			Content:     content,
-			Index:       idx,
-			StartOffset: start,
-			EndOffset:   end,
-			TokenCount:  len(content) / 4,
-		})
-
-		idx++
-		start += step
-		if start >= len(text) {
-			break
-		}
-	}
-
-	return results, nil
+	return windowChunks(text, opts, 50), nil
 }
@@ -0,0 +1,281 @@
+package chunker
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// span is a half-open byte range [start, end) into the original text.
+// Chunkers work on spans rather than copies so that every ChunkResult
+// satisfies text[StartOffset:EndOffset] == Content.
+type span struct {
+	start, end int
+}
+
+func (s span) len() int { return s.end - s.start }
+
+// trimSpan narrows s so that it excludes leading and trailing whitespace.
+func trimSpan(text string, s span) span {
+	for s.start < s.end {
+		r, size := utf8.DecodeRuneInString(text[s.start:s.end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.start += size
+	}
+	for s.end > s.start {
+		r, size := utf8.DecodeLastRuneInString(text[s.start:s.end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.end -= size
+	}
+	return s
+}
+
+// boundaries holds the byte offsets of every grapheme cluster boundary in a
+// text, including 0 and len(text). Cutting only at these offsets never splits
+// a multi-byte rune, a combining sequence or an emoji ZWJ sequence.
+type boundaries []int
+
+func graphemeBoundaries(text string) boundaries {
+	b := make(boundaries, 1, len(text)+1)
+	state := -1
+	offset := 0
+	for rest := text; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		offset += len(cluster)
+		b = append(b, offset)
+	}
+	return b
+}
+
+// floor returns the largest boundary <= i.
+func (b boundaries) floor(i int) int {
+	j := sort.SearchInts(b, i)
+	if j < len(b) && b[j] == i {
+		return i
+	}
+	if j == 0 {
+		return b[0]
+	}
+	return b[j-1]
+}
+
+// ceil returns the smallest boundary >= i.
+func (b boundaries) ceil(i int) int {
+	j := sort.SearchInts(b, i)
+	if j == len(b) {
+		return b[len(b)-1]
+	}
+	return b[j]
+}
+
+// measure sizes spans of a text either in real tokens, when a tokenizer is
+// configured, or in bytes at roughly four bytes per token otherwise.
+type measure struct {
+	text    string
+	bounds  boundaries
+	counter TokenCounter
+	limit   int
+	overlap int
+}
+
+func newMeasure(text string, opts *Options, chunkSize, overlap int) *measure {
+	m := &measure{
+		text:    text,
+		bounds:  graphemeBoundaries(text),
+		limit:   chunkSize * 4,
+		overlap: overlap * 4,
+	}
+	if opts != nil && opts.Tokenizer != nil {
+		m.counter = opts.Tokenizer
+		m.limit = chunkSize
+		m.overlap = overlap
+	}
+	return m
+}
+
+// size returns the size of s in the measure's unit.
+func (m *measure) size(s span) int {
+	if m.counter == nil {
+		return s.len()
+	}
+	return m.counter.CountTokens(m.text[s.start:s.end])
+}
+
+// units converts a token count into the measure's unit.
+func (m *measure) units(tokens int) int {
+	if m.counter == nil {
+		return tokens * 4
+	}
+	return tokens
+}
+
+// fits reports whether s is no larger than the chunk limit. A token is at
+// least one byte, so spans shorter than the limit are never counted.
+func (m *measure) fits(s span) bool {
+	return s.len() <= m.limit || m.size(s) <= m.limit
+}
+
+// tokens returns the token count reported for a chunk.
+func (m *measure) tokens(s span) int {
+	if m.counter == nil {
+		return s.len() / 4
+	}
+	return m.size(s)
+}
+
+// fitEnd returns the largest boundary end in [start, hi] such that
+// text[start:end] is at most n units. It returns start when not even one
+// grapheme cluster fits.
+func (m *measure) fitEnd(start, hi, n int) int {
+	if m.counter == nil {
+		return m.bounds.floor(min(start+n, hi))
+	}
+	// Gallop forward from an estimate of four bytes per token, then binary
+	// search the last window that overshot.
+	lo := start
+	for step := max(n*4, 1); ; step *= 2 {
+		probe := min(m.bounds.ceil(min(start+step, hi)), hi)
+		if m.size(span{start, probe}) > n {
+			return m.search(lo, probe, func(i int) bool { return m.size(span{start, i}) <= n })
+		}
+		if probe >= hi {
+			return hi
+		}
+		lo = probe
+	}
+}
+
+// tailStart returns the smallest boundary p in [lo, end] such that
+// text[p:end] is at most n units.
+func (m *measure) tailStart(lo, end, n int) int {
+	if m.counter == nil {
+		return m.bounds.ceil(max(end-n, lo))
+	}
+	lo = m.bounds.ceil(lo)
+	if m.size(span{lo, end}) <= n {
+		return lo
+	}
+	// search finds the last boundary that is too large; the tail starts at
+	// the boundary after it.
+	p := m.search(lo, end, func(i int) bool { return m.size(span{i, end}) > n })
+	return m.bounds.ceil(p + 1)
+}
+
+// search returns the largest boundary in [lo, hi) for which ok holds,
+// assuming ok holds at lo and is monotone.
+func (m *measure) search(lo, hi int, ok func(int) bool) int {
+	i, j := sort.SearchInts(m.bounds, lo), sort.SearchInts(m.bounds, hi)
+	for j-i > 1 {
+		mid := (i + j) / 2
+		if ok(m.bounds[mid]) {
+			i = mid
+		} else {
+			j = mid
+		}
+	}
+	return max(m.bounds[i], lo)
+}
+
+// windowChunks cuts text, trimmed of surrounding whitespace, into windows
+// of Options.ChunkSize (default 512) overlapping by Options.ChunkOverlap,
+// or by defaultOverlap when that is unset. FixedChunker and SlidingChunker
+// differ only in that default.
+func windowChunks(text string, opts *Options, defaultOverlap int) []ChunkResult {
+	chunkSize := 512
+	overlap := defaultOverlap
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			chunkSize = opts.ChunkSize
+		}
+		if opts.ChunkOverlap > 0 {
+			overlap = opts.ChunkOverlap
+		}
+	}
+
+	s := trimSpan(text, span{0, len(text)})
+	if s.len() == 0 {
+		return nil
+	}
+
+	m := newMeasure(text, opts, chunkSize, overlap)
+	spans := m.windows(s)
+	results := make([]ChunkResult, len(spans))
+	for i, sp := range spans {
+		results[i] = m.result(sp, i)
+	}
+	return results
+}
+
+// windows cuts s into consecutive windows of at most the chunk limit, each
+// starting about overlap units before the previous one ended. Every cut is
+// moved to a grapheme boundary, and a window always holds at least one
+// cluster so the loop makes progress even when overlap >= limit.
+func (m *measure) windows(s span) []span {
+	var out []span
+	start := s.start
+	for start < s.end {
+		end := m.fitEnd(start, s.end, m.limit)
+		if end <= start {
+			end = min(m.bounds.ceil(start+1), s.end)
+		}
+		out = append(out, span{start, end})
+		if end >= s.end {
+			break
+		}
+
+		next := end
+		if m.overlap > 0 {
+			next = m.tailStart(start, end, m.overlap)
+		}
+		if next <= start {
+			next = m.bounds.ceil(start + 1)
+		}
+		start = next
+	}
+	return out
+}
+
+// pack greedily merges consecutive parts into chunks that fit the limit.
+// Parts that are too large on their own are cut into windows.
+func (m *measure) pack(parts []span) []span {
+	var out []span
+	var current span
+	hasCurrent := false
+	for _, part := range parts {
+		if hasCurrent && !m.fits(span{current.start, part.end}) {
+			out = append(out, current)
+			hasCurrent = false
+		}
+		if !hasCurrent {
+			if !m.fits(part) {
+				out = append(out, m.windows(part)...)
+				continue
+			}
+			current = part
+			hasCurrent = true
+		}
+		current.end = part.end
+	}
+	if hasCurrent {
+		out = append(out, current)
+	}
+	return out
+}
+
+// result builds a ChunkResult for s, slicing its content from the text.
+func (m *measure) result(s span, index int) ChunkResult {
+	return ChunkResult{
+		Content:     m.text[s.start:s.end],
+		Index:       index,
+		StartOffset: s.start,
+		EndOffset:   s.end,
+		TokenCount:  m.tokens(s),
+	}
+}
@@ -0,0 +1,221 @@
+package chunker
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableFormat controls how TableChunker renders rows.
+type TableFormat string
+
+// Table formats.
+const (
+	// TableFormatHeader repeats the header line above the rows of every
+	// chunk, keeping the rows as they appear in the source.
+	TableFormatHeader TableFormat = "header"
+	// TableFormatRecords renders each row as "column: value" lines, with
+	// a blank line between rows. Empty cells are left out.
+	TableFormatRecords TableFormat = "records"
+)
+
+// TableChunker splits tabular text, one row per line with a header line
+// first, into chunks of whole rows. Unlike the other chunkers its Content
+// is rendered rather than sliced from the source, so that every chunk
+// carries the column names; StartOffset and EndOffset span the chunk's
+// rows in the source. The 1-based data row range is recorded under
+// MetaRowStart and MetaRowEnd.
+type TableChunker struct {
+	// Separator divides the cells of a row (default " | ", as produced by
+	// loader.CSVLoader). A single-character separator such as "," or "\t"
+	// parses the text as quoted CSV.
+	Separator string
+	// RowsPerChunk caps the rows in a chunk. Zero packs as many rows as
+	// fit in the chunk size. A row larger than the chunk size is emitted
+	// alone and never split.
+	RowsPerChunk int
+	// Format selects the rendering (default TableFormatHeader).
+	Format TableFormat
+	// MetadataColumns are copied into each chunk's metadata, keyed by
+	// column name. A new chunk starts whenever one of their values
+	// changes, so every chunk holds a single value per column and can be
+	// matched by a metadata filter.
+	MetadataColumns []string
+}
+
+// NewTableChunker creates a TableChunker for loader.CSVLoader output.
+func NewTableChunker() *TableChunker { return &TableChunker{} }
+
+// tableRow is a data row and its position in the source.
+type tableRow struct {
+	span
+	cells []string
+}
+
+// Chunk groups table rows into chunks that repeat the column names.
+func (c *TableChunker) Chunk(_ context.Context, text string, opts *Options) ([]ChunkResult, error) {
+	chunkSize := 512
+	if opts != nil && opts.ChunkSize > 0 {
+		chunkSize = opts.ChunkSize
+	}
+	var counter TokenCounter
+	if opts != nil {
+		counter = opts.Tokenizer
+	}
+	fits := func(s string) bool {
+		if counter == nil {
+			return len(s) <= chunkSize*4
+		}
+		return len(s) <= chunkSize || counter.CountTokens(s) <= chunkSize
+	}
+	tokens := func(s string) int {
+		if counter == nil {
+			return len(s) / 4
+		}
+		return counter.CountTokens(s)
+	}
+
+	sep := c.Separator
+	if sep == "" {
+		sep = " | "
+	}
+	header, rows, err := parseTable(text, sep)
+	if err != nil {
+		return nil, fmt.Errorf("weave: table chunker: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	metaCols := make(map[string]int, len(c.MetadataColumns))
+	for _, name := range c.MetadataColumns {
+		for i, h := range header.cells {
+			if strings.TrimSpace(h) == name {
+				metaCols[name] = i
+				break
+			}
+		}
+	}
+	groupKey := func(r tableRow) string {
+		var b strings.Builder
+		for _, name := range c.MetadataColumns {
+			if i, ok := metaCols[name]; ok && i < len(r.cells) {
+				b.WriteString(r.cells[i])
+			}
+			b.WriteByte(0)
+		}
+		return b.String()
+	}
+
+	render := func(first, last int) string {
+		if c.Format == TableFormatRecords {
+			return renderRecords(header.cells, rows[first:last+1])
+		}
+		return text[header.start:header.end] + "\n" + text[rows[first].start:rows[last].end]
+	}
+
+	var results []ChunkResult
+	emit := func(first, last int) {
+		content := render(first, last)
+		res := ChunkResult{
+			Content:     content,
+			Index:       len(results),
+			StartOffset: rows[first].start,
+			EndOffset:   rows[last].end,
+			TokenCount:  tokens(content),
+			Metadata: map[string]string{
+				MetaRowStart: strconv.Itoa(first + 1),
+				MetaRowEnd:   strconv.Itoa(last + 1),
+			},
+		}
+		for name, i := range metaCols {
+			if i < len(rows[first].cells) {
+				res.Metadata[name] = strings.TrimSpace(rows[first].cells[i])
+			}
+		}
+		results = append(results, res)
+	}
+
+	first := 0
+	for i := 1; i < len(rows); i++ {
+		full := c.RowsPerChunk > 0 && i-first >= c.RowsPerChunk
+		if full || groupKey(rows[i]) != groupKey(rows[first]) || !fits(render(first, i)) {
+			emit(first, i-1)
+			first = i
+		}
+	}
+	emit(first, len(rows)-1)
+
+	return results, nil
+}
+
+// parseTable splits text into its header and data rows. Blank lines are
+// skipped.
+func parseTable(text, sep string) (header tableRow, rows []tableRow, err error) {
+	if utf8.RuneCountInString(sep) == 1 {
+		rows, err = parseCSVRows(text, []rune(sep)[0])
+	} else {
+		for _, line := range lineSpans(text, span{0, len(text)}) {
+			line = trimSpan(text, line)
+			rows = append(rows, tableRow{span: line, cells: strings.Split(text[line.start:line.end], sep)})
+		}
+	}
+	if err != nil || len(rows) == 0 {
+		return header, nil, err
+	}
+	return rows[0], rows[1:], nil
+}
+
+// parseCSVRows reads quoted CSV, recording where each record lies in text.
+func parseCSVRows(text string, comma rune) ([]tableRow, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+
+	var rows []tableRow
+	prev := 0
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		offset := int(r.InputOffset())
+		rows = append(rows, tableRow{span: trimSpan(text, span{prev, offset}), cells: record})
+		prev = offset
+	}
+}
+
+// renderRecords renders rows as "column: value" lines.
+func renderRecords(header []string, rows []tableRow) string {
+	var b strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		line := 0
+		for j, cell := range row.cells {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			name := "column " + strconv.Itoa(j+1)
+			if j < len(header) && strings.TrimSpace(header[j]) != "" {
+				name = strings.TrimSpace(header[j])
+			}
+			if line > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(name + ": " + cell)
+			line++
+		}
+	}
+	return b.String()
+}
@@ -2,6 +2,8 @@
 package collection
 
 import (
+	"time"
+
 	"github.com/xraph/weave"
 	"github.com/xraph/weave/id"
 )
@@ -21,7 +23,13 @@ type Collection struct {
 	ChunkStrategy  string            `json:"chunk_strategy" bun:"chunk_strategy,notnull,default:'recursive'"`
 	ChunkSize      int               `json:"chunk_size" bun:"chunk_size,notnull,default:512"`
 	ChunkOverlap   int               `json:"chunk_overlap" bun:"chunk_overlap,notnull,default:50"`
+	Language       string            `json:"language,omitempty" bun:"language"`
+	EmbedTemplate  string            `json:"embed_template,omitempty" bun:"embed_template"`
 	Metadata       map[string]string `json:"metadata" bun:"metadata,notnull,default:'{}'"`
 	DocumentCount  int64             `json:"document_count" bun:"document_count,notnull,default:0"`
 	ChunkCount     int64             `json:"chunk_count" bun:"chunk_count,notnull,default:0"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty" bun:"deleted_at"`
 }
+
+// Trashed reports whether the collection has been soft-deleted.
+func (c *Collection) Trashed() bool { return c.DeletedAt != nil }
@@ -10,6 +10,10 @@ import (
 type ListFilter struct {
 	// Search filters collections by name (case-insensitive substring match).
 	Search string
+	// TenantID filters by tenant. Empty means all tenants.
+	TenantID string
+	// Trashed selects soft-deleted collections instead of live ones.
+	Trashed bool
 	// Limit is the maximum number of collections to return. Zero means no limit.
 	Limit int
 	// Offset is the number of collections to skip.
@@ -20,6 +24,8 @@ type ListFilter struct {
 type CountFilter struct {
 	// Search filters collections by name (case-insensitive substring match).
 	Search string
+	// Trashed selects soft-deleted collections instead of live ones.
+	Trashed bool
 }
 
 // Store defines the persistence contract for collections.
@@ -36,10 +42,11 @@ type Store interface {
 	// UpdateCollection persists changes to an existing collection.
 	UpdateCollection(ctx context.Context, col *Collection) error
 
-	// DeleteCollection removes a collection by ID.
+	// DeleteCollection permanently removes a collection by ID.
 	DeleteCollection(ctx context.Context, colID id.CollectionID) error
 
 	// ListCollections returns collections matching the given filter.
+	// Soft-deleted collections are excluded unless filter.Trashed is set.
 	ListCollections(ctx context.Context, filter *ListFilter) ([]*Collection, error)
 
 	// CountCollections returns the number of collections matching the given filter.
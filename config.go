@@ -27,6 +27,14 @@ type Config struct {
 	// IngestConcurrency is the maximum number of documents processed
 	// concurrently during batch ingestion.
 	IngestConcurrency int
+
+	// TrashPurgeDelay is how long soft-deleted collections and documents
+	// stay in the trash before the janitor permanently removes them.
+	TrashPurgeDelay time.Duration
+
+	// JanitorInterval is how often the janitor sweeps the trash.
+	// Zero disables the janitor.
+	JanitorInterval time.Duration
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -39,5 +47,7 @@ func DefaultConfig() Config {
 		DefaultTopK:           10,
 		ShutdownTimeout:       30 * time.Second,
 		IngestConcurrency:     4,
+		TrashPurgeDelay:       30 * 24 * time.Hour,
+		JanitorInterval:       time.Hour,
 	}
 }
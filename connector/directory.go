@@ -0,0 +1,388 @@
+// Package connector provides engine connectors, which keep a collection
+// in sync with an external source while the engine runs.
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/loader"
+)
+
+// Default Directory timings.
+const (
+	DefaultDebounce     = 500 * time.Millisecond
+	DefaultPollInterval = 30 * time.Second
+)
+
+var _ engine.Connector = (*Directory)(nil)
+
+// Directory keeps a collection in sync with a directory tree. It ingests
+// new files, re-ingests modified files into their existing documents, and
+// moves the document of a removed file to the trash. Documents are matched
+// to files by their path relative to the directory.
+//
+// Changes are picked up from filesystem notifications and scanned once
+// they settle for Debounce. Where notifications are unavailable, or when
+// Poll is set, the directory is rescanned every PollInterval instead.
+type Directory struct {
+	collectionID id.CollectionID
+	dir          string
+	files        *loader.DirectoryLoader
+
+	// StatePath is a file where the sync state is saved after every scan,
+	// so a restart only processes what changed while the connector was
+	// down. Empty keeps the state in memory.
+	StatePath string
+	// Debounce is how long the directory must be quiet before a change is
+	// scanned (default DefaultDebounce).
+	Debounce time.Duration
+	// Poll disables filesystem notifications.
+	Poll bool
+	// PollInterval is the time between scans when polling (default
+	// DefaultPollInterval).
+	PollInterval time.Duration
+
+	// documents maps relative paths to the documents ingested for them.
+	documents map[string]id.DocumentID
+	loaded    bool
+}
+
+// NewDirectory creates a connector that syncs dir into a collection.
+// files loads the directory and sets its filters; the connector manages
+// its Manifest.
+func NewDirectory(colID id.CollectionID, dir string, files *loader.DirectoryLoader) *Directory {
+	return &Directory{
+		collectionID: colID,
+		dir:          dir,
+		files:        files,
+		documents:    make(map[string]id.DocumentID),
+	}
+}
+
+// Name identifies the connector in logs.
+func (d *Directory) Name() string { return "directory:" + d.dir }
+
+// directoryState is what Directory saves to StatePath.
+type directoryState struct {
+	Manifest  json.RawMessage          `json:"manifest"`
+	Documents map[string]id.DocumentID `json:"documents"`
+}
+
+// Run syncs the directory, then keeps syncing it as it changes until ctx
+// is cancelled.
+func (d *Directory) Run(ctx context.Context, e *engine.Engine) error {
+	if err := d.Sync(ctx, e); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		e.Logger().Warn("directory sync failed",
+			log.String("dir", d.dir),
+			log.String("error", err.Error()),
+		)
+	}
+
+	var watcher *fsnotify.Watcher
+	if !d.Poll {
+		var err error
+		if watcher, err = d.watch(); err != nil {
+			e.Logger().Warn("directory notifications unavailable; polling",
+				log.String("dir", d.dir),
+				log.String("error", err.Error()),
+			)
+		}
+	}
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	var poll <-chan time.Time
+	if watcher != nil {
+		defer func() { _ = watcher.Close() }()
+		events, watchErrs = watcher.Events, watcher.Errors
+	} else {
+		interval := d.PollInterval
+		if interval <= 0 {
+			interval = DefaultPollInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	debounce := d.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		var scan bool
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			// Watch new directories; fsnotify is not recursive.
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					_ = addTree(watcher, ev.Name, d.files.SkipHidden)
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watchErrs:
+			if !ok {
+				return nil
+			}
+			// Events may have been dropped; rescan to catch up.
+			e.Logger().Warn("directory watch error",
+				log.String("dir", d.dir),
+				log.String("error", err.Error()),
+			)
+			timer.Reset(debounce)
+		case <-timer.C:
+			scan = true
+		case <-poll:
+			scan = true
+		}
+		if !scan {
+			continue
+		}
+		if err := d.Sync(ctx, e); err != nil && ctx.Err() == nil {
+			e.Logger().Warn("directory sync failed",
+				log.String("dir", d.dir),
+				log.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// Sync scans the directory once and applies the changes since the last
+// scan to the collection. Files that fail to load or ingest are logged
+// and retried on the next scan.
+func (d *Directory) Sync(ctx context.Context, e *engine.Engine) error {
+	if err := d.loadState(); err != nil {
+		return err
+	}
+
+	var ingested, updated, failed int
+	err := d.files.EachFile(ctx, d.dir, func(result *loader.LoadResult, err error) error {
+		if err != nil {
+			failed++
+			e.Logger().Warn("directory file failed",
+				log.String("error", err.Error()),
+			)
+			return nil
+		}
+		rel, relErr := filepath.Rel(d.dir, result.Metadata["source_path"])
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		docID, replaced, err := d.upsert(ctx, e, rel, result)
+		if !docID.IsNil() {
+			d.documents[rel] = docID
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+			e.Logger().Warn("directory ingest failed",
+				log.String("path", rel),
+				log.String("error", err.Error()),
+			)
+			return loader.ErrSkipFile
+		}
+		if replaced {
+			updated++
+		} else {
+			ingested++
+		}
+		return nil
+	})
+	if err != nil {
+		// Save what was done so far; the manifest is only pruned by a
+		// complete scan.
+		if saveErr := d.saveState(); saveErr != nil {
+			err = errors.Join(err, saveErr)
+		}
+		return err
+	}
+
+	removed := d.files.Manifest.Removed()
+	for _, rel := range removed {
+		docID, ok := d.documents[rel]
+		if !ok {
+			continue
+		}
+		if err := d.remove(ctx, e, docID); err != nil {
+			e.Logger().Warn("directory could not trash removed document",
+				log.String("path", rel),
+				log.String("document_id", docID.String()),
+				log.String("error", err.Error()),
+			)
+			continue
+		}
+		delete(d.documents, rel)
+	}
+
+	if ingested+updated+len(removed)+failed > 0 {
+		e.Logger().Info("directory synced",
+			log.String("dir", d.dir),
+			log.Int("ingested", ingested),
+			log.Int("updated", updated),
+			log.Int("removed", len(removed)),
+			log.Int("failed", failed),
+		)
+	}
+	return d.saveState()
+}
+
+// upsert ingests a loaded file, replacing the document synced for it
+// earlier in place so its ID stays the same. It reports the document the
+// file maps to, which is set even when ingestion failed after the
+// document was created, so the retry replaces it.
+func (d *Directory) upsert(ctx context.Context, e *engine.Engine, rel string, result *loader.LoadResult) (id.DocumentID, bool, error) {
+	title := result.Metadata["title"]
+	if title == "" {
+		title = filepath.Base(rel)
+	}
+	input := &engine.IngestInput{
+		CollectionID: d.collectionID,
+		Title:        title,
+		Source:       rel,
+		Content:      result.Content,
+		Metadata:     result.Metadata,
+	}
+
+	if docID, ok := d.documents[rel]; ok {
+		_, err := e.ReplaceDocument(ctx, docID, input)
+		if !errors.Is(err, weave.ErrDocumentNotFound) && !errors.Is(err, weave.ErrDocumentTrashed) {
+			return docID, true, err
+		}
+		// The document was removed outside the connector; start over.
+	}
+	res, err := e.Ingest(ctx, input)
+	if res == nil {
+		return id.DocumentID{}, false, err
+	}
+	return res.DocumentID, false, err
+}
+
+// remove trashes a document. Documents that are already gone are ignored.
+func (d *Directory) remove(ctx context.Context, e *engine.Engine, docID id.DocumentID) error {
+	err := e.DeleteDocument(ctx, docID)
+	if errors.Is(err, weave.ErrDocumentNotFound) || errors.Is(err, weave.ErrDocumentTrashed) {
+		return nil
+	}
+	return err
+}
+
+// loadState reads StatePath on the first scan.
+func (d *Directory) loadState() error {
+	if d.loaded {
+		return nil
+	}
+	d.loaded = true
+	d.files.Manifest = loader.NewManifest()
+	if d.StatePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(d.StatePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("weave: read directory state: %w", err)
+	}
+	var state directoryState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("weave: read directory state: %w", err)
+	}
+	if len(state.Manifest) > 0 {
+		m, err := loader.ReadManifest(bytes.NewReader(state.Manifest))
+		if err != nil {
+			return err
+		}
+		d.files.Manifest = m
+	}
+	if state.Documents != nil {
+		d.documents = state.Documents
+	}
+	return nil
+}
+
+// saveState writes StatePath, replacing it atomically.
+func (d *Directory) saveState() error {
+	if d.StatePath == "" {
+		return nil
+	}
+	var manifest bytes.Buffer
+	if err := d.files.Manifest.Save(&manifest); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(directoryState{
+		Manifest:  manifest.Bytes(),
+		Documents: d.documents,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("weave: save directory state: %w", err)
+	}
+
+	tmp := d.StatePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("weave: save directory state: %w", err)
+	}
+	if err := os.Rename(tmp, d.StatePath); err != nil {
+		return fmt.Errorf("weave: save directory state: %w", err)
+	}
+	return nil
+}
+
+// watch starts watching the directory tree.
+func (d *Directory) watch() (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := addTree(watcher, d.dir, d.files.SkipHidden); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// addTree watches dir and every directory below it.
+func addTree(watcher *fsnotify.Watcher, dir string, skipHidden bool) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if skipHidden && path != dir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
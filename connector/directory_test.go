@@ -0,0 +1,189 @@
+package connector_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/connector"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/loader"
+	"github.com/xraph/weave/store/memory"
+	vsmemory "github.com/xraph/weave/vectorstore/memory"
+)
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(_ context.Context, texts []string) ([]embedder.EmbedResult, error) {
+	out := make([]embedder.EmbedResult, len(texts))
+	for i, text := range texts {
+		out[i] = embedder.EmbedResult{Vector: []float32{float32(len(text)), 1}}
+	}
+	return out, nil
+}
+
+func (fakeEmbedder) Dimensions() int { return 2 }
+
+func newEngine(t *testing.T, colID id.CollectionID, opts ...engine.Option) (*engine.Engine, *collection.Collection) {
+	t.Helper()
+	eng, err := engine.New(append([]engine.Option{
+		engine.WithStore(memory.New()),
+		engine.WithVectorStore(vsmemory.New()),
+		engine.WithEmbedder(fakeEmbedder{}),
+		engine.WithChunker(chunker.NewFixedChunker()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := &collection.Collection{ID: colID, Name: "docs"}
+	if err := eng.CreateCollection(context.Background(), col); err != nil {
+		t.Fatal(err)
+	}
+	return eng, col
+}
+
+// liveSources returns the sources of the collection's live documents.
+func liveSources(t *testing.T, eng *engine.Engine, col *collection.Collection) map[string]string {
+	t.Helper()
+	docs, err := eng.ListDocuments(context.Background(), &document.ListFilter{CollectionID: col.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]string)
+	for _, doc := range docs {
+		out[doc.Source] = doc.ID.String()
+	}
+	return out
+}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirectorySync(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	state := filepath.Join(t.TempDir(), "state.json")
+	write(t, filepath.Join(dir, "a.txt"), "alpha")
+	write(t, filepath.Join(dir, "sub", "b.txt"), "beta")
+
+	eng, col := newEngine(t, id.NewCollectionID())
+	conn := connector.NewDirectory(col.ID, dir, loader.NewDirectoryLoader(loader.NewTextLoader()))
+	conn.StatePath = state
+	if err := conn.Sync(ctx, eng); err != nil {
+		t.Fatal(err)
+	}
+	first := liveSources(t, eng, col)
+	if len(first) != 2 || first["a.txt"] == "" || first["sub/b.txt"] == "" {
+		t.Fatalf("after first sync: %v", first)
+	}
+
+	// A new connector with the saved state only processes the changes.
+	write(t, filepath.Join(dir, "a.txt"), "alpha, edited")
+	write(t, filepath.Join(dir, "c.txt"), "gamma")
+	if err := os.Remove(filepath.Join(dir, "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	conn = connector.NewDirectory(col.ID, dir, loader.NewDirectoryLoader(loader.NewTextLoader()))
+	conn.StatePath = state
+	if err := conn.Sync(ctx, eng); err != nil {
+		t.Fatal(err)
+	}
+	second := liveSources(t, eng, col)
+	if got := slices.Sorted(maps.Keys(second)); !slices.Equal(got, []string{"a.txt", "c.txt"}) {
+		t.Fatalf("after second sync: %v", second)
+	}
+	// The modified file is re-ingested into the same document.
+	if second["a.txt"] != first["a.txt"] {
+		t.Errorf("modified file moved from document %s to %s", first["a.txt"], second["a.txt"])
+	}
+	docID, err := id.ParseDocumentID(second["a.txt"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := eng.Store().ListChunksByDocument(ctx, docID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].Content != "alpha, edited" {
+		t.Errorf("chunks of modified file = %+v", chunks)
+	}
+
+	// The removed file's document is in the trash.
+	trash, err := eng.ListTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Documents) != 1 || trash.Documents[0].Source != "sub/b.txt" {
+		t.Errorf("trash = %+v", trash.Documents)
+	}
+}
+
+func TestDirectoryRunsWithEngine(t *testing.T) {
+	for _, poll := range []bool{false, true} {
+		dir := t.TempDir()
+		write(t, filepath.Join(dir, "a.txt"), "alpha")
+
+		colID := id.NewCollectionID()
+		conn := connector.NewDirectory(colID, dir, loader.NewDirectoryLoader(loader.NewTextLoader()))
+		conn.Poll = poll
+		conn.StatePath = filepath.Join(t.TempDir(), "state.json")
+		conn.Debounce = 20 * time.Millisecond
+		conn.PollInterval = 20 * time.Millisecond
+		eng, col := newEngine(t, colID, engine.WithConnector(conn))
+
+		if err := eng.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		// Progress is read from the saved state, since the memory store
+		// is not safe to read while the connector writes to it.
+		waitFor := func(want ...string) {
+			t.Helper()
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				var state struct{ Documents map[string]string }
+				if data, err := os.ReadFile(conn.StatePath); err == nil {
+					_ = json.Unmarshal(data, &state)
+				}
+				got := slices.Sorted(maps.Keys(state.Documents))
+				if slices.Equal(got, want) {
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("poll=%v: documents %v, want %v", poll, got, want)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		waitFor("a.txt")
+		write(t, filepath.Join(dir, "new", "b.txt"), "beta")
+		waitFor("a.txt", "new/b.txt")
+		if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+			t.Fatal(err)
+		}
+		waitFor("new/b.txt")
+
+		if err := eng.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := slices.Sorted(maps.Keys(liveSources(t, eng, col))); !slices.Equal(got, []string{"new/b.txt"}) {
+			t.Errorf("poll=%v: live documents %v", poll, got)
+		}
+	}
+}
@@ -41,6 +41,14 @@ func resolveIcon(iconName string, opts ...icons.Option) templ.Component {
 		return icons.Zap(opts...)
 	case "settings":
 		return icons.Settings(opts...)
+	case "trash-2":
+		return icons.Trash2(opts...)
+	case "coins":
+		return icons.Coins(opts...)
+	case "chart-column":
+		return icons.ChartColumn(opts...)
+	case "refresh-cw":
+		return icons.RefreshCw(opts...)
 	default:
 		return icons.Info(opts...)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/xraph/weave/id"
 	"github.com/xraph/weave/plugins"
 	"github.com/xraph/weave/store"
+	"github.com/xraph/weave/usage"
 )
 
 var _ contributor.LocalContributor = (*Contributor)(nil)
@@ -96,6 +98,8 @@ func (c *Contributor) renderPageRoute(ctx context.Context, pageRoute string, s s
 		return c.renderCollectionDetail(ctx, s, params)
 	case "/collections/create":
 		return c.renderCollectionForm(ctx, s, params)
+	case "/collections/preview":
+		return c.renderIngestPreview(ctx, s, params)
 	case "/collections/edit":
 		return c.renderCollectionForm(ctx, s, params)
 	case "/documents":
@@ -116,6 +120,10 @@ func (c *Contributor) renderPageRoute(ctx context.Context, pageRoute string, s s
 		return c.renderLoaders(ctx)
 	case "/extensions":
 		return c.renderExtensions(ctx)
+	case "/trash":
+		return c.renderTrash(ctx, s)
+	case "/usage":
+		return c.renderUsage(ctx, s, params)
 	default:
 		return components.EmptyState("alert-circle", "Page not found", "The requested page '"+pageRoute+"' does not exist in the Weave dashboard."), nil
 	}
@@ -239,6 +247,33 @@ func (c *Contributor) renderCollectionForm(ctx context.Context, s store.Store, p
 	return pages.CollectionFormPage(nil, cfg), nil
 }
 
+func (c *Contributor) renderIngestPreview(ctx context.Context, s store.Store, params contributor.Params) (templ.Component, error) {
+	colID, err := id.ParseCollectionID(params.QueryParams["id"])
+	if err != nil {
+		return nil, contributor.ErrPageNotFound
+	}
+	col, err := s.GetCollection(ctx, colID)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: resolve collection for preview: %w", err)
+	}
+
+	content := params.FormData["content"]
+	sourceType := params.FormData["source_type"]
+	if content == "" {
+		return pages.IngestPreviewPage(col, content, sourceType, nil, ""), nil
+	}
+
+	result, err := c.engine.PreviewIngest(ctx, &engine.IngestInput{
+		CollectionID: colID,
+		SourceType:   sourceType,
+		Content:      content,
+	})
+	if err != nil {
+		return pages.IngestPreviewPage(col, content, sourceType, nil, err.Error()), nil
+	}
+	return pages.IngestPreviewPage(col, content, sourceType, result, ""), nil
+}
+
 func (c *Contributor) renderDocuments(ctx context.Context, s store.Store, params contributor.Params) (templ.Component, error) {
 	search := params.QueryParams["search"]
 	stateFilter := params.QueryParams["state"]
@@ -376,6 +411,51 @@ func (c *Contributor) renderExtensions(_ context.Context) (templ.Component, erro
 	return pages.ExtensionsPage(extNames), nil
 }
 
+func (c *Contributor) renderTrash(ctx context.Context, s store.Store) (templ.Component, error) {
+	trash, err := c.engine.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: list trash: %w", err)
+	}
+	colNames := buildCollectionNameMap(ctx, s)
+	for _, col := range trash.Collections {
+		colNames[col.ID.String()] = col.Name
+	}
+	return pages.TrashPage(trash.Collections, trash.Documents, colNames, c.engine.Config().TrashPurgeDelay), nil
+}
+
+func (c *Contributor) renderUsage(ctx context.Context, s store.Store, params contributor.Params) (templ.Component, error) {
+	q := &usage.Query{
+		TenantID:    params.QueryParams["tenant"],
+		Operation:   usage.Operation(params.QueryParams["operation"]),
+		Granularity: usage.Granularity(params.QueryParams["granularity"]),
+	}
+	if !q.Granularity.Valid() {
+		q.Granularity = usage.GranularityDay
+	}
+	if colID, err := id.ParseCollectionID(params.QueryParams["collection"]); err == nil {
+		q.CollectionID = colID
+	}
+	if t, err := time.Parse(time.DateOnly, params.QueryParams["from"]); err == nil {
+		q.From = t
+	} else {
+		// Default to the last 30 days.
+		q.From = time.Now().UTC().AddDate(0, 0, -30).Truncate(24 * time.Hour)
+	}
+	if t, err := time.Parse(time.DateOnly, params.QueryParams["to"]); err == nil {
+		// The date picker's "to" is inclusive.
+		q.To = t.AddDate(0, 0, 1)
+	}
+
+	summaries, err := c.engine.QueryUsage(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: query usage: %w", err)
+	}
+
+	collections, _ := s.ListCollections(ctx, &collection.ListFilter{}) //nolint:errcheck // best-effort
+	colNames := buildCollectionNameMap(ctx, s)
+	return pages.UsagePage(summaries, collections, colNames, params.QueryParams), nil
+}
+
 // --- Widget Renderers ---
 
 func (c *Contributor) renderStatsWidget(ctx context.Context, s store.Store) (templ.Component, error) {
@@ -77,8 +77,10 @@ func baseNav() []contributor.NavItem {
 		{Label: "Collections", Path: "/collections", Icon: "folders", Group: "Content", Priority: 3},
 		{Label: "Documents", Path: "/documents", Icon: "file-text", Group: "Content", Priority: 4},
 		{Label: "Chunks", Path: "/chunks", Icon: "puzzle", Group: "Content", Priority: 5},
-		{Label: "Loaders", Path: "/loaders", Icon: "upload", Group: "Reference", Priority: 6},
-		{Label: "Extensions", Path: "/extensions", Icon: "plug", Group: "Reference", Priority: 7},
+		{Label: "Trash", Path: "/trash", Icon: "trash-2", Group: "Content", Priority: 6},
+		{Label: "Usage", Path: "/usage", Icon: "chart-column", Group: "Weave", Priority: 7},
+		{Label: "Loaders", Path: "/loaders", Icon: "upload", Group: "Reference", Priority: 8},
+		{Label: "Extensions", Path: "/extensions", Icon: "plug", Group: "Reference", Priority: 9},
 	}
 }
 
@@ -687,7 +687,7 @@ func CollectionsPage(items []*collection.Collection, search string, pg shared.Pa
 								templ_7745c5c3_Err = components.ConfirmDialog(components.ConfirmDialogProps{
 									ID:           "delete-col-" + c.ID.String(),
 									Title:        "Delete Collection",
-									Description:  "Are you sure you want to delete collection \"" + c.Name + "\"? All documents and chunks will be permanently deleted.",
+									Description:  "Are you sure you want to delete collection \"" + c.Name + "\"? It will be moved to the trash and can be restored until it is purged.",
 									ConfirmLabel: "Delete",
 									HxEndpoint:   "/weave/collections/" + c.ID.String(),
 									HxMethod:     "delete",
@@ -949,7 +949,7 @@ func DocumentDetailPage(doc *document.Document, previewChunks []*chunk.Chunk, ch
 		templ_7745c5c3_Err = components.ConfirmDialog(components.ConfirmDialogProps{
 			ID:           "delete-doc-detail-" + doc.ID.String(),
 			Title:        "Delete Document",
-			Description:  "Are you sure you want to delete this document? All associated chunks will be permanently removed.",
+			Description:  "Are you sure you want to delete this document? It will be moved to the trash and can be restored until it is purged.",
 			ConfirmLabel: "Delete",
 			HxEndpoint:   "/weave/documents/" + doc.ID.String(),
 			HxMethod:     "delete",
@@ -767,7 +767,7 @@ func DocumentsPage(items []*document.Document, collections []*collection.Collect
 								templ_7745c5c3_Err = components.ConfirmDialog(components.ConfirmDialogProps{
 									ID:           "delete-doc-" + d.ID.String(),
 									Title:        "Delete Document",
-									Description:  "Are you sure you want to delete this document? All chunks will be permanently removed.",
+									Description:  "Are you sure you want to delete this document? It will be moved to the trash and can be restored until it is purged.",
 									ConfirmLabel: "Delete",
 									HxEndpoint:   "/weave/documents/" + d.ID.String(),
 									HxMethod:     "delete",
@@ -2,6 +2,8 @@
 package document
 
 import (
+	"time"
+
 	"github.com/xraph/weave"
 	"github.com/xraph/weave/id"
 )
@@ -36,4 +38,8 @@ type Document struct {
 	Metadata      map[string]string `json:"metadata" bun:"metadata,notnull,default:'{}'"`
 	State         State             `json:"state" bun:"state,notnull,default:'pending'"`
 	Error         string            `json:"error,omitempty" bun:"error"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty" bun:"deleted_at"`
 }
+
+// Trashed reports whether the document has been soft-deleted.
+func (d *Document) Trashed() bool { return d.DeletedAt != nil }
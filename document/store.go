@@ -10,10 +10,16 @@ import (
 type ListFilter struct {
 	// CollectionID filters by collection. Empty means all collections.
 	CollectionID id.CollectionID
+	// TenantID filters by tenant. Empty means all tenants.
+	TenantID string
 	// State filters by document state. Empty means all states.
 	State State
 	// Search filters documents by title (case-insensitive substring match).
 	Search string
+	// Metadata filters by metadata values. Every key must match exactly.
+	Metadata map[string]string
+	// Trashed selects soft-deleted documents instead of live ones.
+	Trashed bool
 	// Limit is the maximum number of documents to return. Zero means no limit.
 	Limit int
 	// Offset is the number of documents to skip.
@@ -26,6 +32,8 @@ type CountFilter struct {
 	CollectionID id.CollectionID
 	// State filters by document state. Empty means all states.
 	State State
+	// Trashed selects soft-deleted documents instead of live ones.
+	Trashed bool
 }
 
 // Store defines the persistence contract for documents.
@@ -39,10 +47,11 @@ type Store interface {
 	// UpdateDocument persists changes to an existing document.
 	UpdateDocument(ctx context.Context, doc *Document) error
 
-	// DeleteDocument removes a document by ID.
+	// DeleteDocument permanently removes a document by ID.
 	DeleteDocument(ctx context.Context, docID id.DocumentID) error
 
 	// ListDocuments returns documents matching the given filter.
+	// Soft-deleted documents are excluded unless filter.Trashed is set.
 	ListDocuments(ctx context.Context, filter *ListFilter) ([]*Document, error)
 
 	// CountDocuments returns the number of documents matching the given filter.
@@ -0,0 +1,26 @@
+package embedder
+
+// pricesPerMillion lists published prices in USD per million input tokens
+// for well-known embedding models.
+var pricesPerMillion = map[string]float64{
+	"text-embedding-3-small": 0.02,
+	"text-embedding-3-large": 0.13,
+	"text-embedding-ada-002": 0.10,
+}
+
+// PricePerMillionTokens returns the price in USD per million tokens for a
+// known embedding model.
+func PricePerMillionTokens(model string) (float64, bool) {
+	p, ok := pricesPerMillion[model]
+	return p, ok
+}
+
+// EstimateCost returns the cost in USD of embedding the given number of
+// tokens with model, or false if the model's price is unknown.
+func EstimateCost(model string, tokens int64) (float64, bool) {
+	p, ok := PricePerMillionTokens(model)
+	if !ok {
+		return 0, false
+	}
+	return float64(tokens) * p / 1_000_000, true
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	log "github.com/xraph/go-utils/log"
+)
+
+// Connector keeps a collection in sync with an external source, such as a
+// directory. The engine runs every registered connector in its own
+// goroutine from Start until Stop.
+type Connector interface {
+	// Name identifies the connector in logs.
+	Name() string
+	// Run syncs until ctx is cancelled. The context carries the values of
+	// the context passed to Start, such as the tenant.
+	Run(ctx context.Context, e *Engine) error
+}
+
+// connectorRunner tracks the connectors started by Start.
+type connectorRunner struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startConnectors launches the registered connectors.
+func (e *Engine) startConnectors(ctx context.Context) {
+	if len(e.connectors) == 0 || e.running != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.running = &connectorRunner{cancel: cancel}
+	for _, c := range e.connectors {
+		e.running.wg.Add(1)
+		go func() {
+			defer e.running.wg.Done()
+			if err := c.Run(ctx, e); err != nil && !errors.Is(err, context.Canceled) {
+				e.logger.Warn("connector stopped",
+					log.String("connector", c.Name()),
+					log.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+}
+
+// stopConnectors cancels the running connectors and waits for them.
+func (e *Engine) stopConnectors() {
+	if e.running == nil {
+		return
+	}
+	e.running.cancel()
+	e.running.wg.Wait()
+	e.running = nil
+}
@@ -4,22 +4,28 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/xraph/go-utils/log"
 
 	"github.com/xraph/weave"
+	"github.com/xraph/weave/assembler"
 	"github.com/xraph/weave/chunk"
 	"github.com/xraph/weave/chunker"
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/enricher"
 	"github.com/xraph/weave/id"
 	"github.com/xraph/weave/loader"
 	"github.com/xraph/weave/plugins"
+	"github.com/xraph/weave/quota"
 	"github.com/xraph/weave/retriever"
 	"github.com/xraph/weave/store"
+	"github.com/xraph/weave/usage"
 	"github.com/xraph/weave/vectorstore"
 )
 
@@ -31,10 +37,25 @@ type Engine struct {
 	vectorStore vectorstore.VectorStore
 	embedder    embedder.Embedder
 	chunker     chunker.Chunker
+	tokenizer   chunker.TokenCounter
+	enricher    enricher.Enricher
 	loader      loader.Loader
 	retriever   retriever.Retriever
 	extensions  *plugins.Registry
 	pendingExts []plugins.Extension
+
+	defaultQuota quota.Limits
+	quotaMu      sync.Mutex
+	ledgers      map[string]*tenantLedger
+
+	janitorStop chan struct{}
+	janitorDone chan struct{}
+
+	trashMu    sync.Mutex
+	trashCache map[trashScope]*trashFilter
+
+	connectors []Connector
+	running    *connectorRunner
 }
 
 // New creates a new Engine with the given options.
@@ -67,14 +88,18 @@ func (e *Engine) Health(ctx context.Context) error {
 	return nil
 }
 
-// Start initialises the engine. Currently a no-op but reserved for
-// future background processes (e.g. ingest workers).
-func (e *Engine) Start(_ context.Context) error {
+// Start initialises the engine, launches the trash janitor when
+// Config.JanitorInterval is positive, and runs the registered connectors.
+func (e *Engine) Start(ctx context.Context) error {
+	e.startJanitor()
+	e.startConnectors(ctx)
 	return nil
 }
 
 // Stop gracefully shuts down the engine.
 func (e *Engine) Stop(ctx context.Context) error {
+	e.stopConnectors()
+	e.stopJanitor()
 	if e.extensions != nil {
 		e.extensions.EmitShutdown(ctx)
 	}
@@ -90,6 +115,15 @@ func (e *Engine) Store() store.Store { return e.store }
 // Logger returns the engine's logger.
 func (e *Engine) Logger() log.Logger { return e.logger }
 
+// Assembler creates a context assembler that counts tokens with the
+// engine's tokenizer, so context budgets match chunk sizes.
+func (e *Engine) Assembler(opts ...assembler.Option) *assembler.Assembler {
+	if e.tokenizer != nil {
+		opts = append([]assembler.Option{assembler.WithTokenCounter(e.tokenizer)}, opts...)
+	}
+	return assembler.New(opts...)
+}
+
 // Config returns a copy of the engine's configuration.
 func (e *Engine) Config() weave.Config { return e.config }
 
@@ -127,10 +161,22 @@ func (e *Engine) CreateCollection(ctx context.Context, col *collection.Collectio
 	if col.ChunkOverlap == 0 {
 		col.ChunkOverlap = e.config.DefaultChunkOverlap
 	}
+	if col.EmbedTemplate != "" {
+		if _, err := enricher.NewTemplateEnricher(col.EmbedTemplate); err != nil {
+			return fmt.Errorf("%w: %w", weave.ErrInvalidTemplate, err)
+		}
+	}
+
+	held, err := e.checkQuota(ctx, col.TenantID, quotaRequest{quota.ResourceCollections, 1})
+	if err != nil {
+		return err
+	}
+	defer held.release()
 
 	if err := e.store.CreateCollection(ctx, col); err != nil {
 		return err
 	}
+	held.keep()
 
 	e.extensions.EmitCollectionCreated(ctx, col)
 	return nil
@@ -161,35 +207,28 @@ func (e *Engine) ListCollections(ctx context.Context, filter *collection.ListFil
 	return e.store.ListCollections(ctx, filter)
 }
 
-// DeleteCollection removes a collection and all its documents and chunks.
+// DeleteCollection moves a collection to the trash. Its documents and
+// chunks are kept but excluded from retrieval until the collection is
+// restored or purged.
 func (e *Engine) DeleteCollection(ctx context.Context, colID id.CollectionID) error {
 	if e.store == nil {
 		return weave.ErrNoStore
 	}
 
-	// Delete chunks and documents first.
-	if err := e.store.DeleteChunksByCollection(ctx, colID); err != nil {
-		return fmt.Errorf("weave: delete chunks for collection: %w", err)
-	}
-	if err := e.store.DeleteDocumentsByCollection(ctx, colID); err != nil {
-		return fmt.Errorf("weave: delete documents for collection: %w", err)
+	col, err := e.store.GetCollection(ctx, colID)
+	if err != nil {
+		return err
 	}
-
-	// Delete vector entries for the collection.
-	if e.vectorStore != nil {
-		if err := e.vectorStore.DeleteByMetadata(ctx, map[string]string{
-			"collection_id": colID.String(),
-		}); err != nil {
-			e.logger.Warn("failed to delete vector entries for collection",
-				log.String("collection_id", colID.String()),
-				log.String("error", err.Error()),
-			)
-		}
+	if col.Trashed() {
+		return weave.ErrCollectionTrashed
 	}
 
-	if err := e.store.DeleteCollection(ctx, colID); err != nil {
-		return err
+	now := time.Now().UTC()
+	col.DeletedAt = &now
+	if err := e.store.UpdateCollection(ctx, col); err != nil {
+		return fmt.Errorf("weave: trash collection: %w", err)
 	}
+	e.invalidateTrash()
 
 	e.extensions.EmitCollectionDeleted(ctx, colID)
 	return nil
@@ -284,11 +323,23 @@ func (e *Engine) Ingest(ctx context.Context, input *IngestInput) (*IngestResult,
 	start := time.Now()
 	tenantID := weave.TenantFromContext(ctx)
 
-	// Verify collection exists.
+	// Verify collection exists and is not in the trash.
 	col, err := e.store.GetCollection(ctx, input.CollectionID)
 	if err != nil {
 		return nil, err
 	}
+	if col.Trashed() {
+		return nil, weave.ErrCollectionTrashed
+	}
+
+	held, err := e.checkQuota(ctx, tenantID,
+		quotaRequest{quota.ResourceDocuments, 1},
+		quotaRequest{quota.ResourceStoredBytes, int64(len(input.Content))},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer held.release()
 
 	// Compute content hash.
 	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(input.Content)))
@@ -311,6 +362,7 @@ func (e *Engine) Ingest(ctx context.Context, input *IngestInput) (*IngestResult,
 	if createErr := e.store.CreateDocument(ctx, doc); createErr != nil {
 		return nil, fmt.Errorf("weave: create document: %w", createErr)
 	}
+	held.keep()
 
 	e.extensions.EmitIngestStarted(ctx, input.CollectionID, []*document.Document{doc})
 
@@ -318,117 +370,448 @@ func (e *Engine) Ingest(ctx context.Context, input *IngestInput) (*IngestResult,
 	doc.State = document.StateProcessing
 	_ = e.store.UpdateDocument(ctx, doc) //nolint:errcheck // best-effort status update
 
+	out, err := e.process(ctx, col, doc, input, 0)
+	if err != nil {
+		return e.failIngest(ctx, doc, input.CollectionID, err)
+	}
+	defer out.quota.release()
+
+	// Store chunks in metadata store.
+	if err := e.store.CreateChunkBatch(ctx, out.chunks); err != nil {
+		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("store chunks: %w", err))
+	}
+	out.quota.keep()
+
+	// Upsert vector entries.
+	if err := e.vectorStore.Upsert(ctx, out.entries); err != nil {
+		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("upsert vectors: %w", err))
+	}
+
+	// Mark document as ready.
+	doc.State = document.StateReady
+	doc.ChunkCount = len(out.chunks)
+	_ = e.store.UpdateDocument(ctx, doc) //nolint:errcheck // best-effort status update
+
+	elapsed := time.Since(start)
+	e.extensions.EmitIngestCompleted(ctx, input.CollectionID, 1, len(out.chunks), elapsed)
+
+	return &IngestResult{
+		DocumentID: doc.ID,
+		ChunkCount: len(out.chunks),
+		State:      document.StateReady,
+	}, nil
+}
+
+// processed is a document's chunks and vector entries, ready to store,
+// with the quota reserved for the chunks.
+type processed struct {
+	chunks  []*chunk.Chunk
+	entries []vectorstore.Entry
+	quota   *reservation
+}
+
+// process loads, chunks, enriches and embeds the input for doc. It
+// records embedding usage but writes nothing to the stores. Only chunks
+// beyond the replaced count, the chunks of the document being replaced,
+// are reserved against the quota. The caller must release the returned
+// quota reservation.
+func (e *Engine) process(ctx context.Context, col *collection.Collection, doc *document.Document, input *IngestInput, replaced int) (*processed, error) {
 	// Optionally load/extract text.
-	content := input.Content
-	if e.loader != nil && input.SourceType != "" && e.loader.Supports(input.SourceType) {
-		result, loadErr := e.loader.Load(ctx, strings.NewReader(content))
-		if loadErr != nil {
-			return e.failIngest(ctx, doc, input.CollectionID, loadErr)
-		}
-		content = result.Content
+	loaded, err := e.loadContent(ctx, input)
+	if err != nil {
+		return nil, err
 	}
+	applyLoadMetadata(doc, loaded)
 
 	// Chunk the content.
-	chunkOpts := &chunker.Options{
-		ChunkSize:    col.ChunkSize,
-		ChunkOverlap: col.ChunkOverlap,
-		Strategy:     col.ChunkStrategy,
-	}
-	chunkResults, err := e.chunker.Chunk(ctx, content, chunkOpts)
+	chunkResults, err := e.chunkContent(ctx, col, loaded.Content)
 	if err != nil {
-		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("chunk: %w", err))
+		return nil, fmt.Errorf("chunk: %w", err)
+	}
+	annotatePages(chunkResults, loaded.Metadata)
+	if err := e.enrichChunks(ctx, col, doc, chunkResults); err != nil {
+		return nil, fmt.Errorf("enrich: %w", err)
 	}
 
-	// Build chunk entities.
-	chunks := make([]*chunk.Chunk, len(chunkResults))
-	for i, cr := range chunkResults {
-		chunks[i] = &chunk.Chunk{
+	// Build chunk entities. Parents from hierarchical chunkers are stored
+	// for context; only chunks without children are embedded.
+	now := time.Now().UTC()
+	newChunk := func(cr chunker.ChunkResult, parentID string) *chunk.Chunk {
+		return &chunk.Chunk{
 			ID:           id.NewChunkID(),
 			DocumentID:   doc.ID,
-			CollectionID: input.CollectionID,
-			TenantID:     tenantID,
+			CollectionID: col.ID,
+			TenantID:     doc.TenantID,
 			Content:      cr.Content,
 			Index:        cr.Index,
 			StartOffset:  cr.StartOffset,
 			EndOffset:    cr.EndOffset,
 			TokenCount:   cr.TokenCount,
 			Metadata:     cr.Metadata,
-			CreatedAt:    time.Now().UTC(),
+			ParentID:     parentID,
+			CreatedAt:    now,
+		}
+	}
+	var chunks, embedded []*chunk.Chunk
+	for _, cr := range chunkResults {
+		ch := newChunk(cr, "")
+		chunks = append(chunks, ch)
+		if len(cr.Children) == 0 {
+			embedded = append(embedded, ch)
+			continue
+		}
+		for _, child := range cr.Children {
+			cc := newChunk(child, ch.ID.String())
+			chunks = append(chunks, cc)
+			embedded = append(embedded, cc)
 		}
 	}
 
 	e.extensions.EmitIngestChunked(ctx, chunks)
 
-	// Embed the chunks.
-	texts := make([]string, len(chunks))
-	for i, ch := range chunks {
-		texts[i] = ch.Content
+	// Embed the chunks, and any summaries and questions generated for them.
+	inputs := embedInputs(embedded)
+	texts := make([]string, len(inputs))
+	for i, in := range inputs {
+		texts[i] = in.text
 	}
 
-	embedResults, err := e.embedder.Embed(ctx, texts)
+	reqs := []quotaRequest{{quota.ResourceEmbeddingTokens, e.estimateTokens(texts)}}
+	if grown := int64(len(chunks) - replaced); grown > 0 {
+		reqs = append(reqs, quotaRequest{quota.ResourceChunks, grown})
+	}
+	held, err := e.checkQuota(ctx, doc.TenantID, reqs...)
 	if err != nil {
-		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("embed: %w", err))
+		return nil, err
 	}
 
+	embedResults, err := e.embedder.Embed(ctx, texts)
+	if err != nil {
+		held.release()
+		return nil, fmt.Errorf("embed: %w", err)
+	}
+	held.keep(quota.ResourceEmbeddingTokens)
+	e.recordEmbedding(ctx, usageScope{
+		tenantID:     doc.TenantID,
+		appID:        col.AppID,
+		collectionID: col.ID,
+		operation:    usage.OperationIngest,
+		model:        col.EmbeddingModel,
+	}, texts, embedResults)
+
 	e.extensions.EmitIngestEmbedded(ctx, chunks)
 
 	// Build vector entries.
-	entries := make([]vectorstore.Entry, len(chunks))
-	for i, ch := range chunks {
-		meta := map[string]string{
-			"collection_id": input.CollectionID.String(),
-			"document_id":   doc.ID.String(),
-			"tenant_id":     tenantID,
-			"chunk_index":   fmt.Sprintf("%d", ch.Index),
-		}
-		for k, v := range ch.Metadata {
-			meta[k] = v
-		}
+	entries := make([]vectorstore.Entry, len(inputs))
+	for i, in := range inputs {
+		entries[i] = in.entry(embedResults[i].Vector)
+	}
+	return &processed{chunks: chunks, entries: entries, quota: held}, nil
+}
 
-		entries[i] = vectorstore.Entry{
-			ID:       ch.ID.String(),
-			Vector:   embedResults[i].Vector,
-			Content:  ch.Content,
-			Metadata: meta,
+// IngestBatch ingests multiple documents into a collection.
+func (e *Engine) IngestBatch(ctx context.Context, inputs []*IngestInput) ([]*IngestResult, error) {
+	results := make([]*IngestResult, 0, len(inputs))
+	for _, input := range inputs {
+		result, err := e.Ingest(ctx, input)
+		if err != nil {
+			return results, err
 		}
+		results = append(results, result)
 	}
+	return results, nil
+}
 
-	// Store chunks in metadata store.
-	if err := e.store.CreateChunkBatch(ctx, chunks); err != nil {
-		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("store chunks: %w", err))
+// ReplaceDocument re-ingests a document in place: input is loaded,
+// chunked and embedded as by Ingest, then swapped in for the document's
+// chunks and vectors. The document keeps its ID, and keeps its old content
+// if processing fails. Replacing a ready document with the same content,
+// title and source does nothing. input.CollectionID is ignored.
+func (e *Engine) ReplaceDocument(ctx context.Context, docID id.DocumentID, input *IngestInput) (*IngestResult, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+	if e.embedder == nil {
+		return nil, weave.ErrNoEmbedder
+	}
+	if e.vectorStore == nil {
+		return nil, weave.ErrNoVectorStore
+	}
+	if e.chunker == nil {
+		return nil, weave.ErrNoChunker
+	}
+	if input.Content == "" {
+		return nil, weave.ErrEmptyContent
 	}
 
-	// Upsert vector entries.
-	if err := e.vectorStore.Upsert(ctx, entries); err != nil {
-		return e.failIngest(ctx, doc, input.CollectionID, fmt.Errorf("upsert vectors: %w", err))
+	start := time.Now()
+	current, err := e.store.GetDocument(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Trashed() {
+		return nil, weave.ErrDocumentTrashed
+	}
+	col, err := e.store.GetCollection(ctx, current.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if col.Trashed() {
+		return nil, weave.ErrCollectionTrashed
 	}
 
-	// Mark document as ready.
-	doc.State = document.StateReady
-	doc.ChunkCount = len(chunks)
-	_ = e.store.UpdateDocument(ctx, doc) //nolint:errcheck // best-effort status update
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(input.Content)))
+	if current.State == document.StateReady && current.ContentHash == hash &&
+		(input.Title == "" || input.Title == current.Title) && input.Source == current.Source {
+		return &IngestResult{
+			DocumentID: current.ID,
+			ChunkCount: current.ChunkCount,
+			State:      document.StateReady,
+		}, nil
+	}
+
+	if grown := int64(len(input.Content) - current.ContentLength); grown > 0 {
+		held, err := e.checkQuota(ctx, current.TenantID,
+			quotaRequest{quota.ResourceStoredBytes, grown},
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer held.release()
+	}
+
+	// Work on a copy so the stored document is untouched until the new
+	// chunks are ready.
+	doc := *current
+	doc.Title = input.Title
+	doc.Source = input.Source
+	doc.SourceType = input.SourceType
+	doc.ContentHash = hash
+	doc.ContentLength = len(input.Content)
+	doc.Metadata = input.Metadata
+	doc.Error = ""
+
+	e.extensions.EmitIngestStarted(ctx, doc.CollectionID, []*document.Document{&doc})
+	out, err := e.process(ctx, col, &doc, input, current.ChunkCount)
+	if err != nil {
+		e.extensions.EmitIngestFailed(ctx, doc.CollectionID, err)
+		return nil, fmt.Errorf("weave: ingest failed: %w", err)
+	}
+	defer out.quota.release()
+	// The old chunks and content are replaced rather than added to, so the
+	// tenant's usage is reloaded once the swap is done.
+	defer e.invalidateUsage(doc.TenantID)
 
-	elapsed := time.Since(start)
-	e.extensions.EmitIngestCompleted(ctx, input.CollectionID, 1, len(chunks), elapsed)
+	// Swap the chunks and vectors.
+	if err := e.store.DeleteChunksByDocument(ctx, doc.ID); err != nil {
+		return nil, fmt.Errorf("weave: delete replaced chunks: %w", err)
+	}
+	if err := e.vectorStore.DeleteByMetadata(ctx, map[string]string{"document_id": doc.ID.String()}); err != nil {
+		return nil, fmt.Errorf("weave: delete replaced vectors: %w", err)
+	}
+	if err := e.store.CreateChunkBatch(ctx, out.chunks); err != nil {
+		return e.failIngest(ctx, &doc, doc.CollectionID, fmt.Errorf("store chunks: %w", err))
+	}
+	if err := e.vectorStore.Upsert(ctx, out.entries); err != nil {
+		return e.failIngest(ctx, &doc, doc.CollectionID, fmt.Errorf("upsert vectors: %w", err))
+	}
 
+	doc.State = document.StateReady
+	doc.ChunkCount = len(out.chunks)
+	if err := e.store.UpdateDocument(ctx, &doc); err != nil {
+		return nil, fmt.Errorf("weave: update document: %w", err)
+	}
+
+	e.extensions.EmitIngestCompleted(ctx, doc.CollectionID, 1, len(out.chunks), time.Since(start))
 	return &IngestResult{
 		DocumentID: doc.ID,
-		ChunkCount: len(chunks),
+		ChunkCount: len(out.chunks),
 		State:      document.StateReady,
 	}, nil
 }
 
-// IngestBatch ingests multiple documents into a collection.
-func (e *Engine) IngestBatch(ctx context.Context, inputs []*IngestInput) ([]*IngestResult, error) {
-	results := make([]*IngestResult, 0, len(inputs))
-	for _, input := range inputs {
-		result, err := e.Ingest(ctx, input)
-		if err != nil {
-			return results, err
+// loadContent extracts text from the input with the configured loader
+// when it supports the input's source type, and returns the raw content
+// otherwise.
+func (e *Engine) loadContent(ctx context.Context, input *IngestInput) (*loader.LoadResult, error) {
+	if e.loader == nil || input.SourceType == "" || !e.loader.Supports(input.SourceType) {
+		return &loader.LoadResult{Content: input.Content}, nil
+	}
+	return e.loader.Load(ctx, strings.NewReader(input.Content))
+}
+
+// applyLoadMetadata copies the metadata a loader extracted into the
+// document, without overriding metadata supplied with the input. A title
+// found by the loader is used when the input has none.
+func applyLoadMetadata(doc *document.Document, loaded *loader.LoadResult) {
+	if len(loaded.Metadata) == 0 {
+		return
+	}
+	meta := make(map[string]string, len(doc.Metadata)+len(loaded.Metadata))
+	for k, v := range loaded.Metadata {
+		if k == loader.MetaPageOffsets || k == loader.MetaChapterOffsets || k == loader.MetaChapterTitles {
+			continue
 		}
-		results = append(results, result)
+		meta[k] = v
 	}
-	return results, nil
+	for k, v := range doc.Metadata {
+		meta[k] = v
+	}
+	doc.Metadata = meta
+	if doc.Title == "" {
+		doc.Title = loaded.Metadata["title"]
+	}
+}
+
+// annotatePages records where each chunk falls in a paged document or a
+// book: "page" and "page_end" from the loader's page offsets, and
+// "chapter", "chapter_end" and "chapter_title" from its chapter offsets.
+func annotatePages(chunks []chunker.ChunkResult, meta map[string]string) {
+	annotateSections(chunks, "page", loader.PageOffsets(meta), nil)
+	annotateSections(chunks, "chapter", loader.ChapterOffsets(meta), loader.ChapterTitles(meta))
+}
+
+// annotateSections records, under key, the section each chunk starts on,
+// and under key+"_end" the section it ends on when that differs, given the
+// offsets at which the sections start. When the sections are titled, the
+// start section's title is recorded under key+"_title".
+func annotateSections(chunks []chunker.ChunkResult, key string, offsets []int, titles []string) {
+	if len(offsets) == 0 {
+		return
+	}
+	for i := range chunks {
+		ch := &chunks[i]
+		if ch.Metadata == nil {
+			ch.Metadata = make(map[string]string)
+		}
+		start := loader.PageAt(offsets, ch.StartOffset)
+		ch.Metadata[key] = strconv.Itoa(start)
+		if start > 0 && start <= len(titles) && titles[start-1] != "" {
+			ch.Metadata[key+"_title"] = titles[start-1]
+		}
+		if end := loader.PageAt(offsets, max(ch.EndOffset-1, ch.StartOffset)); end != start {
+			ch.Metadata[key+"_end"] = strconv.Itoa(end)
+		}
+		annotateSections(ch.Children, key, offsets, titles)
+	}
+}
+
+// vectorEntry builds the vector store entry for an embedded chunk. Child
+// chunks carry their parent's ID so retrievers can expand them.
+func vectorEntry(ch *chunk.Chunk, vector []float32) vectorstore.Entry {
+	meta := map[string]string{
+		"collection_id": ch.CollectionID.String(),
+		"document_id":   ch.DocumentID.String(),
+		"tenant_id":     ch.TenantID,
+		"chunk_id":      ch.ID.String(),
+		"chunk_index":   fmt.Sprintf("%d", ch.Index),
+	}
+	if ch.ParentID != "" {
+		meta["parent_id"] = ch.ParentID
+	}
+	for k, v := range ch.Metadata {
+		meta[k] = v
+	}
+	return vectorstore.Entry{
+		ID:       ch.ID.String(),
+		Vector:   vector,
+		Content:  ch.Content,
+		Metadata: meta,
+	}
+}
+
+// embedInput is a text to embed for a chunk: the chunk itself, or a
+// summary or question generated for it by an enricher.QuestionEnricher.
+type embedInput struct {
+	chunk   *chunk.Chunk
+	text    string
+	variant string // "", "summary" or "question-<n>"
+}
+
+// embedInputs lists the texts to embed for chunks.
+func embedInputs(chunks []*chunk.Chunk) []embedInput {
+	inputs := make([]embedInput, 0, len(chunks))
+	for _, ch := range chunks {
+		inputs = append(inputs, embedInput{chunk: ch, text: chunker.EmbedText(ch.Content, ch.Metadata)})
+		if summary := ch.Metadata[enricher.MetaSummary]; summary != "" {
+			inputs = append(inputs, embedInput{chunk: ch, text: summary, variant: "summary"})
+		}
+		if questions := ch.Metadata[enricher.MetaQuestions]; questions != "" {
+			for i, q := range strings.Split(questions, "\n") {
+				inputs = append(inputs, embedInput{chunk: ch, text: q, variant: fmt.Sprintf("question-%d", i+1)})
+			}
+		}
+	}
+	return inputs
+}
+
+// entry builds the vector entry for the input. Summary and question
+// entries hold the chunk's content and chunk_id, so a hit on them
+// retrieves the chunk itself.
+func (in embedInput) entry(vector []float32) vectorstore.Entry {
+	entry := vectorEntry(in.chunk, vector)
+	if in.variant != "" {
+		entry.ID += "#" + in.variant
+		entry.Metadata["variant"] = in.variant
+	}
+	return entry
+}
+
+// embeddable drops parent chunks, which are stored for context but not
+// embedded.
+func embeddable(chunks []*chunk.Chunk) []*chunk.Chunk {
+	parents := make(map[string]bool)
+	for _, ch := range chunks {
+		if ch.ParentID != "" {
+			parents[ch.ParentID] = true
+		}
+	}
+	if len(parents) == 0 {
+		return chunks
+	}
+	out := make([]*chunk.Chunk, 0, len(chunks)-len(parents))
+	for _, ch := range chunks {
+		if !parents[ch.ID.String()] {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// chunkContent splits content using the collection's chunk settings.
+func (e *Engine) chunkContent(ctx context.Context, col *collection.Collection, content string) ([]chunker.ChunkResult, error) {
+	return e.chunker.Chunk(ctx, content, &chunker.Options{
+		ChunkSize:    col.ChunkSize,
+		ChunkOverlap: col.ChunkOverlap,
+		Strategy:     col.ChunkStrategy,
+		Language:     col.Language,
+		Tokenizer:    e.tokenizer,
+	})
+}
+
+// enrichChunks adds document context to the chunks before they are
+// embedded: first the collection's EmbedTemplate, then the engine's
+// enricher.
+func (e *Engine) enrichChunks(ctx context.Context, col *collection.Collection, doc *document.Document, chunks []chunker.ChunkResult) error {
+	if err := e.applyTemplate(ctx, col, doc, chunks); err != nil {
+		return err
+	}
+	if e.enricher == nil {
+		return nil
+	}
+	return e.enricher.Enrich(ctx, doc, chunks)
+}
+
+// applyTemplate renders the collection's EmbedTemplate into the chunks.
+func (e *Engine) applyTemplate(ctx context.Context, col *collection.Collection, doc *document.Document, chunks []chunker.ChunkResult) error {
+	if col.EmbedTemplate == "" {
+		return nil
+	}
+	tmpl, err := enricher.NewTemplateEnricher(col.EmbedTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Enrich(ctx, doc, chunks)
 }
 
 // failIngest marks a document as failed and emits the failure event.
@@ -459,6 +842,7 @@ type RetrieveParams struct {
 	TopK         int     `json:"top_k"`
 	MinScore     float64 `json:"min_score"`
 	Strategy     string  `json:"strategy,omitempty"`
+	Parents      bool    `json:"parents,omitempty"`
 }
 
 // WithCollection restricts retrieval to a specific collection.
@@ -481,6 +865,14 @@ func WithStrategy(strategy string) RetrieveOption {
 	return func(p *RetrieveParams) { p.Strategy = strategy }
 }
 
+// WithParents returns the parent chunks of matching child chunks, for
+// collections chunked with chunker.HierarchicalChunker. Siblings collapse
+// into one parent scored by its best child, as with
+// retriever.ParentRetriever. Chunks without a parent are returned as is.
+func WithParents() RetrieveOption {
+	return func(p *RetrieveParams) { p.Parents = true }
+}
+
 // WithTenantID explicitly sets the tenant for retrieval.
 func WithTenantID(tenantID string) RetrieveOption {
 	return func(p *RetrieveParams) { p.TenantID = tenantID }
@@ -515,73 +907,100 @@ func (e *Engine) Retrieve(ctx context.Context, query string, opts ...RetrieveOpt
 
 	e.extensions.EmitRetrievalStarted(ctx, colID, query)
 
-	// Use the plugged-in retriever if available.
-	if e.retriever != nil {
-		filter := map[string]string{}
-		if params.CollectionID != "" {
-			filter["collection_id"] = params.CollectionID
-		}
-		if params.TenantID != "" {
-			filter["tenant_id"] = params.TenantID
-		}
-
-		results, err := e.retriever.Retrieve(ctx, query, &retriever.Options{
-			CollectionID: params.CollectionID,
-			TenantKey:    params.TenantID,
-			TopK:         params.TopK,
-			MinScore:     params.MinScore,
-			Filter:       filter,
-		})
-		if err != nil {
-			e.extensions.EmitRetrievalFailed(ctx, colID, err)
-			return nil, fmt.Errorf("weave: retrieve: %w", err)
-		}
-
-		scored := make([]ScoredChunk, len(results))
-		for i, r := range results {
-			scored[i] = ScoredChunk{Chunk: r.Chunk, Score: r.Score}
-		}
-
-		elapsed := time.Since(start)
-		e.extensions.EmitRetrievalCompleted(ctx, colID, len(scored), elapsed)
-		return scored, nil
-	}
-
-	// Fallback: embed query and search vector store directly.
-	embedResults, err := e.embedder.Embed(ctx, []string{query})
+	trash, err := e.loadTrashFilter(ctx, newTrashScope(params.CollectionID, params.TenantID))
 	if err != nil {
 		e.extensions.EmitRetrievalFailed(ctx, colID, err)
-		return nil, fmt.Errorf("weave: embed query: %w", err)
+		return nil, err
 	}
 
-	searchFilter := map[string]string{}
+	filter := map[string]string{}
 	if params.CollectionID != "" {
-		searchFilter["collection_id"] = params.CollectionID
+		filter["collection_id"] = params.CollectionID
 	}
 	if params.TenantID != "" {
-		searchFilter["tenant_id"] = params.TenantID
+		filter["tenant_id"] = params.TenantID
 	}
 
-	searchResults, err := e.vectorStore.Search(ctx, embedResults[0].Vector, &vectorstore.SearchOptions{
-		TopK:      params.TopK,
-		Filter:    searchFilter,
-		TenantKey: params.TenantID,
-		MinScore:  params.MinScore,
-	})
-	if err != nil {
-		e.extensions.EmitRetrievalFailed(ctx, colID, err)
-		return nil, fmt.Errorf("weave: search: %w", err)
+	// search returns up to topK raw hits from the plugged-in retriever, or
+	// else from the vector store with the query embedded once.
+	var search searchFunc
+	if e.retriever != nil {
+		search = func(topK int) ([]ScoredChunk, error) {
+			results, err := e.retriever.Retrieve(ctx, query, &retriever.Options{
+				CollectionID: params.CollectionID,
+				TenantKey:    params.TenantID,
+				TopK:         topK,
+				MinScore:     params.MinScore,
+				Filter:       filter,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("weave: retrieve: %w", err)
+			}
+			// The retriever embeds the query itself, so its cost is estimated.
+			e.recordEmbedding(ctx, e.queryScope(ctx, params.TenantID, colID), []string{query}, nil)
+
+			scored := make([]ScoredChunk, len(results))
+			for i, r := range results {
+				scored[i] = ScoredChunk{Chunk: r.Chunk, Score: r.Score}
+			}
+			return scored, nil
+		}
+	} else {
+		embedResults, err := e.embedder.Embed(ctx, []string{query})
+		if err != nil {
+			e.extensions.EmitRetrievalFailed(ctx, colID, err)
+			return nil, fmt.Errorf("weave: embed query: %w", err)
+		}
+		e.recordEmbedding(ctx, e.queryScope(ctx, params.TenantID, colID), []string{query}, embedResults)
+
+		search = func(topK int) ([]ScoredChunk, error) {
+			searchResults, err := e.vectorStore.Search(ctx, embedResults[0].Vector, &vectorstore.SearchOptions{
+				TopK:      topK,
+				Filter:    filter,
+				TenantKey: params.TenantID,
+				MinScore:  params.MinScore,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("weave: search: %w", err)
+			}
+			scored := make([]ScoredChunk, len(searchResults))
+			for i, sr := range searchResults {
+				scored[i] = ScoredChunk{
+					Chunk: &chunk.Chunk{
+						Content:  sr.Content,
+						Metadata: sr.Metadata,
+					},
+					Score: sr.Score,
+				}
+			}
+			return scored, nil
+		}
 	}
 
-	scored := make([]ScoredChunk, len(searchResults))
-	for i, sr := range searchResults {
-		scored[i] = ScoredChunk{
-			Chunk: &chunk.Chunk{
-				Content:  sr.Content,
-				Metadata: sr.Metadata,
-			},
-			Score: sr.Score,
+	if params.Parents {
+		if e.store == nil {
+			e.extensions.EmitRetrievalFailed(ctx, colID, weave.ErrNoStore)
+			return nil, weave.ErrNoStore
 		}
+		search = e.parentSearch(ctx, query, search)
+	}
+
+	// Over-fetch so that extra hits on a chunk through its generated
+	// summary or questions can be dropped without shrinking the result
+	// set. When trashed hits still leave it short, search again deeper.
+	searchTopK := 2 * params.TopK * enricher.EntriesPerChunk(e.enricher)
+	var scored []ScoredChunk
+	for round := 0; ; round++ {
+		raw, err := search(searchTopK)
+		if err != nil {
+			e.extensions.EmitRetrievalFailed(ctx, colID, err)
+			return nil, err
+		}
+		scored = trash.apply(dedupeChunks(raw), params.TopK)
+		if len(scored) >= params.TopK || len(raw) < searchTopK || round == maxSearchRounds-1 {
+			break
+		}
+		searchTopK *= 4
 	}
 
 	elapsed := time.Since(start)
@@ -589,6 +1008,43 @@ func (e *Engine) Retrieve(ctx context.Context, query string, opts ...RetrieveOpt
 	return scored, nil
 }
 
+// maxSearchRounds caps how many times Retrieve deepens a search whose hits
+// were mostly trashed.
+const maxSearchRounds = 3
+
+// searchFunc is a Retrieve search returning up to topK raw hits.
+type searchFunc func(topK int) ([]ScoredChunk, error)
+
+// Retrieve implements retriever.Retriever.
+func (f searchFunc) Retrieve(_ context.Context, _ string, opts *retriever.Options) ([]retriever.Result, error) {
+	scored, err := f(opts.TopK)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]retriever.Result, len(scored))
+	for i, sc := range scored {
+		results[i] = retriever.Result{Chunk: sc.Chunk, Score: sc.Score}
+	}
+	return results, nil
+}
+
+// parentSearch wraps search so that it returns the parents of the child
+// chunks it finds.
+func (e *Engine) parentSearch(ctx context.Context, query string, search searchFunc) searchFunc {
+	parents := retriever.NewParentRetriever(search, e.store)
+	return func(topK int) ([]ScoredChunk, error) {
+		results, err := parents.Retrieve(ctx, query, &retriever.Options{TopK: topK})
+		if err != nil {
+			return nil, fmt.Errorf("weave: retrieve parents: %w", err)
+		}
+		scored := make([]ScoredChunk, len(results))
+		for i, r := range results {
+			scored[i] = ScoredChunk{Chunk: r.Chunk, Score: r.Score}
+		}
+		return scored, nil
+	}
+}
+
 // ──────────────────────────────────────────────────
 // Document operations
 // ──────────────────────────────────────────────────
@@ -609,33 +1065,27 @@ func (e *Engine) ListDocuments(ctx context.Context, filter *document.ListFilter)
 	return e.store.ListDocuments(ctx, filter)
 }
 
-// DeleteDocument removes a document and its chunks from both stores.
+// DeleteDocument moves a document to the trash. Its chunks are kept but
+// excluded from retrieval until the document is restored or purged.
 func (e *Engine) DeleteDocument(ctx context.Context, docID id.DocumentID) error {
 	if e.store == nil {
 		return weave.ErrNoStore
 	}
 
-	// Delete vector entries for the document.
-	if e.vectorStore != nil {
-		if err := e.vectorStore.DeleteByMetadata(ctx, map[string]string{
-			"document_id": docID.String(),
-		}); err != nil {
-			e.logger.Warn("failed to delete vector entries for document",
-				log.String("document_id", docID.String()),
-				log.String("error", err.Error()),
-			)
-		}
+	doc, err := e.store.GetDocument(ctx, docID)
+	if err != nil {
+		return err
 	}
-
-	// Delete chunks.
-	if err := e.store.DeleteChunksByDocument(ctx, docID); err != nil {
-		return fmt.Errorf("weave: delete chunks: %w", err)
+	if doc.Trashed() {
+		return weave.ErrDocumentTrashed
 	}
 
-	// Delete the document.
-	if err := e.store.DeleteDocument(ctx, docID); err != nil {
-		return err
+	now := time.Now().UTC()
+	doc.DeletedAt = &now
+	if err := e.store.UpdateDocument(ctx, doc); err != nil {
+		return fmt.Errorf("weave: trash document: %w", err)
 	}
+	e.invalidateTrash()
 
 	e.extensions.EmitDocumentDeleted(ctx, docID)
 	return nil
@@ -657,6 +1107,14 @@ func (e *Engine) ReindexCollection(ctx context.Context, colID id.CollectionID) e
 		return weave.ErrNoVectorStore
 	}
 
+	col, err := e.store.GetCollection(ctx, colID)
+	if err != nil {
+		return err
+	}
+	if col.Trashed() {
+		return weave.ErrCollectionTrashed
+	}
+
 	start := time.Now()
 	e.extensions.EmitReindexStarted(ctx, colID)
 
@@ -683,37 +1141,32 @@ func (e *Engine) ReindexCollection(ctx context.Context, colID id.CollectionID) e
 			return fmt.Errorf("weave: list chunks for reindex: %w", err)
 		}
 
+		chunks = embeddable(chunks)
 		if len(chunks) == 0 {
 			continue
 		}
 
-		texts := make([]string, len(chunks))
-		for i, ch := range chunks {
-			texts[i] = ch.Content
+		inputs := embedInputs(chunks)
+		texts := make([]string, len(inputs))
+		for i, in := range inputs {
+			texts[i] = in.text
 		}
 
 		embedResults, err := e.embedder.Embed(ctx, texts)
 		if err != nil {
 			return fmt.Errorf("weave: embed for reindex: %w", err)
 		}
-
-		entries := make([]vectorstore.Entry, len(chunks))
-		for i, ch := range chunks {
-			meta := map[string]string{
-				"collection_id": colID.String(),
-				"document_id":   doc.ID.String(),
-				"tenant_id":     ch.TenantID,
-				"chunk_index":   fmt.Sprintf("%d", ch.Index),
-			}
-			for k, v := range ch.Metadata {
-				meta[k] = v
-			}
-			entries[i] = vectorstore.Entry{
-				ID:       ch.ID.String(),
-				Vector:   embedResults[i].Vector,
-				Content:  ch.Content,
-				Metadata: meta,
-			}
+		e.recordEmbedding(ctx, usageScope{
+			tenantID:     doc.TenantID,
+			appID:        col.AppID,
+			collectionID: colID,
+			operation:    usage.OperationReindex,
+			model:        col.EmbeddingModel,
+		}, texts, embedResults)
+
+		entries := make([]vectorstore.Entry, len(inputs))
+		for i, in := range inputs {
+			entries[i] = in.entry(embedResults[i].Vector)
 		}
 
 		if err := e.vectorStore.Upsert(ctx, entries); err != nil {
@@ -783,6 +1236,26 @@ func (e *Engine) HybridSearch(ctx context.Context, query string, params *HybridS
 	)
 }
 
+// dedupeChunks keeps the best-scoring hit per chunk_id, so a chunk matched
+// through several of its vector entries is returned once. Scored must be
+// sorted by score descending.
+func dedupeChunks(scored []ScoredChunk) []ScoredChunk {
+	seen := make(map[string]bool, len(scored))
+	kept := scored[:0]
+	for _, sc := range scored {
+		if sc.Chunk != nil {
+			if key := sc.Chunk.Metadata["chunk_id"]; key != "" {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+		}
+		kept = append(kept, sc)
+	}
+	return kept
+}
+
 // sortScoredChunks sorts scored chunks by score descending.
 func sortScoredChunks(chunks []ScoredChunk) {
 	for i := 1; i < len(chunks); i++ {
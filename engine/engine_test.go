@@ -0,0 +1,156 @@
+package engine_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/loader"
+	"github.com/xraph/weave/store/memory"
+	vsmemory "github.com/xraph/weave/vectorstore/memory"
+)
+
+// lengthEmbedder embeds a text as its length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) Embed(_ context.Context, texts []string) ([]embedder.EmbedResult, error) {
+	out := make([]embedder.EmbedResult, len(texts))
+	for i, text := range texts {
+		out[i] = embedder.EmbedResult{Vector: []float32{float32(len(text)), 1}}
+	}
+	return out, nil
+}
+
+func (lengthEmbedder) Dimensions() int { return 2 }
+
+// splitChunker makes one chunk per piece of text between separators.
+type splitChunker struct{ sep string }
+
+func (c splitChunker) Chunk(_ context.Context, text string, _ *chunker.Options) ([]chunker.ChunkResult, error) {
+	var results []chunker.ChunkResult
+	offset := 0
+	for i, piece := range strings.Split(text, c.sep) {
+		results = append(results, chunker.ChunkResult{
+			Content:     piece,
+			Index:       i,
+			StartOffset: offset,
+			EndOffset:   offset + len(piece),
+		})
+		offset += len(piece) + len(c.sep)
+	}
+	return results, nil
+}
+
+// newEngine creates an engine on memory stores with one collection.
+func newEngine(t *testing.T, opts ...engine.Option) (*engine.Engine, *collection.Collection) {
+	t.Helper()
+	eng, err := engine.New(append([]engine.Option{
+		engine.WithStore(memory.New()),
+		engine.WithVectorStore(vsmemory.New()),
+		engine.WithEmbedder(lengthEmbedder{}),
+		engine.WithChunker(chunker.NewFixedChunker()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := &collection.Collection{ID: id.NewCollectionID(), Name: "docs"}
+	if err := eng.CreateCollection(context.Background(), col); err != nil {
+		t.Fatal(err)
+	}
+	return eng, col
+}
+
+// bookLoader loads its input as a book with two chapters.
+type bookLoader struct{}
+
+func (bookLoader) Load(context.Context, io.Reader) (*loader.LoadResult, error) {
+	one := "The first chapter."
+	return &loader.LoadResult{
+		Content: one + "\n\nThe second chapter.",
+		Metadata: map[string]string{
+			loader.MetaChapterOffsets: "0," + strconv.Itoa(len(one)+2),
+			loader.MetaChapterTitles:  "One\nTwo",
+		},
+	}, nil
+}
+
+func (bookLoader) Supports(mimeType string) bool { return mimeType == "application/epub+zip" }
+
+func TestIngestAnnotatesChapters(t *testing.T) {
+	eng, col := newEngine(t, engine.WithLoader(bookLoader{}))
+	ctx := context.Background()
+
+	res, err := eng.Ingest(ctx, &engine.IngestInput{
+		CollectionID: col.ID,
+		Content:      "book",
+		SourceType:   "application/epub+zip",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := eng.Store().ListChunksByDocument(ctx, res.DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(chunks))
+	}
+	meta := chunks[0].Metadata
+	if meta["chapter"] != "1" || meta["chapter_end"] != "2" || meta["chapter_title"] != "One" || meta["page"] != "" {
+		t.Errorf("chunk metadata = %v", meta)
+	}
+
+	doc, err := eng.GetDocument(ctx, res.DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Metadata[loader.MetaChapterOffsets]; ok {
+		t.Errorf("document metadata = %v", doc.Metadata)
+	}
+}
+
+func TestRetrieveWithParents(t *testing.T) {
+	ctx := context.Background()
+	eng, col := newEngine(t, engine.WithChunker(&chunker.HierarchicalChunker{
+		Parent: splitChunker{"\n\n"},
+		Child:  splitChunker{"\n"},
+	}))
+	if _, err := eng.Ingest(ctx, &engine.IngestInput{
+		CollectionID: col.ID,
+		Content:      "alpha one\nalpha two\n\nbeta one\nbeta two",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	children, err := eng.Retrieve(ctx, "query", engine.WithTopK(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 4 {
+		t.Fatalf("retrieved %d children, want 4", len(children))
+	}
+
+	parents, err := eng.Retrieve(ctx, "query", engine.WithTopK(10), engine.WithParents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range parents {
+		got = append(got, p.Chunk.Content)
+		if p.Chunk.Metadata["parent_id"] != "" {
+			t.Errorf("parent %q has parent_id", p.Chunk.Content)
+		}
+	}
+	slices.Sort(got)
+	if want := []string{"alpha one\nalpha two", "beta one\nbeta two"}; !slices.Equal(got, want) {
+		t.Errorf("retrieved parents %q, want %q", got, want)
+	}
+}
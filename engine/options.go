@@ -7,8 +7,10 @@ import (
 	"github.com/xraph/weave"
 	"github.com/xraph/weave/chunker"
 	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/enricher"
 	"github.com/xraph/weave/ext"
 	"github.com/xraph/weave/loader"
+	"github.com/xraph/weave/quota"
 	"github.com/xraph/weave/retriever"
 	"github.com/xraph/weave/store"
 	"github.com/xraph/weave/vectorstore"
@@ -49,6 +51,26 @@ func WithChunker(c chunker.Chunker) Option {
 	}
 }
 
+// WithTokenizer sets the token counter used to size chunks and to count
+// embedding tokens, e.g. a *tokenizer.Encoding. Without one, tokens are
+// approximated as four characters.
+func WithTokenizer(tc chunker.TokenCounter) Option {
+	return func(e *Engine) error {
+		e.tokenizer = tc
+		return nil
+	}
+}
+
+// WithEnricher sets the enricher that adds context to chunks before
+// embedding, e.g. an enricher.QuestionEnricher. It runs after the
+// collection's EmbedTemplate; combine several with enricher.Chain.
+func WithEnricher(enr enricher.Enricher) Option {
+	return func(e *Engine) error {
+		e.enricher = enr
+		return nil
+	}
+}
+
 // WithLoader sets the default document loader.
 func WithLoader(l loader.Loader) Option {
 	return func(e *Engine) error {
@@ -57,6 +79,15 @@ func WithLoader(l loader.Loader) Option {
 	}
 }
 
+// WithConnector registers a connector that the engine runs from Start
+// until Stop.
+func WithConnector(c Connector) Option {
+	return func(e *Engine) error {
+		e.connectors = append(e.connectors, c)
+		return nil
+	}
+}
+
 // WithRetriever sets the retriever.
 func WithRetriever(r retriever.Retriever) Option {
 	return func(e *Engine) error {
@@ -88,3 +119,12 @@ func WithConfig(cfg weave.Config) Option {
 		return nil
 	}
 }
+
+// WithDefaultQuota sets the limits applied to tenants without their own
+// quota. The zero value leaves tenants unlimited.
+func WithDefaultQuota(limits quota.Limits) Option {
+	return func(e *Engine) error {
+		e.defaultQuota = limits
+		return nil
+	}
+}
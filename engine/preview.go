@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/chunk"
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/embedder"
+)
+
+// ──────────────────────────────────────────────────
+// Ingest preview
+// ──────────────────────────────────────────────────
+
+// PreviewResult describes how a document would be split by Ingest.
+type PreviewResult struct {
+	// Content is the text after loading; chunk offsets index into it.
+	Content    string                `json:"content"`
+	Chunks     []chunker.ChunkResult `json:"chunks"`
+	ChunkCount int                   `json:"chunk_count"`
+	// VectorCount is the number of vector entries: one per embedded
+	// chunk, plus one per summary and question an enricher generated.
+	VectorCount    int    `json:"vector_count"`
+	TotalTokens    int64  `json:"total_tokens"`
+	EmbeddingModel string `json:"embedding_model"`
+	// EstimatedCost is the embedding cost in USD, or nil when the model's
+	// price is unknown.
+	EstimatedCost *float64 `json:"estimated_cost,omitempty"`
+	// EnricherSkipped is set when the engine has an enricher that the
+	// preview did not run, so its output is missing from the counts.
+	EnricherSkipped bool `json:"enricher_skipped,omitempty"`
+}
+
+// PreviewOption configures PreviewIngest.
+type PreviewOption func(*previewParams)
+
+type previewParams struct {
+	enrich bool
+}
+
+// WithPreviewEnricher runs the engine's enricher in the preview, so the
+// summaries and questions it generates are shown and counted. It is off by
+// default because enrichers such as enricher.QuestionEnricher make paid
+// language model calls.
+func WithPreviewEnricher() PreviewOption {
+	return func(p *previewParams) { p.enrich = true }
+}
+
+// PreviewIngest runs the loader and chunker with the collection's settings
+// and reports the resulting chunks and estimated embedding cost. Nothing
+// is written to the metadata store, and neither the embedder nor the
+// vector store is called. The collection's EmbedTemplate is applied, but
+// the engine's enricher only runs with WithPreviewEnricher.
+func (e *Engine) PreviewIngest(ctx context.Context, input *IngestInput, opts ...PreviewOption) (*PreviewResult, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+	if e.chunker == nil {
+		return nil, weave.ErrNoChunker
+	}
+	if input.Content == "" {
+		return nil, weave.ErrEmptyContent
+	}
+	var params previewParams
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	col, err := e.store.GetCollection(ctx, input.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if col.Trashed() {
+		return nil, weave.ErrCollectionTrashed
+	}
+
+	loaded, err := e.loadContent(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("weave: preview load: %w", err)
+	}
+	content := loaded.Content
+	chunks, err := e.chunkContent(ctx, col, content)
+	if err != nil {
+		return nil, fmt.Errorf("weave: preview chunk: %w", err)
+	}
+	annotatePages(chunks, loaded.Metadata)
+	doc := &document.Document{
+		CollectionID: col.ID,
+		Title:        input.Title,
+		Source:       input.Source,
+		SourceType:   input.SourceType,
+		Metadata:     input.Metadata,
+	}
+	applyLoadMetadata(doc, loaded)
+	enrich := e.applyTemplate
+	if params.enrich {
+		enrich = e.enrichChunks
+	}
+	if err := enrich(ctx, col, doc, chunks); err != nil {
+		return nil, fmt.Errorf("weave: preview enrich: %w", err)
+	}
+
+	result := &PreviewResult{
+		Content:         content,
+		Chunks:          chunks,
+		ChunkCount:      len(chunks),
+		EmbeddingModel:  col.EmbeddingModel,
+		EnricherSkipped: e.enricher != nil && !params.enrich,
+	}
+	// Only leaves are embedded; parents from hierarchical chunkers are
+	// stored but cost nothing.
+	var leaves []*chunk.Chunk
+	var collect func(chunks []chunker.ChunkResult)
+	collect = func(chunks []chunker.ChunkResult) {
+		for i := range chunks {
+			if chunks[i].TokenCount == 0 {
+				chunks[i].TokenCount = int(e.estimateTokens([]string{chunks[i].Content}))
+			}
+			if len(chunks[i].Children) > 0 {
+				result.ChunkCount += len(chunks[i].Children)
+				collect(chunks[i].Children)
+				continue
+			}
+			leaves = append(leaves, &chunk.Chunk{Content: chunks[i].Content, Metadata: chunks[i].Metadata})
+		}
+	}
+	collect(chunks)
+	// Count what Ingest embeds: each chunk with its prefix, and any
+	// summaries and questions.
+	inputs := embedInputs(leaves)
+	texts := make([]string, len(inputs))
+	for i, in := range inputs {
+		texts[i] = in.text
+	}
+	result.VectorCount = len(inputs)
+	result.TotalTokens = e.estimateTokens(texts)
+	if cost, ok := embedder.EstimateCost(col.EmbeddingModel, result.TotalTokens); ok {
+		result.EstimatedCost = &cost
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/enricher"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/llm"
+	"github.com/xraph/weave/tokenizer"
+	"github.com/xraph/weave/usage"
+	"github.com/xraph/weave/vectorstore"
+	vsmemory "github.com/xraph/weave/vectorstore/memory"
+)
+
+func TestPreviewSkipsEnricherUnlessAsked(t *testing.T) {
+	model := llm.NewFake(func(*llm.Request) string {
+		return "SUMMARY: A greeting.\nQUESTION: Who is greeted?\nQUESTION: What is said?"
+	})
+	questions := enricher.NewQuestionEnricher(model)
+	questions.Questions = 2
+	eng, col := newEngine(t, engine.WithEnricher(questions))
+	input := &engine.IngestInput{CollectionID: col.ID, Content: "Hello, world."}
+
+	preview, err := eng.PreviewIngest(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(model.Requests()); n != 0 {
+		t.Fatalf("preview made %d model calls", n)
+	}
+	if !preview.EnricherSkipped || preview.VectorCount != 1 {
+		t.Errorf("preview: skipped %v, %d vectors", preview.EnricherSkipped, preview.VectorCount)
+	}
+
+	enriched, err := eng.PreviewIngest(context.Background(), input, engine.WithPreviewEnricher())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The chunk, its summary and two questions.
+	if enriched.EnricherSkipped || enriched.VectorCount != 4 {
+		t.Errorf("enriched preview: skipped %v, %d vectors", enriched.EnricherSkipped, enriched.VectorCount)
+	}
+	if enriched.TotalTokens <= preview.TotalTokens {
+		t.Errorf("enriched tokens %d not above %d", enriched.TotalTokens, preview.TotalTokens)
+	}
+}
+
+// recordingVectorStore counts upserted entries.
+type recordingVectorStore struct {
+	*vsmemory.Store
+	upserted int
+}
+
+func (s *recordingVectorStore) Upsert(ctx context.Context, entries []vectorstore.Entry) error {
+	s.upserted += len(entries)
+	return s.Store.Upsert(ctx, entries)
+}
+
+// recordingEmbedder counts embedding calls.
+type recordingEmbedder struct {
+	lengthEmbedder
+	calls *int
+}
+
+func (e recordingEmbedder) Embed(ctx context.Context, texts []string) ([]embedder.EmbedResult, error) {
+	*e.calls++
+	return e.lengthEmbedder.Embed(ctx, texts)
+}
+
+func TestPreviewIngestWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	enc, err := tokenizer.Get(tokenizer.Cl100kBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := &recordingVectorStore{Store: vsmemory.New()}
+	var embeds int
+	eng, _ := newEngine(t,
+		engine.WithTokenizer(enc),
+		engine.WithChunker(chunker.NewRecursiveChunker()),
+		engine.WithVectorStore(vs),
+		engine.WithEmbedder(recordingEmbedder{calls: &embeds}),
+	)
+	col := &collection.Collection{ID: id.NewCollectionID(), Name: "preview", ChunkSize: 12, ChunkOverlap: 2}
+	if err := eng.CreateCollection(ctx, col); err != nil {
+		t.Fatal(err)
+	}
+	input := &engine.IngestInput{
+		CollectionID: col.ID,
+		Content: "  Weave splits documents into chunks. Each chunk is embedded once.\n\n" +
+			"Previews show the chunks, naïvely priced, before anything is stored. Nothing is written.",
+	}
+
+	preview, err := eng.PreviewIngest(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embeds != 0 || vs.upserted != 0 {
+		t.Errorf("preview made %d embedding calls and upserted %d vectors", embeds, vs.upserted)
+	}
+	docs, err := eng.ListDocuments(ctx, &document.ListFilter{CollectionID: col.ID})
+	if err != nil || len(docs) != 0 {
+		t.Errorf("preview stored documents: %v, %v", docs, err)
+	}
+	if summaries, _ := eng.QueryUsage(ctx, &usage.Query{}); len(summaries) != 0 {
+		t.Errorf("preview recorded usage: %+v", summaries)
+	}
+
+	if preview.ChunkCount < 2 || preview.ChunkCount != len(preview.Chunks) || preview.VectorCount != preview.ChunkCount {
+		t.Fatalf("preview has %d chunks, ChunkCount %d, VectorCount %d", len(preview.Chunks), preview.ChunkCount, preview.VectorCount)
+	}
+	var total int64
+	for i, c := range preview.Chunks {
+		if got := preview.Content[c.StartOffset:c.EndOffset]; got != c.Content {
+			t.Errorf("chunk %d offsets [%d:%d] hold %q, want %q", i, c.StartOffset, c.EndOffset, got, c.Content)
+		}
+		if want := enc.CountTokens(c.Content); c.TokenCount != want || c.TokenCount > col.ChunkSize {
+			t.Errorf("chunk %d has %d tokens, want %d within %d", i, c.TokenCount, want, col.ChunkSize)
+		}
+		total += int64(c.TokenCount)
+	}
+	if preview.TotalTokens != total {
+		t.Errorf("TotalTokens = %d, want %d", preview.TotalTokens, total)
+	}
+
+	// Ingest stores the chunks the preview showed.
+	res, err := eng.Ingest(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := eng.Store().ListChunksByDocument(ctx, res.DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != len(preview.Chunks) {
+		t.Fatalf("ingest stored %d chunks, preview showed %d", len(stored), len(preview.Chunks))
+	}
+	for i, c := range preview.Chunks {
+		s := stored[i]
+		if s.Content != c.Content || s.StartOffset != c.StartOffset || s.EndOffset != c.EndOffset || s.TokenCount != c.TokenCount {
+			t.Errorf("chunk %d: stored %+v, previewed %+v", i, s, c)
+		}
+	}
+}
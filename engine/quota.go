@@ -0,0 +1,252 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/quota"
+)
+
+// ──────────────────────────────────────────────────
+// Quotas
+// ──────────────────────────────────────────────────
+
+// TenantQuota describes the limits in effect for a tenant together with
+// its current usage.
+type TenantQuota struct {
+	TenantID string       `json:"tenant_id"`
+	Limits   quota.Limits `json:"limits"`
+	Usage    *quota.Usage `json:"usage"`
+	// Custom is true when the tenant has its own quota rather than the
+	// engine default.
+	Custom bool `json:"custom"`
+}
+
+// TenantLimits returns the limits in effect for a tenant: its own quota
+// if one is set, otherwise the engine default.
+func (e *Engine) TenantLimits(ctx context.Context, tenantID string) (quota.Limits, bool, error) {
+	if e.store == nil {
+		return quota.Limits{}, false, weave.ErrNoStore
+	}
+
+	q, err := e.store.GetQuota(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, weave.ErrQuotaNotFound) {
+			return e.defaultQuota, false, nil
+		}
+		return quota.Limits{}, false, err
+	}
+	return q.Limits, true, nil
+}
+
+// TenantUsage returns a tenant's resource usage for the current period.
+func (e *Engine) TenantUsage(ctx context.Context, tenantID string) (*quota.Usage, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+	return e.store.GetUsage(ctx, tenantID, quota.Period(time.Now()))
+}
+
+// GetTenantQuota returns the effective limits and current usage for a
+// tenant.
+func (e *Engine) GetTenantQuota(ctx context.Context, tenantID string) (*TenantQuota, error) {
+	limits, custom, err := e.TenantLimits(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := e.TenantUsage(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &TenantQuota{
+		TenantID: tenantID,
+		Limits:   limits,
+		Usage:    usage,
+		Custom:   custom,
+	}, nil
+}
+
+// ListTenantQuotas returns all tenant-specific quotas.
+func (e *Engine) ListTenantQuotas(ctx context.Context) ([]*quota.Quota, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+	return e.store.ListQuotas(ctx)
+}
+
+// SetTenantQuota creates or replaces a tenant's quota.
+func (e *Engine) SetTenantQuota(ctx context.Context, q *quota.Quota) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+	return e.store.SetQuota(ctx, q)
+}
+
+// DeleteTenantQuota removes a tenant's quota so the engine default
+// applies again.
+func (e *Engine) DeleteTenantQuota(ctx context.Context, tenantID string) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+	return e.store.DeleteQuota(ctx, tenantID)
+}
+
+// quotaRequest is an amount of a resource an operation is about to use.
+type quotaRequest struct {
+	resource quota.Resource
+	amount   int64
+}
+
+// quotaUsageTTL is how long a tenant's usage is tracked in memory before
+// it is reloaded from the store. The engine adds its own writes as it
+// makes them, so this only bounds how stale the usage of other engines
+// sharing the store can be.
+const quotaUsageTTL = 30 * time.Second
+
+// tenantLedger tracks a tenant's usage between reloads from the store,
+// together with the quota reserved by operations still in flight.
+// Checking and reserving happen under mu, so concurrent operations cannot
+// both pass a check the other's usage would have failed.
+type tenantLedger struct {
+	mu       sync.Mutex
+	usage    *quota.Usage
+	pending  map[quota.Resource]int64
+	loadedAt time.Time
+}
+
+// reservation is quota granted to an operation. It counts as used from the
+// moment it is granted; release returns whatever was not kept once the
+// operation has written it to the store.
+type reservation struct {
+	ledger *tenantLedger
+	held   map[quota.Resource]int64
+}
+
+// checkQuota reserves the requested amounts for the tenant, or returns a
+// *quota.ExceededError if any of them would take it over its limits. The
+// caller must release the reservation. It is nil for unlimited tenants.
+func (e *Engine) checkQuota(ctx context.Context, tenantID string, reqs ...quotaRequest) (*reservation, error) {
+	limits, _, err := e.TenantLimits(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("weave: load quota: %w", err)
+	}
+	if limits.Unlimited() {
+		return nil, nil
+	}
+
+	l := e.tenantLedger(tenantID)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.usage == nil || now.Sub(l.loadedAt) >= quotaUsageTTL || l.usage.Period != quota.Period(now) {
+		usage, err := e.TenantUsage(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("weave: load usage: %w", err)
+		}
+		// Usage in flight is not in the store yet.
+		for r, n := range l.pending {
+			addUsage(usage, r, n)
+		}
+		l.usage = usage
+		l.loadedAt = now
+	}
+
+	for _, req := range reqs {
+		if err := quota.Check(limits, l.usage, req.resource, req.amount); err != nil {
+			return nil, err
+		}
+	}
+	res := &reservation{ledger: l, held: make(map[quota.Resource]int64, len(reqs))}
+	for _, req := range reqs {
+		addUsage(l.usage, req.resource, req.amount)
+		l.pending[req.resource] += req.amount
+		res.held[req.resource] += req.amount
+	}
+	return res, nil
+}
+
+// tenantLedger returns the ledger for a tenant, creating it if needed.
+func (e *Engine) tenantLedger(tenantID string) *tenantLedger {
+	e.quotaMu.Lock()
+	defer e.quotaMu.Unlock()
+	if e.ledgers == nil {
+		e.ledgers = make(map[string]*tenantLedger)
+	}
+	l, ok := e.ledgers[tenantID]
+	if !ok {
+		l = &tenantLedger{pending: make(map[quota.Resource]int64)}
+		e.ledgers[tenantID] = l
+	}
+	return l
+}
+
+// invalidateUsage makes the next quota check for the tenant reload its
+// usage from the store, after the engine freed or replaced some of it.
+func (e *Engine) invalidateUsage(tenantID string) {
+	e.quotaMu.Lock()
+	l := e.ledgers[tenantID]
+	e.quotaMu.Unlock()
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.usage = nil
+}
+
+// keep marks the reserved amounts of the given resources, or of all
+// resources if none are given, as written to the store. They stay
+// counted as used.
+func (r *reservation) keep(resources ...quota.Resource) {
+	if r == nil {
+		return
+	}
+	r.ledger.mu.Lock()
+	defer r.ledger.mu.Unlock()
+	for res, n := range r.held {
+		if len(resources) > 0 && !slices.Contains(resources, res) {
+			continue
+		}
+		r.ledger.pending[res] -= n
+		delete(r.held, res)
+	}
+}
+
+// release returns the amounts that were not kept. It is safe to call
+// more than once.
+func (r *reservation) release() {
+	if r == nil {
+		return
+	}
+	r.ledger.mu.Lock()
+	defer r.ledger.mu.Unlock()
+	for res, n := range r.held {
+		r.ledger.pending[res] -= n
+		if r.ledger.usage != nil {
+			addUsage(r.ledger.usage, res, -n)
+		}
+		delete(r.held, res)
+	}
+}
+
+// addUsage adds n of a resource to usage.
+func addUsage(u *quota.Usage, r quota.Resource, n int64) {
+	switch r {
+	case quota.ResourceCollections:
+		u.Collections += n
+	case quota.ResourceDocuments:
+		u.Documents += n
+	case quota.ResourceChunks:
+		u.Chunks += n
+	case quota.ResourceStoredBytes:
+		u.StoredBytes += n
+	case quota.ResourceEmbeddingTokens:
+		u.EmbeddingTokens += n
+	}
+}
@@ -0,0 +1,125 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/quota"
+)
+
+func TestQuotaConcurrentIngest(t *testing.T) {
+	const limit = 3
+	eng, col := newEngine(t, engine.WithDefaultQuota(quota.Limits{MaxDocuments: limit}))
+	ctx := context.Background()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		ok, over int
+	)
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := eng.Ingest(ctx, &engine.IngestInput{
+				CollectionID: col.ID,
+				Content:      fmt.Sprintf("document number %d", i),
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				ok++
+			case errors.Is(err, weave.ErrQuotaExceeded):
+				over++
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ok != limit || over != 10-limit {
+		t.Errorf("ingested %d and rejected %d, want %d and %d", ok, over, limit, 10-limit)
+	}
+}
+
+func TestQuotaReleasedOnFailure(t *testing.T) {
+	eng, col := newEngine(t, engine.WithDefaultQuota(quota.Limits{MaxDocuments: 2}))
+	ctx := context.Background()
+
+	ingest := func(content string) error {
+		_, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: content})
+		return err
+	}
+	if err := ingest("first"); err != nil {
+		t.Fatal(err)
+	}
+	// A duplicate is rejected by the store and must not hold on to quota.
+	if err := ingest("first"); err == nil {
+		t.Fatal("duplicate ingest succeeded")
+	}
+	if err := ingest("second"); err != nil {
+		t.Fatalf("second document: %v", err)
+	}
+	if err := ingest("third"); !errors.Is(err, weave.ErrQuotaExceeded) {
+		t.Fatalf("third document: got %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestQuotaFreedByPurge(t *testing.T) {
+	eng, col := newEngine(t, engine.WithDefaultQuota(quota.Limits{MaxDocuments: 1}))
+	ctx := context.Background()
+
+	res, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.DeleteDocument(ctx, res.DocumentID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.PurgeDocument(ctx, res.DocumentID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: "second"}); err != nil {
+		t.Fatalf("ingest after purge: %v", err)
+	}
+}
+
+func TestQuotaReplaceReservesNetChunks(t *testing.T) {
+	eng, col := newEngine(t,
+		engine.WithDefaultQuota(quota.Limits{MaxChunks: 4}),
+		engine.WithChunker(splitChunker{"\n"}),
+	)
+	ctx := context.Background()
+
+	// lines returns content that chunks into n chunks.
+	lines := func(n int) string {
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = fmt.Sprintf("line %d of %d", i, n)
+		}
+		return strings.Join(parts, "\n")
+	}
+	res, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: lines(4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At the limit, a replacement may shrink the document and grow it
+	// back, but not past the limit.
+	for _, n := range []int{2, 4} {
+		if _, err := eng.ReplaceDocument(ctx, res.DocumentID, &engine.IngestInput{Content: lines(n)}); err != nil {
+			t.Fatalf("replace with %d chunks: %v", n, err)
+		}
+	}
+	if _, err := eng.ReplaceDocument(ctx, res.DocumentID, &engine.IngestInput{Content: lines(5)}); !errors.Is(err, weave.ErrQuotaExceeded) {
+		t.Errorf("replace with 5 chunks: got %v, want ErrQuotaExceeded", err)
+	}
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/loader"
+)
+
+// ──────────────────────────────────────────────────
+// Records
+// ──────────────────────────────────────────────────
+
+// IngestRecord ingests one record from a loader that splits a source into
+// records, such as CSVLoader.EachRecord, JSONLoader.LoadRecords,
+// EmailLoader.LoadMBOX or ArchiveLoader.LoadArchive, as its own document.
+// The record's content is already extracted, so the engine's loader is
+// not applied, and its "title" metadata names the document.
+//
+// A record with loader.MetaRecordID is upserted: if the collection has a
+// document with the same record ID, it is replaced in place with
+// ReplaceDocument and keeps its document ID. Loading a source again
+// therefore updates its records instead of duplicating them. Records
+// without an ID are always ingested as new documents.
+func (e *Engine) IngestRecord(ctx context.Context, colID id.CollectionID, record *loader.LoadResult) (*IngestResult, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+
+	input := &IngestInput{
+		CollectionID: colID,
+		Title:        record.Metadata["title"],
+		Content:      record.Content,
+		Metadata:     record.Metadata,
+	}
+	recordID := record.Metadata[loader.MetaRecordID]
+	if recordID == "" {
+		return e.Ingest(ctx, input)
+	}
+
+	existing, err := e.store.ListDocuments(ctx, &document.ListFilter{
+		CollectionID: colID,
+		Metadata:     map[string]string{loader.MetaRecordID: recordID},
+		Limit:        1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("weave: find record %s: %w", recordID, err)
+	}
+	if len(existing) > 0 {
+		return e.ReplaceDocument(ctx, existing[0].ID, input)
+	}
+	return e.Ingest(ctx, input)
+}
+
+// IngestRecords ingests each record as its own document with IngestRecord.
+// It stops at the first failure and returns the results so far.
+func (e *Engine) IngestRecords(ctx context.Context, colID id.CollectionID, records []*loader.LoadResult) ([]*IngestResult, error) {
+	results := make([]*IngestResult, 0, len(records))
+	for i, record := range records {
+		result, err := e.IngestRecord(ctx, colID, record)
+		if err != nil {
+			return results, fmt.Errorf("weave: record %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
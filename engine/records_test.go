@@ -0,0 +1,68 @@
+package engine_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/loader"
+)
+
+func TestIngestRecordsUpsertsByRecordID(t *testing.T) {
+	eng, col := newEngine(t)
+	ctx := context.Background()
+
+	csv := &loader.CSVLoader{
+		ContentColumns:  []string{"answer"},
+		MetadataColumns: map[string]string{"title": "question"},
+		IDColumn:        "id",
+	}
+	load := func(data string) []*loader.LoadResult {
+		t.Helper()
+		records, err := csv.LoadRecords(ctx, strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return records
+	}
+
+	first, err := eng.IngestRecords(ctx, col.ID, load("id,question,answer\n1,Hours?,Nine to five\n2,Parking?,Behind the shop\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 {
+		t.Fatalf("got %d results, want 2", len(first))
+	}
+
+	// Loading the sheet again updates row 1 in place and adds row 3.
+	second, err := eng.IngestRecords(ctx, col.ID, load("id,question,answer\n1,Hours?,Ten to six\n2,Parking?,Behind the shop\n3,Pets?,Dogs welcome\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second[0].DocumentID != first[0].DocumentID || second[1].DocumentID != first[1].DocumentID {
+		t.Errorf("record IDs moved to new documents: %v then %v", first, second)
+	}
+
+	docs, err := eng.ListDocuments(ctx, &document.ListFilter{CollectionID: col.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("got %d documents, want 3", len(docs))
+	}
+	doc, err := eng.GetDocument(ctx, first[0].DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Hours?" || doc.Metadata[loader.MetaRecordID] != "1" {
+		t.Errorf("document = %q %v", doc.Title, doc.Metadata)
+	}
+	chunks, err := eng.Store().ListChunksByDocument(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].Content != "answer: Ten to six" {
+		t.Errorf("chunks = %+v", chunks)
+	}
+}
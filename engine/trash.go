@@ -0,0 +1,414 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/id"
+)
+
+// ──────────────────────────────────────────────────
+// Trash
+// ──────────────────────────────────────────────────
+
+// TrashResult holds the soft-deleted collections and documents.
+type TrashResult struct {
+	Collections []*collection.Collection `json:"collections"`
+	Documents   []*document.Document     `json:"documents"`
+}
+
+// ListTrash returns all soft-deleted collections and documents.
+func (e *Engine) ListTrash(ctx context.Context) (*TrashResult, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+
+	cols, err := e.store.ListCollections(ctx, &collection.ListFilter{Trashed: true})
+	if err != nil {
+		return nil, fmt.Errorf("weave: list trashed collections: %w", err)
+	}
+	docs, err := e.store.ListDocuments(ctx, &document.ListFilter{Trashed: true})
+	if err != nil {
+		return nil, fmt.Errorf("weave: list trashed documents: %w", err)
+	}
+
+	return &TrashResult{Collections: cols, Documents: docs}, nil
+}
+
+// RestoreCollection moves a collection out of the trash.
+func (e *Engine) RestoreCollection(ctx context.Context, colID id.CollectionID) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+
+	col, err := e.store.GetCollection(ctx, colID)
+	if err != nil {
+		return err
+	}
+	if !col.Trashed() {
+		return weave.ErrNotTrashed
+	}
+
+	col.DeletedAt = nil
+	if err := e.store.UpdateCollection(ctx, col); err != nil {
+		return fmt.Errorf("weave: restore collection: %w", err)
+	}
+	e.invalidateTrash()
+	return nil
+}
+
+// RestoreDocument moves a document out of the trash. The document's
+// collection must not itself be in the trash.
+func (e *Engine) RestoreDocument(ctx context.Context, docID id.DocumentID) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+
+	doc, err := e.store.GetDocument(ctx, docID)
+	if err != nil {
+		return err
+	}
+	if !doc.Trashed() {
+		return weave.ErrNotTrashed
+	}
+
+	col, err := e.store.GetCollection(ctx, doc.CollectionID)
+	if err != nil {
+		return err
+	}
+	if col.Trashed() {
+		return weave.ErrCollectionTrashed
+	}
+
+	doc.DeletedAt = nil
+	if err := e.store.UpdateDocument(ctx, doc); err != nil {
+		return fmt.Errorf("weave: restore document: %w", err)
+	}
+	e.invalidateTrash()
+	return nil
+}
+
+// PurgeCollection permanently removes a trashed collection and all its
+// documents, chunks, and vector entries.
+func (e *Engine) PurgeCollection(ctx context.Context, colID id.CollectionID) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+
+	col, err := e.store.GetCollection(ctx, colID)
+	if err != nil {
+		return err
+	}
+	if !col.Trashed() {
+		return weave.ErrNotTrashed
+	}
+
+	// Delete vector entries first. The collection stays in the trash, and
+	// out of retrieval results, until they are gone, so a failed purge can
+	// be retried.
+	if e.vectorStore != nil {
+		if err := e.vectorStore.DeleteByMetadata(ctx, map[string]string{
+			"collection_id": colID.String(),
+		}); err != nil {
+			return fmt.Errorf("weave: delete vectors for collection: %w", err)
+		}
+	}
+
+	if err := e.store.DeleteChunksByCollection(ctx, colID); err != nil {
+		return fmt.Errorf("weave: delete chunks for collection: %w", err)
+	}
+	if err := e.store.DeleteDocumentsByCollection(ctx, colID); err != nil {
+		return fmt.Errorf("weave: delete documents for collection: %w", err)
+	}
+
+	defer e.invalidateTrash()
+	defer e.invalidateUsage(col.TenantID)
+	return e.store.DeleteCollection(ctx, colID)
+}
+
+// PurgeDocument permanently removes a trashed document and its chunks
+// from both stores.
+func (e *Engine) PurgeDocument(ctx context.Context, docID id.DocumentID) error {
+	if e.store == nil {
+		return weave.ErrNoStore
+	}
+
+	doc, err := e.store.GetDocument(ctx, docID)
+	if err != nil {
+		return err
+	}
+	if !doc.Trashed() {
+		return weave.ErrNotTrashed
+	}
+
+	// Delete vector entries first, so a failed purge leaves the document
+	// in the trash to be retried.
+	if e.vectorStore != nil {
+		if err := e.vectorStore.DeleteByMetadata(ctx, map[string]string{
+			"document_id": docID.String(),
+		}); err != nil {
+			return fmt.Errorf("weave: delete vectors: %w", err)
+		}
+	}
+
+	// Delete chunks.
+	if err := e.store.DeleteChunksByDocument(ctx, docID); err != nil {
+		return fmt.Errorf("weave: delete chunks: %w", err)
+	}
+
+	defer e.invalidateTrash()
+	defer e.invalidateUsage(doc.TenantID)
+	return e.store.DeleteDocument(ctx, docID)
+}
+
+// PurgeTrash permanently removes every collection and document that was
+// trashed before the given time. It returns the number of items purged.
+// An item that fails to purge does not stop the others; the failures are
+// joined into the returned error.
+func (e *Engine) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	trash, err := e.ListTrash(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for _, doc := range trash.Documents {
+		if !doc.DeletedAt.Before(before) {
+			continue
+		}
+		if err := e.PurgeDocument(ctx, doc.ID); err != nil {
+			errs = append(errs, fmt.Errorf("document %s: %w", doc.ID, err))
+			continue
+		}
+		purged++
+	}
+	for _, col := range trash.Collections {
+		if !col.DeletedAt.Before(before) {
+			continue
+		}
+		if err := e.PurgeCollection(ctx, col.ID); err != nil {
+			errs = append(errs, fmt.Errorf("collection %s: %w", col.ID, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// ──────────────────────────────────────────────────
+// Janitor
+// ──────────────────────────────────────────────────
+
+// startJanitor launches the background loop that purges trash older than
+// Config.TrashPurgeDelay every Config.JanitorInterval.
+func (e *Engine) startJanitor() {
+	if e.store == nil || e.config.JanitorInterval <= 0 || e.janitorStop != nil {
+		return
+	}
+
+	e.janitorStop = make(chan struct{})
+	e.janitorDone = make(chan struct{})
+
+	go func() {
+		defer close(e.janitorDone)
+
+		ticker := time.NewTicker(e.config.JanitorInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.janitorStop:
+				return
+			case <-ticker.C:
+				e.sweepTrash()
+			}
+		}
+	}()
+}
+
+// stopJanitor signals the janitor loop to exit and waits for it.
+func (e *Engine) stopJanitor() {
+	if e.janitorStop == nil {
+		return
+	}
+	close(e.janitorStop)
+	<-e.janitorDone
+	e.janitorStop = nil
+	e.janitorDone = nil
+}
+
+// sweepTrash runs a single janitor pass.
+func (e *Engine) sweepTrash() {
+	ctx := context.Background()
+	cutoff := time.Now().UTC().Add(-e.config.TrashPurgeDelay)
+
+	purged, err := e.PurgeTrash(ctx, cutoff)
+	if err != nil {
+		e.logger.Warn("janitor failed to purge trash",
+			log.String("error", err.Error()),
+		)
+	}
+	if purged > 0 {
+		e.logger.Info("janitor purged trash",
+			log.Int("purged", purged),
+		)
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Retrieval exclusion
+// ──────────────────────────────────────────────────
+
+// trashFilter identifies vector entries that belong to trashed
+// collections or documents.
+type trashFilter struct {
+	collections map[string]struct{}
+	documents   map[string]struct{}
+	loadedAt    time.Time
+}
+
+// trashFilterTTL is how long a cached trashFilter is used. The engine
+// drops its cache whenever it trashes, restores or purges, so this only
+// bounds how stale trash changes made by other engines sharing the store
+// can be.
+const trashFilterTTL = 10 * time.Second
+
+// trashScope is the part of the trash a search can reach: one collection,
+// or else one tenant's collections and documents, or everything when both
+// are empty.
+type trashScope struct {
+	collectionID string
+	tenantID     string
+}
+
+// newTrashScope returns the scope of a search filtered to the given
+// collection and tenant, either of which may be empty.
+func newTrashScope(collectionID, tenantID string) trashScope {
+	if collectionID != "" {
+		return trashScope{collectionID: collectionID}
+	}
+	return trashScope{tenantID: tenantID}
+}
+
+// loadTrashFilter returns the cached trashFilter for scope, reading that
+// part of the trash from the metadata store when the cache is empty or
+// stale.
+func (e *Engine) loadTrashFilter(ctx context.Context, scope trashScope) (*trashFilter, error) {
+	e.trashMu.Lock()
+	defer e.trashMu.Unlock()
+	if tf := e.trashCache[scope]; tf != nil && time.Since(tf.loadedAt) < trashFilterTTL {
+		return tf, nil
+	}
+
+	tf := &trashFilter{
+		collections: map[string]struct{}{},
+		documents:   map[string]struct{}{},
+		loadedAt:    time.Now(),
+	}
+	if e.store == nil {
+		return tf, nil
+	}
+	if err := e.readTrash(ctx, scope, tf); err != nil {
+		return nil, err
+	}
+
+	if e.trashCache == nil {
+		e.trashCache = map[trashScope]*trashFilter{}
+	}
+	for key, cached := range e.trashCache {
+		if time.Since(cached.loadedAt) >= trashFilterTTL {
+			delete(e.trashCache, key)
+		}
+	}
+	e.trashCache[scope] = tf
+	return tf, nil
+}
+
+// readTrash adds the trashed collections and documents within scope to tf.
+func (e *Engine) readTrash(ctx context.Context, scope trashScope, tf *trashFilter) error {
+	docFilter := &document.ListFilter{TenantID: scope.tenantID, Trashed: true}
+	if scope.collectionID != "" {
+		colID, err := id.ParseCollectionID(scope.collectionID)
+		if err != nil {
+			// No vector entry carries an invalid collection ID, so there
+			// is nothing to exclude.
+			return nil
+		}
+		col, err := e.store.GetCollection(ctx, colID)
+		switch {
+		case errors.Is(err, weave.ErrCollectionNotFound):
+			return nil
+		case err != nil:
+			return fmt.Errorf("weave: read trash: %w", err)
+		case col.Trashed():
+			tf.collections[scope.collectionID] = struct{}{}
+			return nil
+		}
+		docFilter = &document.ListFilter{CollectionID: colID, Trashed: true}
+	} else {
+		cols, err := e.store.ListCollections(ctx, &collection.ListFilter{TenantID: scope.tenantID, Trashed: true})
+		if err != nil {
+			return fmt.Errorf("weave: list trashed collections: %w", err)
+		}
+		for _, col := range cols {
+			tf.collections[col.ID.String()] = struct{}{}
+		}
+	}
+
+	docs, err := e.store.ListDocuments(ctx, docFilter)
+	if err != nil {
+		return fmt.Errorf("weave: list trashed documents: %w", err)
+	}
+	for _, doc := range docs {
+		tf.documents[doc.ID.String()] = struct{}{}
+	}
+	return nil
+}
+
+// invalidateTrash drops the cached trashFilter after the trash changed.
+func (e *Engine) invalidateTrash() {
+	e.trashMu.Lock()
+	defer e.trashMu.Unlock()
+	e.trashCache = nil
+}
+
+// empty reports whether nothing is in the trash.
+func (tf *trashFilter) empty() bool {
+	return len(tf.collections) == 0 && len(tf.documents) == 0
+}
+
+// excludes reports whether a vector entry with the given metadata belongs
+// to a trashed collection or document.
+func (tf *trashFilter) excludes(meta map[string]string) bool {
+	if _, ok := tf.collections[meta["collection_id"]]; ok {
+		return true
+	}
+	_, ok := tf.documents[meta["document_id"]]
+	return ok
+}
+
+// apply drops trashed results and trims the remainder to topK.
+func (tf *trashFilter) apply(scored []ScoredChunk, topK int) []ScoredChunk {
+	kept := scored
+	if !tf.empty() {
+		kept = scored[:0]
+		for _, sc := range scored {
+			if sc.Chunk != nil && tf.excludes(sc.Chunk.Metadata) {
+				continue
+			}
+			kept = append(kept, sc)
+		}
+	}
+	if topK > 0 && len(kept) > topK {
+		kept = kept[:topK]
+	}
+	return kept
+}
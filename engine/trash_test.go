@@ -0,0 +1,327 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/store/memory"
+	vsmemory "github.com/xraph/weave/vectorstore/memory"
+)
+
+func ingest(t *testing.T, eng *engine.Engine, colID id.CollectionID, content string) id.DocumentID {
+	t.Helper()
+	res, err := eng.Ingest(context.Background(), &engine.IngestInput{CollectionID: colID, Content: content})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.DocumentID
+}
+
+// retrieved returns the contents Retrieve finds for query.
+func retrieved(t *testing.T, eng *engine.Engine, query string, topK int) []string {
+	t.Helper()
+	results, err := eng.Retrieve(context.Background(), query, engine.WithTopK(topK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Chunk.Content
+	}
+	return out
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	eng, col := newEngine(t)
+	docID := ingest(t, eng, col.ID, "kept in the trash")
+
+	if err := eng.DeleteDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.DeleteDocument(ctx, docID); !errors.Is(err, weave.ErrDocumentTrashed) {
+		t.Errorf("second delete: %v", err)
+	}
+	if got := retrieved(t, eng, "kept in the trash", 5); len(got) != 0 {
+		t.Errorf("trashed document retrieved: %q", got)
+	}
+
+	if err := eng.RestoreDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if got := retrieved(t, eng, "kept in the trash", 5); len(got) != 1 {
+		t.Errorf("restored document not retrieved: %q", got)
+	}
+
+	if err := eng.PurgeDocument(ctx, docID); !errors.Is(err, weave.ErrNotTrashed) {
+		t.Errorf("purge of live document: %v", err)
+	}
+	if err := eng.DeleteDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.PurgeDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.GetDocument(ctx, docID); !errors.Is(err, weave.ErrDocumentNotFound) {
+		t.Errorf("purged document: %v", err)
+	}
+	if chunks, _ := eng.Store().ListChunksByDocument(ctx, docID); len(chunks) != 0 {
+		t.Errorf("purged document kept %d chunks", len(chunks))
+	}
+}
+
+func TestTrashedCollection(t *testing.T) {
+	ctx := context.Background()
+	eng, col := newEngine(t)
+	docID := ingest(t, eng, col.ID, "in a trashed collection")
+	if err := eng.DeleteDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.DeleteCollection(ctx, col.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: "more"}); !errors.Is(err, weave.ErrCollectionTrashed) {
+		t.Errorf("ingest: %v", err)
+	}
+	if err := eng.ReindexCollection(ctx, col.ID); !errors.Is(err, weave.ErrCollectionTrashed) {
+		t.Errorf("reindex: %v", err)
+	}
+	if err := eng.RestoreDocument(ctx, docID); !errors.Is(err, weave.ErrCollectionTrashed) {
+		t.Errorf("restore document: %v", err)
+	}
+
+	if err := eng.RestoreCollection(ctx, col.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.RestoreDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if got := retrieved(t, eng, "in a trashed collection", 5); len(got) != 1 {
+		t.Errorf("restored collection not retrieved: %q", got)
+	}
+}
+
+func TestRetrieveSearchesPastTrashedHits(t *testing.T) {
+	ctx := context.Background()
+	eng, col := newEngine(t)
+	// The lengthEmbedder scores texts of the query's length highest, so
+	// the trashed documents outrank the live ones.
+	for i := range 8 {
+		docID := ingest(t, eng, col.ID, fmt.Sprintf("trashed-%02d", i))
+		if err := eng.DeleteDocument(ctx, docID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range 4 {
+		ingest(t, eng, col.ID, fmt.Sprintf("live document number %02d", i))
+	}
+
+	got := retrieved(t, eng, "0123456789", 2)
+	if len(got) != 2 {
+		t.Fatalf("retrieved %q, want 2 live chunks", got)
+	}
+	for _, content := range got {
+		if strings.HasPrefix(content, "trashed") {
+			t.Errorf("retrieved trashed chunk %q", content)
+		}
+	}
+}
+
+// purgeFailStore fails to delete the chunks of one document.
+type purgeFailStore struct {
+	*memory.Store
+	fail id.DocumentID
+}
+
+func (s *purgeFailStore) DeleteChunksByDocument(ctx context.Context, docID id.DocumentID) error {
+	if docID == s.fail {
+		return errors.New("disk on fire")
+	}
+	return s.Store.DeleteChunksByDocument(ctx, docID)
+}
+
+func TestPurgeTrashContinuesPastFailures(t *testing.T) {
+	ctx := context.Background()
+	st := &purgeFailStore{Store: memory.New()}
+	eng, err := engine.New(
+		engine.WithStore(st),
+		engine.WithVectorStore(vsmemory.New()),
+		engine.WithEmbedder(lengthEmbedder{}),
+		engine.WithChunker(chunker.NewFixedChunker()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := &collection.Collection{ID: id.NewCollectionID(), Name: "docs"}
+	if err := eng.CreateCollection(ctx, col); err != nil {
+		t.Fatal(err)
+	}
+	var docs []id.DocumentID
+	for i := range 3 {
+		docID := ingest(t, eng, col.ID, fmt.Sprintf("document %d", i))
+		if err := eng.DeleteDocument(ctx, docID); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, docID)
+	}
+	st.fail = docs[0]
+
+	purged, err := eng.PurgeTrash(ctx, time.Now().Add(time.Minute))
+	if err == nil || !strings.Contains(err.Error(), "disk on fire") {
+		t.Errorf("PurgeTrash error = %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("purged %d, want 2", purged)
+	}
+	trash, err := eng.ListTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Documents) != 1 || trash.Documents[0].ID != docs[0] {
+		t.Errorf("trash after purge = %+v", trash.Documents)
+	}
+}
+
+// vectorFailStore fails to delete vector entries while fail is set.
+type vectorFailStore struct {
+	*vsmemory.Store
+	fail bool
+}
+
+func (s *vectorFailStore) DeleteByMetadata(ctx context.Context, filter map[string]string) error {
+	if s.fail {
+		return errors.New("index offline")
+	}
+	return s.Store.DeleteByMetadata(ctx, filter)
+}
+
+func TestPurgeKeepsTrashWhenVectorsRemain(t *testing.T) {
+	ctx := context.Background()
+	vs := &vectorFailStore{Store: vsmemory.New(), fail: true}
+	eng, col := newEngine(t, engine.WithVectorStore(vs))
+	docID := ingest(t, eng, col.ID, "still indexed")
+	if err := eng.DeleteDocument(ctx, docID); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.DeleteCollection(ctx, col.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := eng.PurgeDocument(ctx, docID); err == nil || !strings.Contains(err.Error(), "index offline") {
+		t.Errorf("PurgeDocument error = %v", err)
+	}
+	if err := eng.PurgeCollection(ctx, col.ID); err == nil || !strings.Contains(err.Error(), "index offline") {
+		t.Errorf("PurgeCollection error = %v", err)
+	}
+	if doc, err := eng.GetDocument(ctx, docID); err != nil || !doc.Trashed() {
+		t.Errorf("document after failed purge: %v, %v", doc, err)
+	}
+	if chunks, _ := eng.Store().ListChunksByDocument(ctx, docID); len(chunks) == 0 {
+		t.Error("failed purge deleted the chunks")
+	}
+	if got := retrieved(t, eng, "still indexed", 5); len(got) != 0 {
+		t.Errorf("retrieved %q after failed purge", got)
+	}
+
+	vs.fail = false
+	if err := eng.PurgeCollection(ctx, col.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := retrieved(t, eng, "still indexed", 5); len(got) != 0 {
+		t.Errorf("retrieved %q after purge", got)
+	}
+}
+
+// trashListStore records the filters of trash listings.
+type trashListStore struct {
+	*memory.Store
+	mu          sync.Mutex
+	collections []collection.ListFilter
+	documents   []document.ListFilter
+}
+
+func (s *trashListStore) ListCollections(ctx context.Context, filter *collection.ListFilter) ([]*collection.Collection, error) {
+	if filter != nil && filter.Trashed {
+		s.mu.Lock()
+		s.collections = append(s.collections, *filter)
+		s.mu.Unlock()
+	}
+	return s.Store.ListCollections(ctx, filter)
+}
+
+func (s *trashListStore) ListDocuments(ctx context.Context, filter *document.ListFilter) ([]*document.Document, error) {
+	if filter != nil && filter.Trashed {
+		s.mu.Lock()
+		s.documents = append(s.documents, *filter)
+		s.mu.Unlock()
+	}
+	return s.Store.ListDocuments(ctx, filter)
+}
+
+func TestRetrieveReadsScopedTrash(t *testing.T) {
+	ctx := context.Background()
+	st := &trashListStore{Store: memory.New()}
+	eng, _ := newEngine(t, engine.WithStore(st))
+
+	cols := map[string]*collection.Collection{}
+	for _, tenant := range []string{"acme", "globex"} {
+		tctx := weave.WithTenant(ctx, tenant)
+		col := &collection.Collection{ID: id.NewCollectionID(), Name: tenant}
+		if err := eng.CreateCollection(tctx, col); err != nil {
+			t.Fatal(err)
+		}
+		cols[tenant] = col
+		for _, content := range []string{tenant + " live", tenant + " gone"} {
+			res, err := eng.Ingest(tctx, &engine.IngestInput{CollectionID: col.ID, Content: content})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.HasSuffix(content, "gone") {
+				if err := eng.DeleteDocument(tctx, res.DocumentID); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	search := func(opts ...engine.RetrieveOption) []string {
+		t.Helper()
+		results, err := eng.Retrieve(ctx, "live", append(opts, engine.WithTopK(5))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Chunk.Content)
+		}
+		return got
+	}
+
+	if got := search(engine.WithCollection(cols["acme"].ID)); len(got) != 1 || got[0] != "acme live" {
+		t.Errorf("acme collection: %q", got)
+	}
+	if len(st.collections) != 0 || len(st.documents) != 1 || st.documents[0].CollectionID != cols["acme"].ID {
+		t.Errorf("collection search read trash with %+v and %+v", st.collections, st.documents)
+	}
+
+	st.documents = nil
+	if got := search(engine.WithTenantID("globex")); len(got) != 1 || got[0] != "globex live" {
+		t.Errorf("globex tenant: %q", got)
+	}
+	if len(st.collections) != 1 || st.collections[0].TenantID != "globex" ||
+		len(st.documents) != 1 || st.documents[0].TenantID != "globex" {
+		t.Errorf("tenant search read trash with %+v and %+v", st.collections, st.documents)
+	}
+}
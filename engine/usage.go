@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
+)
+
+// ──────────────────────────────────────────────────
+// Usage accounting
+// ──────────────────────────────────────────────────
+
+// QueryUsage aggregates recorded embedding token usage. A zero
+// Granularity defaults to daily buckets.
+func (e *Engine) QueryUsage(ctx context.Context, q *usage.Query) ([]*usage.Summary, error) {
+	if e.store == nil {
+		return nil, weave.ErrNoStore
+	}
+	if q.Granularity == "" {
+		q.Granularity = usage.GranularityDay
+	}
+	return e.store.QueryUsage(ctx, q)
+}
+
+// usageScope identifies who an embedding call is charged to.
+type usageScope struct {
+	tenantID     string
+	appID        string
+	collectionID id.CollectionID
+	operation    usage.Operation
+	model        string
+}
+
+// recordEmbedding persists the tokens consumed by an embedding call as a
+// usage record and adds them to the tenant's quota counter for the
+// current period. Embedders that do not report token counts are charged
+// an estimate based on the input length. Failures are logged, not
+// returned, so accounting never fails the operation itself.
+func (e *Engine) recordEmbedding(ctx context.Context, scope usageScope, texts []string, results []embedder.EmbedResult) {
+	var tokens int64
+	for _, r := range results {
+		tokens += int64(r.TokenCount)
+	}
+	if tokens == 0 {
+		tokens = e.estimateTokens(texts)
+	}
+	if tokens == 0 || e.store == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	if err := e.store.AddEmbeddingTokens(ctx, scope.tenantID, quota.Period(now), tokens); err != nil {
+		e.logger.Warn("failed to record embedding tokens",
+			log.String("tenant_id", scope.tenantID),
+			log.String("error", err.Error()),
+		)
+	}
+
+	rec := &usage.Record{
+		ID:           id.NewUsageID(),
+		TenantID:     scope.tenantID,
+		AppID:        scope.appID,
+		CollectionID: scope.collectionID,
+		Operation:    scope.operation,
+		Model:        scope.model,
+		Tokens:       tokens,
+		CreatedAt:    now,
+	}
+	if err := e.store.RecordUsage(ctx, rec); err != nil {
+		e.logger.Warn("failed to record usage",
+			log.String("tenant_id", scope.tenantID),
+			log.String("operation", string(scope.operation)),
+			log.String("error", err.Error()),
+		)
+	}
+}
+
+// queryScope builds the usage scope for a retrieval query, charging it to
+// the collection's embedding model when the collection is known.
+func (e *Engine) queryScope(ctx context.Context, tenantID string, colID id.CollectionID) usageScope {
+	scope := usageScope{
+		tenantID:     tenantID,
+		appID:        weave.AppFromContext(ctx),
+		collectionID: colID,
+		operation:    usage.OperationQuery,
+		model:        e.config.DefaultEmbeddingModel,
+	}
+	if e.store != nil && !colID.IsNil() {
+		if col, err := e.store.GetCollection(ctx, colID); err == nil {
+			scope.appID = col.AppID
+			scope.model = col.EmbeddingModel
+		}
+	}
+	return scope
+}
+
+// estimateTokens counts the tokens in texts with the configured tokenizer,
+// or approximates them at four characters per token without one.
+func (e *Engine) estimateTokens(texts []string) int64 {
+	var n int64
+	for _, t := range texts {
+		if e.tokenizer != nil {
+			n += int64(e.tokenizer.CountTokens(t))
+		} else {
+			n += int64((len(t) + 3) / 4)
+		}
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/collection"
+	"github.com/xraph/weave/embedder"
+	"github.com/xraph/weave/engine"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/usage"
+)
+
+// countingEmbedder reports seven tokens for every text it embeds.
+type countingEmbedder struct{ lengthEmbedder }
+
+func (e countingEmbedder) Embed(ctx context.Context, texts []string) ([]embedder.EmbedResult, error) {
+	results, err := e.lengthEmbedder.Embed(ctx, texts)
+	for i := range results {
+		results[i].TokenCount = 7
+	}
+	return results, err
+}
+
+func TestUsageRecordedOnIngestAndRetrieve(t *testing.T) {
+	ctx := weave.WithApp(weave.WithTenant(context.Background(), "acme"), "portal")
+	eng, _ := newEngine(t, engine.WithEmbedder(countingEmbedder{}))
+	col := &collection.Collection{ID: id.NewCollectionID(), Name: "billed", EmbeddingModel: "tiny-embed"}
+	if err := eng.CreateCollection(ctx, col); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := eng.Ingest(ctx, &engine.IngestInput{CollectionID: col.ID, Content: "one chunk of text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.Retrieve(ctx, "query", engine.WithCollection(col.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	summaries, err := eng.QueryUsage(ctx, &usage.Query{TenantID: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2: %+v", len(summaries), summaries)
+	}
+	want := map[usage.Operation]int64{
+		usage.OperationIngest: int64(7 * res.ChunkCount),
+		usage.OperationQuery:  7,
+	}
+	for _, s := range summaries {
+		if s.Tokens != want[s.Operation] || s.Requests != 1 {
+			t.Errorf("%s: %d tokens in %d requests, want %d in 1", s.Operation, s.Tokens, s.Requests, want[s.Operation])
+		}
+		if s.AppID != "portal" || s.CollectionID != col.ID || s.Model != "tiny-embed" {
+			t.Errorf("%s charged to app %q, collection %s, model %q", s.Operation, s.AppID, s.CollectionID, s.Model)
+		}
+		if s.Period != usage.Day(time.Now()) {
+			t.Errorf("%s period = %q", s.Operation, s.Period)
+		}
+	}
+
+	// The same tokens count toward the tenant's monthly quota.
+	used, err := eng.TenantUsage(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := want[usage.OperationIngest] + want[usage.OperationQuery]; used.EmbeddingTokens != total {
+		t.Errorf("quota embedding tokens = %d, want %d", used.EmbeddingTokens, total)
+	}
+}
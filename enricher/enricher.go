@@ -0,0 +1,114 @@
+// Package enricher adds document context to chunks before they are
+// embedded, so a chunk that says "it supports three modes" is embedded
+// together with the product it belongs to.
+package enricher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/document"
+)
+
+// Enricher adds context to the chunks of a document. Enrichers record it
+// in chunk metadata, such as chunker.MetaEmbedPrefix, rather than changing
+// Content, so the context affects the vectors but the stored chunk stays
+// clean.
+type Enricher interface {
+	// Enrich updates the chunks of doc in place, including any Children.
+	Enrich(ctx context.Context, doc *document.Document, chunks []chunker.ChunkResult) error
+}
+
+// Chain runs enrichers in order.
+func Chain(enrichers ...Enricher) Enricher { return chain(enrichers) }
+
+type chain []Enricher
+
+func (c chain) Enrich(ctx context.Context, doc *document.Document, chunks []chunker.ChunkResult) error {
+	for _, enr := range c {
+		if err := enr.Enrich(ctx, doc, chunks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultTemplate names the document title, source and section of each
+// chunk.
+const DefaultTemplate = `{{with .Title}}Document: {{.}}
+{{end}}{{with .Source}}Source: {{.}}
+{{end}}{{with .HeadingPath}}Section: {{.}}
+{{end}}`
+
+// TemplateData is the data a TemplateEnricher renders for each chunk.
+type TemplateData struct {
+	// Title, Source and SourceType describe the document.
+	Title      string
+	Source     string
+	SourceType string
+	// HeadingPath is the chunk's chunker.MetaHeadingPath, if any.
+	HeadingPath string
+	// Index is the chunk index.
+	Index int
+	// Metadata is the document metadata overlaid with the chunk metadata.
+	Metadata map[string]string
+}
+
+// TemplateEnricher renders a text/template for every chunk and prepends
+// the result, followed by a blank line, to the text that is embedded.
+// Chunks for which the template renders only whitespace are left as they
+// are.
+type TemplateEnricher struct {
+	tmpl *template.Template
+}
+
+// NewTemplateEnricher parses text as a text/template over TemplateData.
+func NewTemplateEnricher(text string) (*TemplateEnricher, error) {
+	tmpl, err := template.New("embed").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("weave: parse embed template: %w", err)
+	}
+	return &TemplateEnricher{tmpl: tmpl}, nil
+}
+
+// Enrich sets chunker.MetaEmbedPrefix on each chunk to the rendered
+// template, ahead of any prefix the chunker already set.
+func (t *TemplateEnricher) Enrich(ctx context.Context, doc *document.Document, chunks []chunker.ChunkResult) error {
+	var b strings.Builder
+	for i := range chunks {
+		ch := &chunks[i]
+		meta := make(map[string]string, len(doc.Metadata)+len(ch.Metadata))
+		for k, v := range doc.Metadata {
+			meta[k] = v
+		}
+		for k, v := range ch.Metadata {
+			meta[k] = v
+		}
+
+		b.Reset()
+		if err := t.tmpl.Execute(&b, TemplateData{
+			Title:       doc.Title,
+			Source:      doc.Source,
+			SourceType:  doc.SourceType,
+			HeadingPath: ch.Metadata[chunker.MetaHeadingPath],
+			Index:       ch.Index,
+			Metadata:    meta,
+		}); err != nil {
+			return fmt.Errorf("weave: render embed template: %w", err)
+		}
+		if prefix := strings.TrimSpace(b.String()); prefix != "" {
+			if ch.Metadata == nil {
+				ch.Metadata = make(map[string]string)
+			}
+			ch.Metadata[chunker.MetaEmbedPrefix] = prefix + "\n\n" + ch.Metadata[chunker.MetaEmbedPrefix]
+		}
+
+		if err := t.Enrich(ctx, doc, ch.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
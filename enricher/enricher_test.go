@@ -0,0 +1,99 @@
+package enricher_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/enricher"
+	"github.com/xraph/weave/llm"
+)
+
+func TestTemplateEnricherSetsEmbedPrefix(t *testing.T) {
+	enr, err := enricher.NewTemplateEnricher(enricher.DefaultTemplate + "{{with .Metadata.product}}Product: {{.}}{{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &document.Document{Title: "Weave Guide", Metadata: map[string]string{"product": "weave"}}
+	chunks := []chunker.ChunkResult{
+		{Content: "It supports three modes.", Metadata: map[string]string{
+			chunker.MetaHeadingPath: "Install > Modes",
+			chunker.MetaEmbedPrefix: "Install > Modes\n\n",
+		}},
+		{Content: "Parent.", Children: []chunker.ChunkResult{{Content: "Child."}}},
+	}
+	if err := enr.Enrich(context.Background(), doc, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Document: Weave Guide\nSection: Install > Modes\nProduct: weave\n\nInstall > Modes\n\nIt supports three modes."
+	if got := chunker.EmbedText(chunks[0].Content, chunks[0].Metadata); got != want {
+		t.Errorf("embed text = %q, want %q", got, want)
+	}
+	if chunks[0].Content != "It supports three modes." {
+		t.Errorf("content changed to %q", chunks[0].Content)
+	}
+	want = "Document: Weave Guide\nProduct: weave\n\nChild."
+	if got := chunker.EmbedText(chunks[1].Children[0].Content, chunks[1].Children[0].Metadata); got != want {
+		t.Errorf("child embed text = %q, want %q", got, want)
+	}
+}
+
+func TestQuestionEnricherParsesReply(t *testing.T) {
+	model := llm.NewFake(func(req *llm.Request) string {
+		passage := req.Messages[len(req.Messages)-1].Content
+		return "SUMMARY: About " + passage[strings.LastIndexAny(passage, " \n")+1:] + "\n" +
+			"QUESTION: What is it?\nquestion: How does it work?\nQUESTION: Why?\nQUESTION: Extra?"
+	})
+	enr := enricher.NewQuestionEnricher(model)
+	chunks := []chunker.ChunkResult{
+		{Content: "Parent text.", Children: []chunker.ChunkResult{{Content: "alpha"}, {Content: "beta"}}},
+		{Content: "gamma"},
+	}
+	if err := enr.Enrich(context.Background(), &document.Document{Title: "Guide"}, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(model.Requests()); n != 3 {
+		t.Errorf("model called %d times, want 3 (leaves only)", n)
+	}
+	if _, ok := chunks[0].Metadata[enricher.MetaSummary]; ok {
+		t.Error("parent chunk was enriched")
+	}
+	for _, ch := range []chunker.ChunkResult{chunks[0].Children[0], chunks[0].Children[1], chunks[1]} {
+		if got, want := ch.Metadata[enricher.MetaSummary], "About "+ch.Content; got != want {
+			t.Errorf("summary = %q, want %q", got, want)
+		}
+		if got, want := ch.Metadata[enricher.MetaQuestions], "What is it?\nHow does it work?\nWhy?"; got != want {
+			t.Errorf("questions = %q, want %q", got, want)
+		}
+	}
+	if req := model.Requests()[0]; !strings.HasPrefix(req.Messages[1].Content, "Document: Guide\n\n") {
+		t.Errorf("passage lacks document title: %q", req.Messages[1].Content)
+	}
+}
+
+func TestEntriesPerChunk(t *testing.T) {
+	tmpl, err := enricher.NewTemplateEnricher(enricher.DefaultTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions := enricher.NewQuestionEnricher(llm.NewFake(nil))
+	questions.Questions = 5
+	tests := []struct {
+		enr  enricher.Enricher
+		want int
+	}{
+		{nil, 1},
+		{tmpl, 1},
+		{enricher.NewQuestionEnricher(llm.NewFake(nil)), 5},
+		{enricher.Chain(tmpl, questions), 7},
+	}
+	for i, tt := range tests {
+		if got := enricher.EntriesPerChunk(tt.enr); got != tt.want {
+			t.Errorf("case %d: EntriesPerChunk = %d, want %d", i, got, tt.want)
+		}
+	}
+}
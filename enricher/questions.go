@@ -0,0 +1,165 @@
+package enricher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/xraph/weave/chunker"
+	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/llm"
+)
+
+// Metadata keys set by QuestionEnricher. The engine embeds each summary
+// and question as an extra vector entry that points back to its chunk, so
+// question-style queries match the chunk that answers them.
+const (
+	// MetaSummary is a one-sentence summary of the chunk.
+	MetaSummary = "summary"
+	// MetaQuestions holds questions the chunk answers, one per line.
+	MetaQuestions = "questions"
+)
+
+const questionPrompt = `You index documents for search. Read the passage and reply in exactly this format, with no other text:
+SUMMARY: <one sentence summarising the passage>
+QUESTION: <a question the passage answers>
+(one QUESTION line per question, %d in total)`
+
+// QuestionEnricher asks a language model for a summary of each chunk and
+// the questions it answers, and records them under MetaSummary and
+// MetaQuestions. Only chunks without children, the ones that are
+// embedded, are sent to the model.
+type QuestionEnricher struct {
+	// Model generates the summaries and questions.
+	Model llm.Model
+	// Questions is the number of questions to ask for per chunk
+	// (default 3).
+	Questions int
+	// Concurrency caps the model calls in flight (default 4).
+	Concurrency int
+}
+
+// NewQuestionEnricher creates a QuestionEnricher using model.
+func NewQuestionEnricher(model llm.Model) *QuestionEnricher {
+	return &QuestionEnricher{Model: model}
+}
+
+// questions returns the number of questions asked for per chunk.
+func (q *QuestionEnricher) questions() int {
+	if q.Questions <= 0 {
+		return 3
+	}
+	return q.Questions
+}
+
+// EntriesPerChunk returns the most vector entries the engine embeds for a
+// chunk enriched by enr: the chunk itself, plus a summary and questions
+// when enr is or chains a QuestionEnricher.
+func EntriesPerChunk(enr Enricher) int {
+	switch enr := enr.(type) {
+	case *QuestionEnricher:
+		return 2 + enr.questions()
+	case chain:
+		n := 1
+		for _, c := range enr {
+			n = max(n, EntriesPerChunk(c))
+		}
+		return n
+	}
+	return 1
+}
+
+// Enrich generates a summary and questions for every leaf chunk.
+func (q *QuestionEnricher) Enrich(ctx context.Context, doc *document.Document, chunks []chunker.ChunkResult) error {
+	questions := q.questions()
+	concurrency := q.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var leaves []*chunker.ChunkResult
+	var collect func(chunks []chunker.ChunkResult)
+	collect = func(chunks []chunker.ChunkResult) {
+		for i := range chunks {
+			if len(chunks[i].Children) > 0 {
+				collect(chunks[i].Children)
+			} else {
+				leaves = append(leaves, &chunks[i])
+			}
+		}
+	}
+	collect(chunks)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, ch := range leaves {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(ch *chunker.ChunkResult) {
+			defer func() { <-sem; wg.Done() }()
+			summary, qs, err := q.generate(ctx, doc, ch.Content, questions)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			if ch.Metadata == nil {
+				ch.Metadata = make(map[string]string)
+			}
+			if summary != "" {
+				ch.Metadata[MetaSummary] = summary
+			}
+			if len(qs) > 0 {
+				ch.Metadata[MetaQuestions] = strings.Join(qs, "\n")
+			}
+		}(ch)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// generate asks the model about one chunk and parses its reply.
+func (q *QuestionEnricher) generate(ctx context.Context, doc *document.Document, content string, n int) (string, []string, error) {
+	passage := content
+	if doc.Title != "" {
+		passage = "Document: " + doc.Title + "\n\n" + content
+	}
+	resp, err := q.Model.Complete(ctx, &llm.Request{Messages: []llm.Message{
+		{Role: llm.RoleSystem, Content: fmt.Sprintf(questionPrompt, n)},
+		{Role: llm.RoleUser, Content: passage},
+	}})
+	if err != nil {
+		return "", nil, fmt.Errorf("weave: generate questions: %w", err)
+	}
+
+	var summary string
+	var questions []string
+	for _, line := range strings.Split(resp.Content, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "SUMMARY":
+			summary = value
+		case "QUESTION":
+			if len(questions) < n {
+				questions = append(questions, value)
+			}
+		}
+	}
+	return summary, questions, nil
+}
@@ -23,6 +23,18 @@ var (
 	ErrInvalidState = errors.New("weave: invalid state transition")
 	ErrEmptyContent = errors.New("weave: empty content")
 
+	// Validation errors.
+	ErrInvalidTemplate = errors.New("weave: invalid embed template")
+
+	// Trash errors.
+	ErrCollectionTrashed = errors.New("weave: collection is in trash")
+	ErrDocumentTrashed   = errors.New("weave: document is in trash")
+	ErrNotTrashed        = errors.New("weave: not in trash")
+
+	// Quota errors.
+	ErrQuotaNotFound = errors.New("weave: quota not found")
+	ErrQuotaExceeded = errors.New("weave: quota exceeded")
+
 	// Pipeline errors.
 	ErrNoEmbedder    = errors.New("weave: no embedder configured")
 	ErrNoVectorStore = errors.New("weave: no vector store configured")
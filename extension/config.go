@@ -1,6 +1,11 @@
 package extension
 
-import "time"
+import (
+	"time"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/quota"
+)
 
 // Config holds the Weave extension configuration.
 // Fields can be set programmatically via ExtOption functions or loaded from
@@ -27,6 +32,11 @@ type Config struct {
 	// DefaultChunkStrategy is the default chunking strategy (e.g., "recursive").
 	DefaultChunkStrategy string `json:"default_chunk_strategy" mapstructure:"default_chunk_strategy" yaml:"default_chunk_strategy"`
 
+	// Tokenizer is the BPE encoding ("cl100k_base" or "o200k_base") used to
+	// size chunks and count embedding tokens. Empty approximates a token as
+	// four characters.
+	Tokenizer string `json:"tokenizer" mapstructure:"tokenizer" yaml:"tokenizer"`
+
 	// DefaultTopK is the default number of results for similarity searches.
 	DefaultTopK int `json:"default_top_k" mapstructure:"default_top_k" yaml:"default_top_k"`
 
@@ -36,6 +46,17 @@ type Config struct {
 	// IngestConcurrency controls how many ingest operations can run in parallel.
 	IngestConcurrency int `json:"ingest_concurrency" mapstructure:"ingest_concurrency" yaml:"ingest_concurrency"`
 
+	// TrashPurgeDelay is how long trashed collections and documents are kept
+	// before the janitor permanently removes them.
+	TrashPurgeDelay time.Duration `json:"trash_purge_delay" mapstructure:"trash_purge_delay" yaml:"trash_purge_delay"`
+
+	// JanitorInterval is how often the janitor sweeps the trash.
+	JanitorInterval time.Duration `json:"janitor_interval" mapstructure:"janitor_interval" yaml:"janitor_interval"`
+
+	// DefaultQuota holds the resource limits applied to tenants without
+	// their own quota. Zero fields are unlimited.
+	DefaultQuota quota.Limits `json:"default_quota" mapstructure:"default_quota" yaml:"default_quota"`
+
 	// GroveDatabase is the name of a grove.DB registered in the DI container.
 	// When set, the extension resolves this named database and auto-constructs
 	// the appropriate store based on the driver type (pg/sqlite/mongo).
@@ -57,5 +78,22 @@ func DefaultConfig() Config {
 		DefaultTopK:           10,
 		ShutdownTimeout:       30 * time.Second,
 		IngestConcurrency:     4,
+		TrashPurgeDelay:       30 * 24 * time.Hour,
+		JanitorInterval:       time.Hour,
+	}
+}
+
+// engineConfig converts the extension config into the engine config.
+func (c Config) engineConfig() weave.Config {
+	return weave.Config{
+		DefaultChunkSize:      c.DefaultChunkSize,
+		DefaultChunkOverlap:   c.DefaultChunkOverlap,
+		DefaultEmbeddingModel: c.DefaultEmbeddingModel,
+		DefaultChunkStrategy:  c.DefaultChunkStrategy,
+		DefaultTopK:           c.DefaultTopK,
+		ShutdownTimeout:       c.ShutdownTimeout,
+		IngestConcurrency:     c.IngestConcurrency,
+		TrashPurgeDelay:       c.TrashPurgeDelay,
+		JanitorInterval:       c.JanitorInterval,
 	}
 }
@@ -28,6 +28,7 @@ import (
 	mongostore "github.com/xraph/weave/store/mongo"
 	pgstore "github.com/xraph/weave/store/postgres"
 	sqlitestore "github.com/xraph/weave/store/sqlite"
+	"github.com/xraph/weave/tokenizer"
 )
 
 // ExtensionName is the name registered with Forge.
@@ -108,7 +109,20 @@ func (e *Extension) Register(fapp forge.App) error {
 		)
 	}
 
-	eng, err := engine.New(e.engineOpts...)
+	// Engine config comes first so explicit engine options can override it.
+	opts := []engine.Option{
+		engine.WithConfig(e.config.engineConfig()),
+		engine.WithDefaultQuota(e.config.DefaultQuota),
+	}
+	if e.config.Tokenizer != "" {
+		enc, err := tokenizer.Get(e.config.Tokenizer)
+		if err != nil {
+			return fmt.Errorf("weave: %w", err)
+		}
+		opts = append(opts, engine.WithTokenizer(enc))
+	}
+	opts = append(opts, e.engineOpts...)
+	eng, err := engine.New(opts...)
 	if err != nil {
 		return fmt.Errorf("weave: create engine: %w", err)
 	}
@@ -309,6 +323,12 @@ func (e *Extension) mergeWithDefaults(cfg Config) Config {
 	if cfg.IngestConcurrency == 0 {
 		cfg.IngestConcurrency = defaults.IngestConcurrency
 	}
+	if cfg.TrashPurgeDelay == 0 {
+		cfg.TrashPurgeDelay = defaults.TrashPurgeDelay
+	}
+	if cfg.JanitorInterval == 0 {
+		cfg.JanitorInterval = defaults.JanitorInterval
+	}
 	return cfg
 }
 
@@ -336,6 +356,9 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if yamlConfig.DefaultChunkStrategy == "" && programmaticConfig.DefaultChunkStrategy != "" {
 		yamlConfig.DefaultChunkStrategy = programmaticConfig.DefaultChunkStrategy
 	}
+	if yamlConfig.Tokenizer == "" && programmaticConfig.Tokenizer != "" {
+		yamlConfig.Tokenizer = programmaticConfig.Tokenizer
+	}
 
 	// Int/Duration fields: YAML takes precedence, programmatic fills gaps.
 	if yamlConfig.DefaultChunkSize == 0 && programmaticConfig.DefaultChunkSize != 0 {
@@ -353,6 +376,15 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if yamlConfig.IngestConcurrency == 0 && programmaticConfig.IngestConcurrency != 0 {
 		yamlConfig.IngestConcurrency = programmaticConfig.IngestConcurrency
 	}
+	if yamlConfig.TrashPurgeDelay == 0 && programmaticConfig.TrashPurgeDelay != 0 {
+		yamlConfig.TrashPurgeDelay = programmaticConfig.TrashPurgeDelay
+	}
+	if yamlConfig.JanitorInterval == 0 && programmaticConfig.JanitorInterval != 0 {
+		yamlConfig.JanitorInterval = programmaticConfig.JanitorInterval
+	}
+	if yamlConfig.DefaultQuota.Unlimited() && !programmaticConfig.DefaultQuota.Unlimited() {
+		yamlConfig.DefaultQuota = programmaticConfig.DefaultQuota
+	}
 
 	// Fill remaining zeros with defaults.
 	return e.mergeWithDefaults(yamlConfig)
@@ -29,6 +29,7 @@ const (
 	PrefixChunk      Prefix = "chk"
 	PrefixPipeline   Prefix = "pipe"
 	PrefixIngestJob  Prefix = "ingjob"
+	PrefixUsage      Prefix = "usg"
 )
 
 // ID is the primary identifier type for all Weave entities.
@@ -124,6 +125,9 @@ type PipelineID = ID
 // IngestJobID is a type-safe identifier for ingest jobs (prefix: "ingjob").
 type IngestJobID = ID
 
+// UsageID is a type-safe identifier for usage records (prefix: "usg").
+type UsageID = ID
+
 // AnyID is a type alias that accepts any valid prefix.
 type AnyID = ID
 
@@ -146,6 +150,9 @@ func NewPipelineID() ID { return New(PrefixPipeline) }
 // NewIngestJobID generates a new unique ingest job ID.
 func NewIngestJobID() ID { return New(PrefixIngestJob) }
 
+// NewUsageID generates a new unique usage record ID.
+func NewUsageID() ID { return New(PrefixUsage) }
+
 // ──────────────────────────────────────────────────
 // Convenience parsers
 // ──────────────────────────────────────────────────
@@ -165,6 +172,9 @@ func ParsePipelineID(s string) (ID, error) { return ParseWithPrefix(s, PrefixPip
 // ParseIngestJobID parses a string and validates the "ingjob" prefix.
 func ParseIngestJobID(s string) (ID, error) { return ParseWithPrefix(s, PrefixIngestJob) }
 
+// ParseUsageID parses a string and validates the "usg" prefix.
+func ParseUsageID(s string) (ID, error) { return ParseWithPrefix(s, PrefixUsage) }
+
 // ParseAny parses a string into an ID without type checking the prefix.
 func ParseAny(s string) (ID, error) { return Parse(s) }
 
@@ -18,6 +18,7 @@ func TestConstructors(t *testing.T) {
 		{"ChunkID", id.NewChunkID, "chk_"},
 		{"PipelineID", id.NewPipelineID, "pipe_"},
 		{"IngestJobID", id.NewIngestJobID, "ingjob_"},
+		{"UsageID", id.NewUsageID, "usg_"},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +52,7 @@ func TestParseRoundTrip(t *testing.T) {
 		{"ChunkID", id.NewChunkID, id.ParseChunkID},
 		{"PipelineID", id.NewPipelineID, id.ParsePipelineID},
 		{"IngestJobID", id.NewIngestJobID, id.ParseIngestJobID},
+		{"UsageID", id.NewUsageID, id.ParseUsageID},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is a deterministic Model for tests. It records every request and
+// answers with Reply, or with the content of the last user message when
+// Reply is nil.
+type Fake struct {
+	// Reply computes the response content for a request.
+	Reply func(req *Request) string
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// NewFake creates a Fake that answers with reply.
+func NewFake(reply func(req *Request) string) *Fake { return &Fake{Reply: reply} }
+
+// Complete records req and returns the deterministic reply.
+func (f *Fake) Complete(_ context.Context, req *Request) (*Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, *req)
+	f.mu.Unlock()
+
+	var content string
+	if f.Reply != nil {
+		content = f.Reply(req)
+	} else {
+		for i := len(req.Messages) - 1; i >= 0; i-- {
+			if req.Messages[i].Role == RoleUser {
+				content = req.Messages[i].Content
+				break
+			}
+		}
+	}
+	return &Response{Content: content, Model: "fake"}, nil
+}
+
+// Requests returns the requests received so far.
+func (f *Fake) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
@@ -0,0 +1,49 @@
+// Package llm defines the interface for generative language models used
+// by ingest-time enrichment.
+package llm
+
+import "context"
+
+// Role is the author of a chat message.
+type Role string
+
+// Message roles.
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a single chat message.
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a chat-completion request.
+type Request struct {
+	// Messages is the conversation so far.
+	Messages []Message `json:"messages"`
+	// Temperature controls randomness; zero asks for the most likely
+	// completion.
+	Temperature float64 `json:"temperature"`
+	// MaxTokens caps the completion length. Zero uses the model default.
+	MaxTokens int `json:"max_tokens,omitempty"`
+}
+
+// Response is a chat-completion response.
+type Response struct {
+	// Content is the generated assistant message.
+	Content string `json:"content"`
+	// Model is the model that produced the response.
+	Model string `json:"model"`
+	// PromptTokens and CompletionTokens are the reported token usage.
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Model generates chat completions.
+type Model interface {
+	// Complete returns the model's reply to the conversation in req.
+	Complete(ctx context.Context, req *Request) (*Response, error)
+}
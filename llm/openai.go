@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI generates completions with the OpenAI Chat Completions API or any
+// compatible endpoint.
+type OpenAI struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// OpenAIOption configures the OpenAI model.
+type OpenAIOption func(*OpenAI)
+
+// WithOpenAIModel sets the model name (default: gpt-4o-mini).
+func WithOpenAIModel(model string) OpenAIOption {
+	return func(m *OpenAI) { m.model = model }
+}
+
+// WithOpenAIBaseURL overrides the base URL for API-compatible endpoints.
+func WithOpenAIBaseURL(url string) OpenAIOption {
+	return func(m *OpenAI) { m.baseURL = strings.TrimRight(url, "/") }
+}
+
+// WithOpenAIClient sets a custom HTTP client.
+func WithOpenAIClient(c *http.Client) OpenAIOption {
+	return func(m *OpenAI) { m.client = c }
+}
+
+// NewOpenAI creates an OpenAI chat model.
+func NewOpenAI(apiKey string, opts ...OpenAIOption) *OpenAI {
+	m := &OpenAI{
+		apiKey:  apiKey,
+		model:   "gpt-4o-mini",
+		baseURL: "https://api.openai.com",
+		client:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+type openAIRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+}
+
+type openAIResponse struct {
+	Model   string         `json:"model"`
+	Choices []openAIChoice `json:"choices"`
+	Usage   openAIUsage    `json:"usage"`
+}
+
+type openAIChoice struct {
+	Message Message `json:"message"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete sends the conversation to the chat completions endpoint.
+func (m *OpenAI) Complete(ctx context.Context, req *Request) (*Response, error) {
+	body, err := json.Marshal(openAIRequest{
+		Model:       m.model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("weave: openai chat: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		m.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("weave: openai chat: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("weave: openai chat: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr openAIError
+		if data, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096)); readErr == nil &&
+			json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("weave: openai chat: status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("weave: openai chat: status %d", resp.StatusCode)
+	}
+
+	var out openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("weave: openai chat: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return nil, fmt.Errorf("weave: openai chat: no choices in response")
+	}
+
+	return &Response{
+		Content:          out.Choices[0].Message.Content,
+		Model:            out.Model,
+		PromptTokens:     out.Usage.PromptTokens,
+		CompletionTokens: out.Usage.CompletionTokens,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/llm"
+)
+
+func TestOpenAIComplete(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("authorization = %q", got)
+		}
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []llm.Message `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Model != "test-model" || len(body.Messages) != 2 || body.Messages[1].Content != "Hi" {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		_, _ = w.Write([]byte(`{"model":"test-model","choices":[{"message":{"role":"assistant","content":"Hello!"}}],"usage":{"prompt_tokens":7,"completion_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	m := llm.NewOpenAI("sk-test", llm.WithOpenAIBaseURL(srv.URL), llm.WithOpenAIModel("test-model"))
+	resp, err := m.Complete(context.Background(), &llm.Request{Messages: []llm.Message{
+		{Role: llm.RoleSystem, Content: "Be brief."},
+		{Role: llm.RoleUser, Content: "Hi"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "Hello!" || resp.PromptTokens != 7 || resp.CompletionTokens != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestOpenAICompleteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+	}))
+	defer srv.Close()
+
+	m := llm.NewOpenAI("sk-test", llm.WithOpenAIBaseURL(srv.URL))
+	_, err := m.Complete(context.Background(), &llm.Request{Messages: []llm.Message{{Role: llm.RoleUser, Content: "Hi"}}})
+	if err == nil || !strings.Contains(err.Error(), "429") || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("err = %v, want status 429 with message", err)
+	}
+}
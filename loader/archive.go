@@ -0,0 +1,267 @@
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrArchiveLimit is returned when an archive has more entries, or more
+// decompressed data, than an ArchiveLoader allows.
+var ErrArchiveLimit = errors.New("weave: archive exceeds limits")
+
+// Default ArchiveLoader limits.
+const (
+	DefaultArchiveMaxEntries   = 10000
+	DefaultArchiveMaxEntrySize = 64 << 20
+	DefaultArchiveMaxTotalSize = 512 << 20
+)
+
+// ArchiveLoader walks .zip, .tar and .tar.gz archives and loads each file
+// with the delegate loader for its extension. Limits on the number of
+// entries and on decompressed sizes guard against archive bombs.
+type ArchiveLoader struct {
+	loaders []Loader
+
+	// MaxEntries caps the number of file entries
+	// (default DefaultArchiveMaxEntries).
+	MaxEntries int
+	// MaxEntrySize caps the decompressed size of a single entry
+	// (default DefaultArchiveMaxEntrySize).
+	MaxEntrySize int64
+	// MaxTotalSize caps the decompressed size of all entries together
+	// (default DefaultArchiveMaxTotalSize).
+	MaxTotalSize int64
+}
+
+// NewArchiveLoader creates an ArchiveLoader that delegates to the given loaders.
+func NewArchiveLoader(loaders ...Loader) *ArchiveLoader {
+	return &ArchiveLoader{loaders: loaders}
+}
+
+// Load is not supported for ArchiveLoader — use LoadArchive instead.
+func (l *ArchiveLoader) Load(_ context.Context, _ io.Reader) (*LoadResult, error) {
+	return nil, fmt.Errorf("weave: ArchiveLoader.Load not supported; use LoadArchive")
+}
+
+// Supports always returns false — use LoadArchive directly.
+func (l *ArchiveLoader) Supports(_ string) bool { return false }
+
+// EntryError is a failure to read or load one entry of an archive.
+type EntryError struct {
+	Path string
+	Err  error
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("weave: archive entry %s: %v", e.Path, e.Err)
+}
+
+func (e *EntryError) Unwrap() error { return e.Err }
+
+// LoadArchive detects the archive format and returns one load result per
+// supported entry, with the archive-relative path as "source_path" and
+// MetaRecordID. Entries no delegate supports are skipped. Entries that
+// fail, including those larger than MaxEntrySize, do not stop the walk;
+// their *EntryError values are joined into the returned error. Exceeding
+// MaxEntries or MaxTotalSize stops it.
+//
+// Office and EPUB entries are themselves zip packages. The parts they
+// decompress count towards MaxEntrySize and MaxTotalSize too.
+func (l *ArchiveLoader) LoadArchive(ctx context.Context, reader io.Reader) ([]*LoadResult, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &archiveWalk{l: l, ctx: ctx}
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) || bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		err = w.zip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, gzErr := gzip.NewReader(bytes.NewReader(data))
+		if gzErr != nil {
+			return nil, fmt.Errorf("weave: archive load: %w", gzErr)
+		}
+		err = w.tar(gz)
+	default:
+		err = w.tar(bytes.NewReader(data))
+	}
+	return w.results, errors.Join(append(w.errs, err)...)
+}
+
+// archiveWalk holds the state of one LoadArchive call.
+type archiveWalk struct {
+	l       *ArchiveLoader
+	ctx     context.Context
+	results []*LoadResult
+	errs    []error
+	entries int
+	total   int64
+}
+
+// skip records a failed entry and reports whether the walk can go on.
+func (w *archiveWalk) skip(err error) bool {
+	var entryErr *EntryError
+	if !errors.As(err, &entryErr) {
+		return false
+	}
+	w.errs = append(w.errs, err)
+	return true
+}
+
+func (w *archiveWalk) zip(data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("weave: archive load: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if err := w.count(); err != nil {
+			return err
+		}
+		name, loader := w.entry(f.Name)
+		if loader == nil {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			w.errs = append(w.errs, &EntryError{Path: name, Err: err})
+			continue
+		}
+		content, err := w.read(name, rc)
+		_ = rc.Close()
+		if err == nil {
+			err = w.load(name, loader, content)
+		}
+		if err != nil && !w.skip(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *archiveWalk) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("weave: archive load: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := w.count(); err != nil {
+			return err
+		}
+		name, loader := w.entry(hdr.Name)
+		if loader == nil {
+			// Skipped entries are still decompressed, so they count
+			// towards the total size.
+			if w.total += hdr.Size; w.total > w.maxTotal() {
+				return fmt.Errorf("%w: more than %d bytes decompressed", ErrArchiveLimit, w.maxTotal())
+			}
+			continue
+		}
+		content, err := w.read(name, tr)
+		if err == nil {
+			err = w.load(name, loader, content)
+		}
+		if err != nil && !w.skip(err) {
+			return err
+		}
+	}
+}
+
+// count registers a file entry and enforces MaxEntries.
+func (w *archiveWalk) count() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	limit := w.l.MaxEntries
+	if limit <= 0 {
+		limit = DefaultArchiveMaxEntries
+	}
+	if w.entries++; w.entries > limit {
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveLimit, limit)
+	}
+	return nil
+}
+
+// entry cleans an entry name and picks its loader. Resource forks and
+// other hidden files get no loader.
+func (w *archiveWalk) entry(raw string) (string, Loader) {
+	name := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(raw, `\`, "/")), "/")
+	base := path.Base(name)
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+		return name, nil
+	}
+	return name, pickLoader(w.l.loaders, mimeFromExt(path.Ext(name)))
+}
+
+// read decompresses one entry, enforcing MaxEntrySize and MaxTotalSize
+// on the bytes actually produced rather than the sizes headers claim. An
+// entry over MaxEntrySize fails with an *EntryError.
+func (w *archiveWalk) read(name string, r io.Reader) ([]byte, error) {
+	entryLimit := w.maxEntry()
+	remaining := w.maxTotal() - w.total
+
+	content, err := io.ReadAll(io.LimitReader(r, min(entryLimit, remaining)+1))
+	w.total += int64(len(content))
+	if err != nil {
+		return nil, &EntryError{Path: name, Err: err}
+	}
+	switch n := int64(len(content)); {
+	case n > remaining:
+		return nil, fmt.Errorf("%w: more than %d bytes decompressed", ErrArchiveLimit, w.maxTotal())
+	case n > entryLimit:
+		return nil, &EntryError{Path: name, Err: fmt.Errorf("%w: larger than %d bytes", ErrArchiveLimit, entryLimit)}
+	}
+	return content, nil
+}
+
+// load runs the delegate loader on one entry. Packages the delegate
+// decompresses, such as Office and EPUB files, draw on what is left of
+// the entry and total limits.
+func (w *archiveWalk) load(name string, loader Loader, content []byte) error {
+	limit := min(w.maxEntry()-int64(len(content)), w.maxTotal()-w.total, DefaultPackageMaxSize)
+	budget := &sizeBudget{limit: limit, remaining: limit}
+	result, err := loader.Load(withSizeBudget(w.ctx, budget), bytes.NewReader(content))
+	w.total += budget.limit - budget.remaining
+	if err != nil {
+		return &EntryError{Path: name, Err: err}
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]string)
+	}
+	result.Metadata["source_path"] = name
+	result.Metadata[MetaRecordID] = name
+	w.results = append(w.results, result)
+	return nil
+}
+
+func (w *archiveWalk) maxEntry() int64 {
+	if w.l.MaxEntrySize > 0 {
+		return w.l.MaxEntrySize
+	}
+	return DefaultArchiveMaxEntrySize
+}
+
+func (w *archiveWalk) maxTotal() int64 {
+	if w.l.MaxTotalSize > 0 {
+		return w.l.MaxTotalSize
+	}
+	return DefaultArchiveMaxTotalSize
+}
@@ -0,0 +1,86 @@
+package loader_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+func TestArchiveLoader(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"docs/a.txt": "alpha", "docs/b.bin": "skipped", "c.md": "# gamma"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l := loader.NewArchiveLoader(loader.NewTextLoader(), loader.NewMarkdownLoader())
+	results, err := l.LoadArchive(context.Background(), bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, r := range results {
+		got[r.Metadata["source_path"]] = r.Content
+	}
+	if len(got) != 2 || got["docs/a.txt"] != "alpha" || got["c.md"] != "gamma" {
+		t.Errorf("results = %v", got)
+	}
+
+	// A zip entry that decompresses past the limit is rejected.
+	zipped := buildZip(t, map[string]string{"big.txt": strings.Repeat("a", 1<<16)})
+	l = loader.NewArchiveLoader(loader.NewTextLoader())
+	l.MaxEntrySize = 1 << 10
+	if _, err := l.LoadArchive(context.Background(), bytes.NewReader(zipped)); !errors.Is(err, loader.ErrArchiveLimit) {
+		t.Errorf("err = %v, want ErrArchiveLimit", err)
+	}
+}
+
+func TestArchiveLoaderEntryErrors(t *testing.T) {
+	// The DOCX is small but its document part decompresses past the entry
+	// limit; the broken DOCX is not a zip at all. Neither stops the walk.
+	bomb := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document ` + nsW + `><w:body><w:p><w:r><w:t>` + strings.Repeat("a", 1<<16) + `</w:t></w:r></w:p></w:body></w:document>`,
+	})
+	zipped := buildZip(t, map[string]string{
+		"bomb.docx":   string(bomb),
+		"broken.docx": "not a zip",
+		"notes.txt":   "kept",
+	})
+
+	l := loader.NewArchiveLoader(loader.NewTextLoader(), loader.NewOfficeLoader())
+	l.MaxEntrySize = 1 << 12
+	results, err := l.LoadArchive(context.Background(), bytes.NewReader(zipped))
+	if len(results) != 1 || results[0].Content != "kept" {
+		t.Errorf("results = %+v", results)
+	}
+
+	failed := map[string]error{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var entryErr *loader.EntryError
+		if !errors.As(e, &entryErr) {
+			t.Fatalf("err = %v, want *EntryError", e)
+		}
+		failed[entryErr.Path] = entryErr.Err
+	}
+	if len(failed) != 2 || !errors.Is(failed["bomb.docx"], loader.ErrArchiveLimit) || failed["broken.docx"] == nil {
+		t.Errorf("failed entries = %v", failed)
+	}
+}
@@ -0,0 +1,467 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// MetaCrawlDepth is the number of links a Crawler followed from a seed to
+// reach a page.
+const MetaCrawlDepth = "crawl_depth"
+
+// Default Crawler limits.
+const (
+	DefaultCrawlMaxDepth    = 3
+	DefaultCrawlMaxPages    = 500
+	DefaultCrawlConcurrency = 4
+)
+
+// Crawler walks a website from seed URLs, fetching pages with a URLLoader
+// and loading each with the delegate for its Content-Type. A seed that is
+// a sitemap (a <urlset> or <sitemapindex> document) adds its URLs to the
+// crawl. HTML pages are scanned for links, which are followed within the
+// allowed hosts and path prefixes. robots.txt is honored, and pages are
+// deduplicated by their canonical URL, which becomes "source_url".
+type Crawler struct {
+	urls *URLLoader
+
+	// AllowedHosts lists the hosts links may lead to (default: the hosts
+	// of the seeds). An entry matches with or without a port.
+	AllowedHosts []string
+	// PathPrefixes, when set, limits followed links and sitemap entries
+	// to URLs whose path starts with one of them. Seeds are always
+	// fetched.
+	PathPrefixes []string
+	// MaxDepth is the number of links followed from a seed (default
+	// DefaultCrawlMaxDepth). A negative value fetches only the seeds and
+	// their sitemap entries.
+	MaxDepth int
+	// MaxPages caps the number of pages fetched (default
+	// DefaultCrawlMaxPages).
+	MaxPages int
+	// Concurrency is the number of pages fetched at once (default
+	// DefaultCrawlConcurrency).
+	Concurrency int
+	// IgnoreRobots skips robots.txt.
+	IgnoreRobots bool
+}
+
+// NewCrawler creates a Crawler that fetches pages with urls. Set
+// urls.UserAgent to identify the crawler; its robots.txt group is chosen
+// by that name.
+func NewCrawler(urls *URLLoader) *Crawler {
+	return &Crawler{urls: urls}
+}
+
+// PageError is a failure to fetch or load one page of a crawl.
+type PageError struct {
+	URL string
+	Err error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("weave: crawl %s: %v", e.URL, e.Err)
+}
+
+func (e *PageError) Unwrap() error { return e.Err }
+
+// Crawl crawls from the seeds and returns a load result per page, in
+// crawl order. Pages that fail do not stop the crawl; their *PageError
+// values are joined into the returned error.
+func (c *Crawler) Crawl(ctx context.Context, seeds ...string) ([]*LoadResult, error) {
+	type loaded struct {
+		seq    int
+		result *LoadResult
+	}
+	var all []loaded
+	var errs []error
+	err := c.crawl(ctx, seeds, func(seq int, result *LoadResult, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			all = append(all, loaded{seq, result})
+		}
+		return nil
+	})
+	sort.Slice(all, func(i, j int) bool { return all[i].seq < all[j].seq })
+	results := make([]*LoadResult, len(all))
+	for i, a := range all {
+		results[i] = a.result
+	}
+	if err != nil {
+		return results, err
+	}
+	return results, errors.Join(errs...)
+}
+
+// EachPage crawls from the seeds and calls fn with each loaded page, or
+// with a *PageError for a page that failed. Calls are serialized but come
+// in no particular order. An error returned by fn stops the crawl and is
+// returned.
+func (c *Crawler) EachPage(ctx context.Context, seeds []string, fn func(*LoadResult, error) error) error {
+	return c.crawl(ctx, seeds, func(_ int, result *LoadResult, err error) error {
+		return fn(result, err)
+	})
+}
+
+// crawlTarget is a URL waiting to be fetched, numbered in discovery order.
+type crawlTarget struct {
+	seq   int
+	url   *url.URL
+	depth int
+	seed  bool
+}
+
+// crawlState is the state of one crawl.
+type crawlState struct {
+	c       *Crawler
+	hosts   map[string]bool
+	mu      sync.Mutex
+	seen    map[string]bool
+	emitted map[string]bool
+	robots  map[string]*robotsRules
+	next    []crawlTarget
+	seq     int
+	fetched int
+}
+
+func (c *Crawler) crawl(ctx context.Context, seeds []string, fn func(int, *LoadResult, error) error) error {
+	s := &crawlState{
+		c:       c,
+		hosts:   make(map[string]bool),
+		seen:    make(map[string]bool),
+		emitted: make(map[string]bool),
+		robots:  make(map[string]*robotsRules),
+	}
+	for _, h := range c.AllowedHosts {
+		s.hosts[strings.ToLower(h)] = true
+	}
+	for _, raw := range seeds {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("weave: crawl: invalid seed %q", raw)
+		}
+		if len(c.AllowedHosts) == 0 {
+			s.hosts[strings.ToLower(u.Host)] = true
+		}
+		s.enqueue(u, 0, true)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var reportMu sync.Mutex
+	var stopErr error
+	report := func(seq int, result *LoadResult, err error) {
+		reportMu.Lock()
+		defer reportMu.Unlock()
+		if stopErr != nil {
+			return
+		}
+		if stopErr = fn(seq, result, err); stopErr != nil {
+			cancel()
+		}
+	}
+
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCrawlConcurrency
+	}
+	for len(s.next) > 0 {
+		round := s.next
+		s.next = nil
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for _, target := range round {
+			if ctx.Err() != nil || !s.take() {
+				break
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				result, err := s.visit(ctx, target)
+				if result != nil || err != nil {
+					report(target.seq, result, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if stopErr != nil {
+			return stopErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// take counts a page against MaxPages and reports whether it may be
+// fetched.
+func (s *crawlState) take() bool {
+	limit := s.c.MaxPages
+	if limit <= 0 {
+		limit = DefaultCrawlMaxPages
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetched >= limit {
+		return false
+	}
+	s.fetched++
+	return true
+}
+
+// enqueue schedules a URL for the next round unless it was seen before.
+func (s *crawlState) enqueue(u *url.URL, depth int, seed bool) {
+	key := normalizeURL(u)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[key] {
+		return
+	}
+	s.seen[key] = true
+	s.next = append(s.next, crawlTarget{seq: s.seq, url: u, depth: depth, seed: seed})
+	s.seq++
+}
+
+// follow reports whether a discovered URL is within the crawl's scope.
+func (s *crawlState) follow(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if !s.hosts[strings.ToLower(u.Host)] && !s.hosts[strings.ToLower(u.Hostname())] {
+		return false
+	}
+	if len(s.c.PathPrefixes) == 0 {
+		return true
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	for _, prefix := range s.c.PathPrefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// visit fetches one target, queues what it links to, and loads it. It
+// returns nil and no error for pages that are skipped.
+func (s *crawlState) visit(ctx context.Context, target crawlTarget) (*LoadResult, error) {
+	if !s.c.IgnoreRobots && !s.robotsFor(ctx, target.url).allowed(target.url) {
+		return nil, nil
+	}
+	page, err := s.c.urls.fetch(ctx, target.url.String())
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		return nil, &PageError{URL: target.url.String(), Err: err}
+	}
+	// Redirects may leave the allowed hosts or land on a known page.
+	if !target.seed && !s.follow(page.url) {
+		return nil, nil
+	}
+	if final := normalizeURL(page.url); final != normalizeURL(target.url) {
+		s.mu.Lock()
+		dup := s.seen[final]
+		s.seen[final] = true
+		s.mu.Unlock()
+		if dup {
+			return nil, nil
+		}
+	}
+
+	switch page.mimeType {
+	case "application/xml", "text/xml":
+		if locs, ok := sitemapLocs(page.body); ok {
+			for _, loc := range locs {
+				if u, err := page.url.Parse(loc); err == nil && s.follow(u) {
+					s.enqueue(u, target.depth, false)
+				}
+			}
+			return nil, nil
+		}
+	case "text/html", "application/xhtml+xml":
+		maxDepth := s.c.MaxDepth
+		if maxDepth == 0 {
+			maxDepth = DefaultCrawlMaxDepth
+		}
+		if target.depth < maxDepth {
+			for _, u := range htmlLinks(page.url, page.body) {
+				if s.follow(u) {
+					s.enqueue(u, target.depth+1, false)
+				}
+			}
+		}
+	}
+
+	loader := pickLoader(s.c.urls.loaders, page.mimeType)
+	if loader == nil {
+		return nil, nil
+	}
+	result, err := loader.Load(ctx, bytes.NewReader(page.body))
+	if err != nil {
+		return nil, &PageError{URL: page.url.String(), Err: err}
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]string)
+	}
+
+	// Pages are identified by their canonical URL when it is in scope.
+	source := page.url
+	if canonical := result.Metadata["canonical_url"]; canonical != "" {
+		if u, err := page.url.Parse(canonical); err == nil && s.follow(u) {
+			source = u
+		}
+	}
+	key := normalizeURL(source)
+	s.mu.Lock()
+	dup := s.emitted[key]
+	s.emitted[key] = true
+	s.seen[key] = true
+	s.mu.Unlock()
+	if dup {
+		return nil, nil
+	}
+
+	source.Fragment = ""
+	result.Metadata["source_url"] = source.String()
+	result.Metadata[MetaCrawlDepth] = strconv.Itoa(target.depth)
+	return result, nil
+}
+
+// robotsFor returns the robots.txt rules of a URL's host, fetching them
+// on first use.
+func (s *crawlState) robotsFor(ctx context.Context, u *url.URL) *robotsRules {
+	origin := u.Scheme + "://" + u.Host
+	s.mu.Lock()
+	rules, ok := s.robots[origin]
+	s.mu.Unlock()
+	if ok {
+		return rules
+	}
+
+	rules = &robotsRules{}
+	page, err := s.c.urls.fetch(ctx, origin+"/robots.txt")
+	var status statusError
+	errors.As(err, &status)
+	switch {
+	case err == nil:
+		agent := s.c.urls.UserAgent
+		if agent == "" {
+			agent = "weave"
+		}
+		if parsed, perr := parseRobots(bytes.NewReader(page.body), agent); perr == nil {
+			rules = parsed
+		}
+	case status >= http.StatusBadRequest && status < http.StatusInternalServerError:
+		// No robots.txt: everything is allowed.
+	default:
+		rules.disallowAll = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.robots[origin]; ok {
+		return existing
+	}
+	s.robots[origin] = rules
+	return rules
+}
+
+// normalizeURL returns the form of a URL used to detect duplicates: no
+// fragment, lowercase scheme and host, no default port and a non-empty
+// path.
+func normalizeURL(u *url.URL) string {
+	n := *u
+	n.Fragment, n.RawFragment = "", ""
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	if port := n.Port(); (n.Scheme == "http" && port == "80") || (n.Scheme == "https" && port == "443") {
+		n.Host = n.Hostname()
+	}
+	if n.Path == "" {
+		n.Path = "/"
+	}
+	return n.String()
+}
+
+// htmlLinks returns the targets of the <a href> links of an HTML page,
+// resolved against the page URL or its <base href>. Links marked
+// rel="nofollow" are skipped.
+func htmlLinks(base *url.URL, body []byte) []*url.URL {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	var links []*url.URL
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if u, err := base.Parse(htmlAttr(n, "href")); err == nil && htmlAttr(n, "href") != "" {
+					base = u
+				}
+			case "a":
+				href := strings.TrimSpace(htmlAttr(n, "href"))
+				nofollow := strings.Contains(" "+strings.ToLower(htmlAttr(n, "rel"))+" ", " nofollow ")
+				if href != "" && !strings.HasPrefix(href, "#") && !nofollow {
+					if u, err := base.Parse(href); err == nil {
+						u.Fragment, u.RawFragment = "", ""
+						links = append(links, u)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return links
+}
+
+// sitemapLocs returns the <loc> entries of a sitemap or sitemap index,
+// and false when body is not a sitemap.
+func sitemapLocs(body []byte) ([]string, bool) {
+	var doc struct {
+		XMLName xml.Name
+		URLs    []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, false
+	}
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return nil, false
+	}
+	var locs []string
+	for _, u := range doc.URLs {
+		locs = append(locs, strings.TrimSpace(u.Loc))
+	}
+	for _, sm := range doc.Sitemaps {
+		locs = append(locs, strings.TrimSpace(sm.Loc))
+	}
+	return locs, true
+}
@@ -0,0 +1,97 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	page := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, strings.ReplaceAll(body, "{{host}}", srv.URL))
+		}
+	}
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n\nUser-agent: weavebot\nDisallow: /private/\nDisallow: /drafts/\nAllow: /drafts/public$\n")
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%s/docs/orphan</loc></url></urlset>`, srv.URL)
+	})
+	mux.HandleFunc("/docs/", page(`<html><head><title>Home</title></head><body>
+		<a href="/docs/a">A</a> <a href="b">B</a> <a href="/docs/a#section">A again</a>
+		<a href="/private/secret">Secret</a> <a href="/drafts/wip">WIP</a> <a href="/drafts/public">Public draft</a>
+		<a href="/blog/post">Blog</a> <a href="https://elsewhere.example/x">Away</a>
+		<a href="/docs/notes.txt">Notes</a> <a href="/docs/missing">Missing</a></body></html>`))
+	mux.HandleFunc("/docs/a", page(`<html><head><title>A</title></head><body><p>Page A</p><a href="/docs/deep">Deep</a></body></html>`))
+	mux.HandleFunc("/docs/b", page(`<html><head><title>B</title><link rel="canonical" href="{{host}}/docs/a"></head><body><p>Page A</p></body></html>`))
+	mux.HandleFunc("/docs/deep", page(`<html><body><p>Deep</p><a href="/docs/deeper">Deeper</a></body></html>`))
+	mux.HandleFunc("/docs/deeper", page(`<html><body><p>Deeper</p></body></html>`))
+	mux.HandleFunc("/docs/orphan", page(`<html><body><p>Only in the sitemap</p></body></html>`))
+	mux.HandleFunc("/docs/notes.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "plain notes")
+	})
+	mux.HandleFunc("/docs/missing", http.NotFound)
+	mux.HandleFunc("/drafts/", page(`<html><body><p>Draft</p></body></html>`))
+	mux.HandleFunc("/private/", page(`<html><body><p>Secret</p></body></html>`))
+	mux.HandleFunc("/blog/", page(`<html><body><p>Blog</p></body></html>`))
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCrawler(t *testing.T) {
+	srv := newTestSite(t)
+	urls := loader.NewURLLoaderWithClient(srv.Client(), loader.NewHTMLLoader(), loader.NewTextLoader())
+	urls.UserAgent = "WeaveBot/1.0"
+	c := loader.NewCrawler(urls)
+	c.PathPrefixes = []string{"/docs/", "/drafts/"}
+	c.MaxDepth = 1
+
+	results, err := c.Crawl(context.Background(), srv.URL+"/docs/", srv.URL+"/sitemap.xml")
+	var pageErr *loader.PageError
+	if !errors.As(err, &pageErr) || !strings.HasSuffix(pageErr.URL, "/docs/missing") {
+		t.Fatalf("err = %v, want PageError for /docs/missing", err)
+	}
+
+	depths := make(map[string]string)
+	var got []string
+	for _, r := range results {
+		path := strings.TrimPrefix(r.Metadata["source_url"], srv.URL)
+		got = append(got, path)
+		depths[path] = r.Metadata[loader.MetaCrawlDepth]
+	}
+	sort.Strings(got)
+	// /docs/b is canonically /docs/a, /docs/deep is beyond MaxDepth, and
+	// robots.txt blocks /private/ and all drafts but /drafts/public.
+	want := []string{"/docs/", "/docs/a", "/docs/notes.txt", "/docs/orphan", "/drafts/public"}
+	if !slices.Equal(got, want) {
+		t.Errorf("crawled %v, want %v", got, want)
+	}
+	if depths["/docs/a"] != "1" || depths["/docs/orphan"] != "0" {
+		t.Errorf("depths = %v", depths)
+	}
+
+	// MaxPages stops the crawl.
+	c = loader.NewCrawler(urls)
+	c.MaxPages = 2
+	c.Concurrency = 1
+	results, _ = c.Crawl(context.Background(), srv.URL+"/docs/")
+	if len(results) != 2 {
+		t.Errorf("MaxPages=2 crawled %d pages", len(results))
+	}
+}
@@ -1,17 +1,55 @@
 package loader
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
 )
 
-// CSVLoader loads CSV files, joining rows into text.
+// MetaRecordID is the value of the ID column of a record, set by
+// CSVLoader when IDColumn is configured. It is stable across loads, so
+// callers can use it to upsert records rather than duplicate them.
+const MetaRecordID = "record_id"
+
+// CSVLoader loads CSV files. Load joins all rows into one text; LoadRecords
+// and EachRecord treat the first row as a header and return each data row
+// as its own result.
 type CSVLoader struct {
 	// Separator between columns in output (default: " | ").
 	Separator string
+	// Delimiter is the input field delimiter. Zero detects ',', ';', tab
+	// or '|' from the first line.
+	Delimiter rune
+	// Encoding is the input character set, by its WHATWG name such as
+	// "windows-1252". Empty detects UTF-8 and UTF-16 byte order marks and
+	// falls back to Windows-1252 when the input is not valid UTF-8.
+	Encoding string
+
+	// ContentColumns lists the columns that form the text of a record, as
+	// "column: value" lines. Empty means all columns.
+	ContentColumns []string
+	// ContentTemplate, when set, renders the text of a record instead. It
+	// is a text/template executed over the row as a map from column name
+	// to value, such as "Q: {{.question}}\nA: {{.answer}}".
+	ContentTemplate string
+	// MetadataColumns maps metadata keys to column names. Mapping a column
+	// to "title" names the document.
+	MetadataColumns map[string]string
+	// IDColumn is recorded as MetaRecordID. Rows with an empty ID fail.
+	IDColumn string
 }
 
 // NewCSVLoader creates a new CSVLoader.
@@ -21,8 +59,10 @@ func NewCSVLoader() *CSVLoader {
 
 // Load reads CSV and returns joined text content.
 func (l *CSVLoader) Load(_ context.Context, reader io.Reader) (*LoadResult, error) {
-	r := csv.NewReader(reader)
-	r.FieldsPerRecord = -1 // Allow variable column counts.
+	r, err := l.reader(reader)
+	if err != nil {
+		return nil, err
+	}
 
 	records, err := r.ReadAll()
 	if err != nil {
@@ -49,7 +89,246 @@ func (l *CSVLoader) Load(_ context.Context, reader io.Reader) (*LoadResult, erro
 	}, nil
 }
 
+// LoadRecords returns one load result per data row. Use EachRecord for
+// files too large to hold in memory.
+func (l *CSVLoader) LoadRecords(ctx context.Context, reader io.Reader) ([]*LoadResult, error) {
+	var results []*LoadResult
+	err := l.EachRecord(ctx, reader, func(result *LoadResult) error {
+		results = append(results, result)
+		return nil
+	})
+	return results, err
+}
+
+// EachRecord streams the data rows of a CSV file with a header row,
+// calling fn with one load result per row. Each result records
+// MetaRecordIndex (the 0-based data row), MetaRecordID when IDColumn is
+// set, and the MetadataColumns. Rows whose text is empty are skipped. An
+// error from fn stops the scan and is returned.
+func (l *CSVLoader) EachRecord(ctx context.Context, reader io.Reader, fn func(*LoadResult) error) error {
+	r, err := l.reader(reader)
+	if err != nil {
+		return err
+	}
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("weave: csv load: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	render, err := l.renderer(header)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, dup := columns[name]; !dup {
+			columns[name] = i
+		}
+	}
+	for _, name := range l.namedColumns() {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("weave: csv load: unknown column %q", name)
+		}
+	}
+
+	for index := 0; ; index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("weave: csv load: %w", err)
+		}
+		value := func(name string) string {
+			if i := columns[name]; i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		content, err := render(row, value)
+		if err != nil {
+			return err
+		}
+		if content == "" {
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		meta := map[string]string{MetaRecordIndex: strconv.Itoa(index)}
+		for key, name := range l.MetadataColumns {
+			if v := value(name); v != "" {
+				meta[key] = v
+			}
+		}
+		if l.IDColumn != "" {
+			id := value(l.IDColumn)
+			if id == "" {
+				return fmt.Errorf("weave: csv line %d: empty %s", line, l.IDColumn)
+			}
+			meta[MetaRecordID] = id
+		}
+		if err := fn(&LoadResult{Content: content, Metadata: meta, MimeType: "text/csv"}); err != nil {
+			return err
+		}
+	}
+}
+
 // Supports returns true for CSV MIME types.
 func (l *CSVLoader) Supports(mimeType string) bool {
 	return mimeType == "text/csv"
 }
+
+// namedColumns returns the columns named by MetadataColumns, IDColumn
+// and ContentColumns.
+func (l *CSVLoader) namedColumns() []string {
+	names := make([]string, 0, len(l.MetadataColumns)+len(l.ContentColumns)+1)
+	for _, name := range l.MetadataColumns {
+		names = append(names, name)
+	}
+	if l.IDColumn != "" {
+		names = append(names, l.IDColumn)
+	}
+	if l.ContentTemplate == "" {
+		names = append(names, l.ContentColumns...)
+	}
+	return names
+}
+
+// renderer returns the function that builds the text of a row.
+func (l *CSVLoader) renderer(header []string) (func(row []string, value func(string) string) (string, error), error) {
+	if l.ContentTemplate != "" {
+		tmpl, err := template.New("csv").Option("missingkey=zero").Parse(l.ContentTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("weave: parse csv template: %w", err)
+		}
+		var b strings.Builder
+		return func(_ []string, value func(string) string) (string, error) {
+			data := make(map[string]string, len(header))
+			for _, name := range header {
+				if _, dup := data[name]; !dup {
+					data[name] = value(name)
+				}
+			}
+			b.Reset()
+			if err := tmpl.Execute(&b, data); err != nil {
+				return "", fmt.Errorf("weave: render csv template: %w", err)
+			}
+			return strings.TrimSpace(b.String()), nil
+		}, nil
+	}
+
+	return func(row []string, value func(string) string) (string, error) {
+		var b strings.Builder
+		write := func(name, v string) {
+			if v == "" {
+				return
+			}
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(name + ": " + v)
+		}
+		if len(l.ContentColumns) > 0 {
+			for _, name := range l.ContentColumns {
+				write(name, value(name))
+			}
+		} else {
+			for i, name := range header {
+				if i < len(row) {
+					write(name, strings.TrimSpace(row[i]))
+				}
+			}
+		}
+		return b.String(), nil
+	}, nil
+}
+
+// reader decodes the input to UTF-8 and returns a csv.Reader with the
+// configured or detected delimiter.
+func (l *CSVLoader) reader(reader io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReaderSize(reader, 64<<10)
+	sample, err := br.Peek(64 << 10)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("weave: csv load: %w", err)
+	}
+
+	var enc encoding.Encoding
+	switch {
+	case l.Encoding != "":
+		if enc, err = htmlindex.Get(l.Encoding); err != nil {
+			return nil, fmt.Errorf("weave: csv load: %w", err)
+		}
+	case bytes.HasPrefix(sample, []byte{0xff, 0xfe}), bytes.HasPrefix(sample, []byte{0xfe, 0xff}):
+		enc = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	case !utf8.Valid(trimPartialRune(sample)):
+		enc = charmap.Windows1252
+	}
+	var in io.Reader = br
+	if enc != nil {
+		in = enc.NewDecoder().Reader(br)
+	}
+	// Strip a UTF-8 byte order mark, including one produced by decoding.
+	text := bufio.NewReaderSize(in, 64<<10)
+	if r, _, err := text.ReadRune(); err == nil && r != '\uFEFF' {
+		_ = text.UnreadRune()
+	}
+
+	r := csv.NewReader(text)
+	r.FieldsPerRecord = -1 // Allow variable column counts.
+	r.Comma = l.Delimiter
+	if r.Comma == 0 {
+		first, _ := text.Peek(64 << 10)
+		r.Comma = detectDelimiter(first)
+	}
+	return r, nil
+}
+
+// detectDelimiter picks the candidate delimiter that occurs most often
+// outside quotes on the first line, defaulting to a comma.
+func detectDelimiter(sample []byte) rune {
+	counts := make(map[byte]int)
+	inQuote := false
+	for _, c := range sample {
+		if c == '"' {
+			inQuote = !inQuote
+			continue
+		}
+		if inQuote {
+			continue
+		}
+		if c == '\n' {
+			break
+		}
+		counts[c]++
+	}
+	best, bestCount := byte(','), 0
+	for _, c := range []byte{',', ';', '\t', '|'} {
+		if counts[c] > bestCount {
+			best, bestCount = c, counts[c]
+		}
+	}
+	return rune(best)
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end
+// of a sample.
+func trimPartialRune(sample []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		if utf8.RuneStart(sample[len(sample)-i]) {
+			if !utf8.FullRune(sample[len(sample)-i:]) {
+				return sample[:len(sample)-i]
+			}
+			break
+		}
+	}
+	return sample
+}
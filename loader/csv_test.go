@@ -0,0 +1,70 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+func TestCSVLoaderRecords(t *testing.T) {
+	faq := "\uFEFFid;question;answer;category\n" +
+		"q1;How do I reset my password?;\"Use the \"\"Forgot password\"\" link.\";account\n" +
+		"q2;Can I export my data?;\"Yes; from Settings, choose Export.\";data\n"
+
+	l := &loader.CSVLoader{
+		ContentTemplate: "Q: {{.question}}\nA: {{.answer}}",
+		MetadataColumns: map[string]string{"title": "question", "category": "category"},
+		IDColumn:        "id",
+	}
+	results, err := l.LoadRecords(context.Background(), strings.NewReader(faq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d records, want 2", len(results))
+	}
+	second := results[1]
+	if second.Content != "Q: Can I export my data?\nA: Yes; from Settings, choose Export." {
+		t.Errorf("content = %q", second.Content)
+	}
+	for k, want := range map[string]string{
+		"title":                "Can I export my data?",
+		"category":             "data",
+		loader.MetaRecordID:    "q2",
+		loader.MetaRecordIndex: "1",
+	} {
+		if got := second.Metadata[k]; got != want {
+			t.Errorf("Metadata[%q] = %q, want %q", k, got, want)
+		}
+	}
+
+	// Windows-1252 input with tab delimiters and a column subset.
+	latin := "name\tcity\tnotes\nJos\xe9\tM\xe1laga\t\n"
+	l = &loader.CSVLoader{ContentColumns: []string{"name", "city"}}
+	results, err = l.LoadRecords(context.Background(), strings.NewReader(latin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Content != "name: José\ncity: Málaga" {
+		t.Fatalf("records = %+v", results)
+	}
+
+	l = &loader.CSVLoader{MetadataColumns: map[string]string{"sku": "SKU"}}
+	if _, err := l.LoadRecords(context.Background(), strings.NewReader(latin)); err == nil {
+		t.Error("expected error for unknown column")
+	}
+
+	// EachRecord stops when the callback fails.
+	stop := errors.New("stop")
+	calls := 0
+	err = (&loader.CSVLoader{}).EachRecord(context.Background(), strings.NewReader(faq), func(*loader.LoadResult) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("EachRecord = %v after %d calls", err, calls)
+	}
+}
@@ -1,18 +1,50 @@
 package loader
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // DirectoryLoader recursively walks a directory and loads files using delegate loaders.
 type DirectoryLoader struct {
 	loaders []Loader
+
+	// Include limits loading to files matching at least one glob. Globs
+	// are matched against the slash-separated path relative to the
+	// directory; "**" matches any number of segments and a glob without a
+	// slash matches the file name at any depth, as in "*.md".
+	Include []string
+	// Exclude skips files and directories matching any glob.
+	Exclude []string
+	// IgnoreFiles names .gitignore-style files honored in every
+	// directory, such as DefaultIgnoreFiles.
+	IgnoreFiles []string
+	// MaxFileSize skips files larger than this many bytes. Zero means no
+	// limit.
+	MaxFileSize int64
+	// SkipHidden skips files and directories whose names start with a dot.
+	SkipHidden bool
+	// SkipBinary skips files bound for a text loader that contain NUL
+	// bytes.
+	SkipBinary bool
+	// Concurrency is the number of files loaded at once (default 1).
+	Concurrency int
+	// Manifest, when set, makes scans incremental: only files that are new
+	// or changed since the manifest was last updated are loaded. Files no
+	// longer found are listed by Manifest.Removed after a complete scan.
+	Manifest *Manifest
 }
 
 // NewDirectoryLoader creates a DirectoryLoader that delegates to the given loaders.
@@ -20,26 +52,170 @@ func NewDirectoryLoader(loaders ...Loader) *DirectoryLoader {
 	return &DirectoryLoader{loaders: loaders}
 }
 
+// FileError is a failure to read or load one file of a directory scan.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("weave: directory load %s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error { return e.Err }
+
+// ErrSkipFile can be returned by an EachFile callback to pass over a file
+// without stopping the walk. The file is not recorded in the Manifest, so
+// the next incremental scan loads it again.
+var ErrSkipFile = errors.New("weave: skip file")
+
 // Load is not supported for DirectoryLoader — use LoadDir instead.
 func (l *DirectoryLoader) Load(_ context.Context, _ io.Reader) (*LoadResult, error) {
 	return nil, fmt.Errorf("weave: DirectoryLoader.Load not supported; use LoadDir")
 }
 
-// LoadDir recursively walks a directory and returns load results for each file.
+// LoadDir recursively walks a directory and returns load results for each
+// file, in walk order. Files that fail do not stop the walk; their
+// *FileError values are joined into the returned error.
 func (l *DirectoryLoader) LoadDir(ctx context.Context, dirPath string) ([]*LoadResult, error) {
-	var results []*LoadResult
+	type loaded struct {
+		seq    int
+		result *LoadResult
+	}
+	var all []loaded
+	var errs []error
+	err := l.walk(ctx, dirPath, func(seq int, result *LoadResult, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			all = append(all, loaded{seq, result})
+		}
+		return nil
+	})
+	sort.Slice(all, func(i, j int) bool { return all[i].seq < all[j].seq })
+	results := make([]*LoadResult, len(all))
+	for i, a := range all {
+		results[i] = a.result
+	}
+	if err != nil {
+		return results, err
+	}
+	return results, errors.Join(errs...)
+}
 
+// EachFile walks a directory and calls fn with each loaded file, or with
+// a *FileError for a file that could not be read or loaded. Calls are
+// serialized, but with Concurrency above 1 they come in no particular
+// order. A file is recorded in the Manifest only once fn returns nil for
+// it. Returning ErrSkipFile passes over the file; any other error stops the
+// walk and is returned.
+func (l *DirectoryLoader) EachFile(ctx context.Context, dirPath string, fn func(*LoadResult, error) error) error {
+	return l.walk(ctx, dirPath, func(_ int, result *LoadResult, err error) error {
+		return fn(result, err)
+	})
+}
+
+// dirJob is a file picked for loading, numbered in walk order.
+type dirJob struct {
+	seq    int
+	path   string
+	rel    string
+	info   os.FileInfo
+	mime   string
+	loader Loader
+}
+
+func (l *DirectoryLoader) walk(ctx context.Context, dirPath string, fn func(int, *LoadResult, error) error) error {
 	root, err := os.OpenRoot(dirPath)
 	if err != nil {
-		return nil, fmt.Errorf("weave: open root dir: %w", err)
+		return fmt.Errorf("weave: open root dir: %w", err)
 	}
 	defer root.Close()
 
-	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, walkErr error) error {
-		if walkErr != nil {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if l.Manifest != nil {
+		l.Manifest.begin()
+	}
+
+	var mu sync.Mutex
+	var stopErr error
+	// report passes a file to fn and reports whether fn accepted it.
+	report := func(seq int, result *LoadResult, err error) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopErr != nil {
+			return false
+		}
+		switch fnErr := fn(seq, result, err); {
+		case fnErr == nil:
+			return true
+		case errors.Is(fnErr, ErrSkipFile):
+			return false
+		default:
+			stopErr = fnErr
+			cancel()
+			return false
+		}
+	}
+
+	jobs := make(chan dirJob)
+	var wg sync.WaitGroup
+	for range max(l.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				result, state, err := l.loadFile(ctx, root, job)
+				if result == nil && err == nil {
+					continue
+				}
+				if report(job.seq, result, err) && result != nil && l.Manifest != nil {
+					l.Manifest.record(job.rel, state)
+				}
+			}
+		}()
+	}
+
+	var rules []ignoreRule
+	seq := 0
+	walkErr := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil && path == dirPath {
 			return walkErr
 		}
-		if info.IsDir() {
+		rel, relErr := filepath.Rel(dirPath, path)
+		if relErr != nil {
+			return fmt.Errorf("weave: directory load rel path: %w", relErr)
+		}
+		rel = filepath.ToSlash(rel)
+		if walkErr != nil {
+			// An unreadable directory is not evidence that its files are
+			// gone, so the manifest keeps them.
+			if l.Manifest != nil {
+				l.Manifest.keep(rel)
+			}
+			report(seq, nil, &FileError{Path: path, Err: walkErr})
+			seq++
+			return nil
+		}
+		if rel != "." && l.skip(rules, rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			for _, name := range l.IgnoreFiles {
+				dirRules, err := readIgnore(root, rel, name)
+				if err != nil {
+					report(seq, nil, &FileError{Path: filepath.Join(path, name), Err: err})
+					seq++
+				}
+				rules = append(rules, dirRules...)
+			}
 			return nil
 		}
 
@@ -48,38 +224,143 @@ func (l *DirectoryLoader) LoadDir(ctx context.Context, dirPath string) ([]*LoadR
 		if loader == nil {
 			return nil // Skip unsupported files.
 		}
-
-		relPath, relErr := filepath.Rel(dirPath, path)
-		if relErr != nil {
-			return fmt.Errorf("weave: directory load rel path: %w", relErr)
+		info, err := d.Info()
+		if err != nil {
+			if l.Manifest != nil {
+				l.Manifest.keep(rel)
+			}
+			report(seq, nil, &FileError{Path: path, Err: err})
+			seq++
+			return nil
 		}
-		f, openErr := root.Open(relPath)
-		if openErr != nil {
-			return fmt.Errorf("weave: directory load: %w", openErr)
+		if !info.Mode().IsRegular() && info.Mode()&fs.ModeSymlink == 0 {
+			return nil
 		}
-		defer func() { _ = f.Close() }()
-
-		result, loadErr := loader.Load(ctx, f)
-		if loadErr != nil {
-			return fmt.Errorf("weave: directory load %s: %w", path, loadErr)
+		if l.MaxFileSize > 0 && info.Size() > l.MaxFileSize {
+			return nil
 		}
-
-		if result.Metadata == nil {
-			result.Metadata = make(map[string]string)
+		select {
+		case jobs <- dirJob{seq: seq, path: path, rel: rel, info: info, mime: mimeType, loader: loader}:
+			seq++
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-		result.Metadata["source_path"] = path
-		results = append(results, result)
 		return nil
 	})
+	close(jobs)
+	wg.Wait()
 
-	return results, err
+	if stopErr != nil {
+		return stopErr
+	}
+	if walkErr != nil {
+		return walkErr
+	}
+	if l.Manifest != nil {
+		l.Manifest.finish()
+	}
+	return nil
+}
+
+// skip reports whether the filters exclude a file or directory.
+func (l *DirectoryLoader) skip(rules []ignoreRule, rel string, d fs.DirEntry) bool {
+	if l.SkipHidden && strings.HasPrefix(d.Name(), ".") {
+		return true
+	}
+	if ignored(rules, rel, d.IsDir()) {
+		return true
+	}
+	for _, pattern := range l.Exclude {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	if d.IsDir() || len(l.Include) == 0 {
+		return false
+	}
+	for _, pattern := range l.Include {
+		if matchGlob(pattern, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFile reads and loads one file, returning the state to record once
+// the result is accepted. It returns nil and no error for a file that is
+// skipped as binary or unchanged.
+func (l *DirectoryLoader) loadFile(ctx context.Context, root *os.Root, job dirJob) (*LoadResult, FileState, error) {
+	if l.Manifest != nil && l.Manifest.unchanged(job.rel, job.info.ModTime(), job.info.Size()) {
+		return nil, FileState{}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, FileState{}, nil
+	}
+
+	f, err := root.Open(filepath.FromSlash(job.rel))
+	if err != nil {
+		return nil, FileState{}, &FileError{Path: job.path, Err: err}
+	}
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, FileState{}, &FileError{Path: job.path, Err: err}
+	}
+	if l.SkipBinary && isTextMime(job.mime) && bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, FileState{}, nil
+	}
+
+	state := FileState{
+		ModTime: job.info.ModTime(),
+		Size:    job.info.Size(),
+		Hash:    fmt.Sprintf("%x", sha256.Sum256(data)),
+	}
+	if l.Manifest != nil && !l.Manifest.changed(job.rel, state.Hash) {
+		// Only the modification time moved; the contents were accepted
+		// before.
+		l.Manifest.record(job.rel, state)
+		return nil, FileState{}, nil
+	}
+
+	result, err := job.loader.Load(ctx, bytes.NewReader(data))
+	if err != nil {
+		return nil, FileState{}, &FileError{Path: job.path, Err: err}
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]string)
+	}
+	result.Metadata["source_path"] = job.path
+	return result, state, nil
+}
+
+// readIgnore reads the rules of an ignore file in dir, if there is one.
+func readIgnore(root *os.Root, dir, name string) ([]ignoreRule, error) {
+	f, err := root.Open(filepath.FromSlash(path.Join(dir, name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return parseIgnore(dir, f)
+}
+
+// isTextMime reports whether a MIME type is loaded as text.
+func isTextMime(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || mimeType == MimeJSONL
 }
 
 // Supports always returns false — use LoadDir directly.
 func (l *DirectoryLoader) Supports(_ string) bool { return false }
 
 func (l *DirectoryLoader) findLoader(mimeType string) Loader {
-	for _, loader := range l.loaders {
+	return pickLoader(l.loaders, mimeType)
+}
+
+// pickLoader returns the first loader that supports mimeType, or nil.
+func pickLoader(loaders []Loader, mimeType string) Loader {
+	for _, loader := range loaders {
 		if loader.Supports(mimeType) {
 			return loader
 		}
@@ -90,8 +371,12 @@ func (l *DirectoryLoader) findLoader(mimeType string) Loader {
 // mimeFromExt returns a MIME type for common file extensions.
 func mimeFromExt(ext string) string {
 	ext = strings.ToLower(ext)
-	// Check Go's built-in MIME types first.
+	// Check Go's built-in MIME types first, without parameters such as
+	// the charset Go adds to text types.
 	if t := mime.TypeByExtension(ext); t != "" {
+		if mediaType, _, err := mime.ParseMediaType(t); err == nil {
+			return mediaType
+		}
 		return t
 	}
 	// Fallback for common types.
@@ -102,10 +387,24 @@ func mimeFromExt(ext string) string {
 		return "text/csv"
 	case ".json":
 		return "application/json"
+	case ".jsonl", ".ndjson":
+		return MimeJSONL
 	case ".html", ".htm":
 		return "text/html"
 	case ".txt":
 		return "text/plain"
+	case ".epub":
+		return "application/epub+zip"
+	case ".eml":
+		return "message/rfc822"
+	case ".pdf":
+		return "application/pdf"
+	case ".docx":
+		return MimeDOCX
+	case ".pptx":
+		return MimePPTX
+	case ".xlsx":
+		return MimeXLSX
 	default:
 		return ""
 	}
@@ -0,0 +1,213 @@
+package loader_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave/loader"
+)
+
+// failingLoader loads any text file and fails for content containing
+// "FAIL".
+type failingLoader struct{ loader.TextLoader }
+
+func (f *failingLoader) Supports(mimeType string) bool { return strings.HasPrefix(mimeType, "text/") }
+
+func (f *failingLoader) Load(ctx context.Context, r io.Reader) (*loader.LoadResult, error) {
+	res, err := f.TextLoader.Load(ctx, r)
+	if err == nil && strings.Contains(res.Content, "FAIL") {
+		return nil, errors.New("broken file")
+	}
+	return res, err
+}
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func relPaths(t *testing.T, dir string, results []*loader.LoadResult) []string {
+	t.Helper()
+	var paths []string
+	for _, r := range results {
+		rel, err := filepath.Rel(dir, r.Metadata["source_path"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths
+}
+
+func TestDirectoryLoaderFilters(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":          "build/\n*.log.txt\n",
+		"docs/.weaveignore":   "drafts/*\n!drafts/keep.md\n",
+		"docs/a.md":           "# A",
+		"docs/b.txt":          "b",
+		"docs/drafts/x.md":    "draft",
+		"docs/drafts/keep.md": "keep",
+		"docs/big.md":         strings.Repeat("x", 2048),
+		"docs/bad.md":         "FAIL",
+		"docs/bin.txt":        "a\x00b",
+		"build/out.md":        "generated",
+		"run.log.txt":         "log",
+		".hidden/h.md":        "hidden",
+		"vendor/v.md":         "vendored",
+	})
+
+	l := loader.NewDirectoryLoader(&failingLoader{})
+	l.Include = []string{"**/*.md", "*.txt"}
+	l.Exclude = []string{"vendor/**"}
+	l.IgnoreFiles = loader.DefaultIgnoreFiles
+	l.MaxFileSize = 1024
+	l.SkipHidden = true
+	l.SkipBinary = true
+	l.Concurrency = 4
+
+	results, err := l.LoadDir(context.Background(), dir)
+	var fileErr *loader.FileError
+	if !errors.As(err, &fileErr) || !strings.HasSuffix(fileErr.Path, "bad.md") {
+		t.Fatalf("err = %v, want FileError for bad.md", err)
+	}
+	want := []string{"docs/a.md", "docs/b.txt", "docs/drafts/keep.md"}
+	if got := relPaths(t, dir, results); !slices.Equal(got, want) {
+		t.Errorf("loaded %v, want %v", got, want)
+	}
+}
+
+func TestDirectoryLoaderIncremental(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "one", "b.txt": "two", "c.txt": "three"})
+
+	m := loader.NewManifest()
+	l := loader.NewDirectoryLoader(loader.NewTextLoader())
+	l.Manifest = m
+	results, err := l.LoadDir(context.Background(), dir)
+	if err != nil || len(results) != 3 {
+		t.Fatalf("first scan: %d results, err %v", len(results), err)
+	}
+
+	// Persist and restore the manifest, then change one file, touch
+	// another without changing it, and remove the third.
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if l.Manifest, err = loader.ReadManifest(&buf); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	writeTree(t, dir, map[string]string{"a.txt": "one, edited"})
+	if err := os.Chtimes(filepath.Join(dir, "b.txt"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "c.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []string
+	err = l.EachFile(context.Background(), dir, func(r *loader.LoadResult, err error) error {
+		if err != nil {
+			return err
+		}
+		seen = append(seen, r.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(seen, []string{"one, edited"}) {
+		t.Errorf("second scan loaded %q", seen)
+	}
+	if removed := l.Manifest.Removed(); !slices.Equal(removed, []string{"c.txt"}) {
+		t.Errorf("Removed() = %v", removed)
+	}
+	if l.Manifest.Len() != 2 {
+		t.Errorf("manifest has %d files, want 2", l.Manifest.Len())
+	}
+}
+
+func TestDirectoryLoaderKeepsUnreadableDirectories(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "one", "sub/b.txt": "two"})
+
+	l := loader.NewDirectoryLoader(loader.NewTextLoader())
+	l.Manifest = loader.NewManifest()
+	if _, err := l.LoadDir(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Chmod(sub, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(sub, 0o755) })
+	if _, err := os.ReadDir(sub); err == nil {
+		t.Skip("directory permissions are not enforced")
+	}
+
+	_, err := l.LoadDir(context.Background(), dir)
+	var fe *loader.FileError
+	if !errors.As(err, &fe) || fe.Path != sub {
+		t.Fatalf("expected FileError for %s, got %v", sub, err)
+	}
+	if removed := l.Manifest.Removed(); len(removed) != 0 {
+		t.Errorf("Removed() = %v, want none", removed)
+	}
+	if _, ok := l.Manifest.Get("sub/b.txt"); !ok {
+		t.Error("sub/b.txt dropped from the manifest")
+	}
+}
+
+func TestDirectoryLoaderRecordsAcceptedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "one", "b.txt": "two", "c.txt": "three"})
+
+	l := loader.NewDirectoryLoader(loader.NewTextLoader())
+	l.Manifest = loader.NewManifest()
+	stop := errors.New("stop")
+	err := l.EachFile(context.Background(), dir, func(r *loader.LoadResult, err error) error {
+		switch r.Content {
+		case "two":
+			return loader.ErrSkipFile
+		case "three":
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("EachFile error = %v, want stop", err)
+	}
+	for name, want := range map[string]bool{"a.txt": true, "b.txt": false, "c.txt": false} {
+		if _, ok := l.Manifest.Get(name); ok != want {
+			t.Errorf("%s recorded = %v, want %v", name, ok, want)
+		}
+	}
+
+	// Files that were not accepted are loaded again.
+	results, err := l.LoadDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("rescan loaded %d files, want 2", len(results))
+	}
+}
@@ -0,0 +1,243 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOCXLoader extracts text from Word documents. Headings become Markdown
+// headings, list items keep their bullets or numbers, and tables are
+// rendered as Markdown tables, so structure-aware chunkers can use them.
+type DOCXLoader struct{}
+
+// NewDOCXLoader creates a new DOCXLoader.
+func NewDOCXLoader() *DOCXLoader { return &DOCXLoader{} }
+
+// Load reads a .docx package and returns its text.
+func (l *DOCXLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	zr, err := openOffice(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := readPart(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	body := doc.child("body")
+	if body == nil {
+		return nil, errors.New("weave: docx load: missing document body")
+	}
+
+	w := &docxWriter{counters: make(map[string]int)}
+	if w.styles, err = docxStyles(zr); err != nil {
+		return nil, err
+	}
+	if w.numbering, err = docxNumbering(zr); err != nil {
+		return nil, err
+	}
+	w.blocks(body)
+
+	meta := make(map[string]string)
+	if err := coreProperties(zr, meta); err != nil {
+		return nil, err
+	}
+	return &LoadResult{
+		Content:  w.b.String(),
+		Metadata: meta,
+		MimeType: MimeDOCX,
+	}, nil
+}
+
+// Supports returns true for DOCX.
+func (l *DOCXLoader) Supports(mimeType string) bool {
+	return mimeType == MimeDOCX
+}
+
+// docxStyle is the part of a paragraph style that affects rendering.
+type docxStyle struct {
+	heading int
+	numID   string
+	ilvl    int
+}
+
+// docxWriter renders the body of a Word document.
+type docxWriter struct {
+	b         strings.Builder
+	styles    map[string]docxStyle
+	numbering map[string]map[int]string // numId -> level -> numFmt
+	counters  map[string]int
+	lastList  bool
+}
+
+// blocks renders the paragraphs and tables of a container element.
+func (w *docxWriter) blocks(n *xmlNode) {
+	for _, c := range n.Children {
+		switch c.Name {
+		case "p":
+			w.paragraph(c)
+		case "tbl":
+			rows := make([][]string, 0, len(c.Children))
+			for _, tr := range c.all("tr") {
+				var row []string
+				for _, tc := range tr.all("tc") {
+					var parts []string
+					for _, p := range tc.all("p") {
+						if text := strings.TrimSpace(docxText(p)); text != "" {
+							parts = append(parts, text)
+						}
+					}
+					row = append(row, strings.Join(parts, " "))
+				}
+				rows = append(rows, row)
+			}
+			w.write(markdownTable(rows), false)
+		case "sdt":
+			w.blocks(c.child("sdtContent"))
+		}
+	}
+}
+
+// paragraph renders one paragraph as a heading, list item or plain text.
+func (w *docxWriter) paragraph(p *xmlNode) {
+	text := strings.TrimSpace(docxText(p))
+	if text == "" {
+		return
+	}
+
+	pPr := p.child("pPr")
+	style := w.styles[pPr.child("pStyle").attr("val")]
+	if lvl := pPr.child("outlineLvl"); lvl != nil {
+		if n, err := strconv.Atoi(lvl.attr("val")); err == nil && n < 9 {
+			style.heading = n + 1
+		}
+	}
+	if numPr := pPr.child("numPr"); numPr != nil {
+		style.numID = numPr.child("numId").attr("val")
+		style.ilvl, _ = strconv.Atoi(numPr.child("ilvl").attr("val"))
+	}
+
+	switch {
+	case style.heading > 0:
+		w.write(strings.Repeat("#", min(style.heading, 6))+" "+text, false)
+	case style.numID != "" && style.numID != "0":
+		// Restart the numbering of deeper levels at each shallower item.
+		for key := range w.counters {
+			if id, lvl, _ := strings.Cut(key, "/"); id == style.numID {
+				if n, _ := strconv.Atoi(lvl); n > style.ilvl {
+					delete(w.counters, key)
+				}
+			}
+		}
+		marker := "-"
+		if format := w.numbering[style.numID][style.ilvl]; format != "" && format != "bullet" && format != "none" {
+			key := style.numID + "/" + strconv.Itoa(style.ilvl)
+			w.counters[key]++
+			marker = strconv.Itoa(w.counters[key]) + "."
+		}
+		w.write(strings.Repeat("  ", style.ilvl)+marker+" "+text, true)
+	default:
+		w.write(text, false)
+	}
+}
+
+// write appends a block. Consecutive list items are kept on adjacent
+// lines; other blocks are separated by a blank line.
+func (w *docxWriter) write(text string, list bool) {
+	if text == "" {
+		return
+	}
+	if w.b.Len() > 0 {
+		if list && w.lastList {
+			w.b.WriteString("\n")
+		} else {
+			w.b.WriteString("\n\n")
+		}
+	}
+	w.b.WriteString(text)
+	w.lastList = list
+}
+
+// docxText returns the text of the runs under n, skipping deleted text
+// and field instructions.
+func docxText(n *xmlNode) string {
+	var b strings.Builder
+	var walk func(*xmlNode)
+	walk = func(n *xmlNode) {
+		for _, c := range n.Children {
+			switch c.Name {
+			case "t":
+				b.WriteString(c.text())
+			case "tab":
+				b.WriteString("\t")
+			case "br", "cr":
+				b.WriteString("\n")
+			case "pPr", "rPr", "delText", "instrText", "del":
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// docxStyles reads the heading level and list numbering of each
+// paragraph style from word/styles.xml.
+func docxStyles(zr *zipPackage) (map[string]docxStyle, error) {
+	root, err := readPart(zr, "word/styles.xml")
+	if err != nil || root == nil {
+		return nil, err
+	}
+	styles := make(map[string]docxStyle)
+	for _, s := range root.all("style") {
+		if s.attr("type") != "paragraph" {
+			continue
+		}
+		var style docxStyle
+		name := strings.ToLower(s.child("name").attr("val"))
+		switch {
+		case name == "title":
+			style.heading = 1
+		case strings.HasPrefix(name, "heading "):
+			style.heading, _ = strconv.Atoi(strings.TrimPrefix(name, "heading "))
+		}
+		pPr := s.child("pPr")
+		if lvl := pPr.child("outlineLvl"); lvl != nil {
+			if n, err := strconv.Atoi(lvl.attr("val")); err == nil && n < 9 {
+				style.heading = n + 1
+			}
+		}
+		if numPr := pPr.child("numPr"); numPr != nil {
+			style.numID = numPr.child("numId").attr("val")
+			style.ilvl, _ = strconv.Atoi(numPr.child("ilvl").attr("val"))
+		}
+		styles[s.attr("styleId")] = style
+	}
+	return styles, nil
+}
+
+// docxNumbering reads the number format of each list level from
+// word/numbering.xml.
+func docxNumbering(zr *zipPackage) (map[string]map[int]string, error) {
+	root, err := readPart(zr, "word/numbering.xml")
+	if err != nil || root == nil {
+		return nil, err
+	}
+	abstract := make(map[string]map[int]string)
+	for _, a := range root.all("abstractNum") {
+		levels := make(map[int]string)
+		for _, lvl := range a.all("lvl") {
+			n, _ := strconv.Atoi(lvl.attr("ilvl"))
+			levels[n] = lvl.child("numFmt").attr("val")
+		}
+		abstract[a.attr("abstractNumId")] = levels
+	}
+	numbering := make(map[string]map[int]string)
+	for _, num := range root.all("num") {
+		numbering[num.attr("numId")] = abstract[num.child("abstractNumId").attr("val")]
+	}
+	return numbering, nil
+}
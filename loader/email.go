@@ -0,0 +1,351 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Metadata keys set by EmailLoader. Message IDs are recorded without
+// their angle brackets.
+const (
+	MetaEmailFrom        = "from"
+	MetaEmailTo          = "to"
+	MetaEmailCc          = "cc"
+	MetaEmailSubject     = "subject"
+	MetaEmailDate        = "date"
+	MetaEmailMessageID   = "message_id"
+	MetaEmailInReplyTo   = "in_reply_to"
+	MetaEmailReferences  = "references"
+	MetaEmailThreadID    = "thread_id"
+	MetaEmailAttachments = "attachments"
+)
+
+// EmailLoader parses RFC 5322 messages. The body is the message's
+// text/plain parts, or its HTML parts converted to text when it has no
+// plain text. Headers are recorded in metadata; the subject also becomes
+// the title. Use LoadMBOX to split a mailbox into one result per message.
+type EmailLoader struct {
+	// StripQuotes removes quoted lines and the reply chain that follows an
+	// attribution such as "On <date>, <name> wrote:".
+	StripQuotes bool
+	// HTML converts HTML bodies to text (default NewHTMLLoader()).
+	HTML Loader
+}
+
+// NewEmailLoader creates a new EmailLoader.
+func NewEmailLoader() *EmailLoader { return &EmailLoader{} }
+
+// Load reads a single message. A leading mbox "From " line is ignored.
+func (l *EmailLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	br := bufio.NewReader(reader)
+	if first, err := br.Peek(5); err == nil && string(first) == "From " {
+		if _, err := br.ReadString('\n'); err != nil {
+			return nil, fmt.Errorf("weave: email load: %w", err)
+		}
+	}
+
+	msg, err := mail.ReadMessage(br)
+	if err != nil {
+		return nil, fmt.Errorf("weave: email load: %w", err)
+	}
+
+	meta := emailHeaders(msg.Header)
+	var body emailBody
+	if err := l.walk(ctx, msg.Header, msg.Body, &body); err != nil {
+		return nil, fmt.Errorf("weave: email load: %w", err)
+	}
+
+	content := strings.Join(body.plain, "\n\n")
+	if content == "" {
+		content = strings.Join(body.html, "\n\n")
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if l.StripQuotes {
+		content = stripQuotes(content)
+	}
+	if len(body.attachments) > 0 {
+		meta[MetaEmailAttachments] = strings.Join(body.attachments, ", ")
+	}
+
+	return &LoadResult{
+		Content:  strings.TrimSpace(content),
+		Metadata: meta,
+		MimeType: "message/rfc822",
+	}, nil
+}
+
+// LoadMBOX splits an mbox stream into messages and loads each one. A
+// message's Message-ID is also recorded as MetaRecordID. Escaped ">From "
+// lines in message bodies are unescaped.
+func (l *EmailLoader) LoadMBOX(ctx context.Context, reader io.Reader) ([]*LoadResult, error) {
+	var results []*LoadResult
+	var msg bytes.Buffer
+	flush := func() error {
+		if len(bytes.TrimSpace(msg.Bytes())) == 0 {
+			return nil
+		}
+		result, err := l.Load(ctx, bytes.NewReader(msg.Bytes()))
+		if err != nil {
+			return fmt.Errorf("weave: mbox message %d: %w", len(results)+1, err)
+		}
+		if messageID := result.Metadata[MetaEmailMessageID]; messageID != "" {
+			result.Metadata[MetaRecordID] = messageID
+		}
+		results = append(results, result)
+		msg.Reset()
+		return nil
+	}
+
+	br := bufio.NewReader(reader)
+	for {
+		line, err := br.ReadString('\n')
+		if strings.HasPrefix(line, "From ") {
+			if flushErr := flush(); flushErr != nil {
+				return results, flushErr
+			}
+		} else {
+			if trimmed := strings.TrimLeft(line, ">"); len(trimmed) < len(line) && strings.HasPrefix(trimmed, "From ") {
+				line = line[1:]
+			}
+			msg.WriteString(line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return results, fmt.Errorf("weave: mbox load: %w", err)
+		}
+		if cerr := ctx.Err(); cerr != nil {
+			return results, cerr
+		}
+	}
+	return results, flush()
+}
+
+// Supports returns true for RFC 822 messages.
+func (l *EmailLoader) Supports(mimeType string) bool {
+	return mimeType == "message/rfc822"
+}
+
+// emailBody collects the text found in a message.
+type emailBody struct {
+	plain       []string
+	html        []string
+	attachments []string
+}
+
+// walk collects the text parts under one MIME entity.
+func (l *EmailLoader) walk(ctx context.Context, header map[string][]string, body io.Reader, out *emailBody) error {
+	get := func(key string) string {
+		if v := header[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	disposition, dparams, _ := mime.ParseMediaType(get("Content-Disposition"))
+	if disposition == "attachment" {
+		name := dparams["filename"]
+		if name == "" {
+			name = params["name"]
+		}
+		if name != "" {
+			out.attachments = append(out.attachments, name)
+		}
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := l.walk(ctx, part.Header, part, out); err != nil {
+				return err
+			}
+		}
+	case mediaType == "message/rfc822":
+		msg, err := mail.ReadMessage(decodeTransfer(get("Content-Transfer-Encoding"), body))
+		if err != nil {
+			return err
+		}
+		return l.walk(ctx, msg.Header, msg.Body, out)
+	case mediaType == "text/plain" || mediaType == "text/html":
+		text, err := readText(decodeTransfer(get("Content-Transfer-Encoding"), body), params["charset"])
+		if err != nil {
+			return err
+		}
+		if mediaType == "text/plain" {
+			out.plain = append(out.plain, strings.TrimSpace(text))
+			return nil
+		}
+		html := l.HTML
+		if html == nil {
+			html = NewHTMLLoader()
+		}
+		res, err := html.Load(ctx, strings.NewReader(text))
+		if err != nil {
+			return err
+		}
+		out.html = append(out.html, res.Content)
+	default:
+		if name := params["name"]; name != "" {
+			out.attachments = append(out.attachments, name)
+		}
+	}
+	return nil
+}
+
+// decodeTransfer undoes a Content-Transfer-Encoding.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	default:
+		return body
+	}
+}
+
+// readText reads a text body and converts it from charset to UTF-8.
+func readText(body io.Reader, charset string) (string, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "utf8", "us-ascii":
+	default:
+		if enc, err := htmlindex.Get(charset); err == nil {
+			body = enc.NewDecoder().Reader(body)
+		}
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.ToValidUTF8(data, []byte("\uFFFD"))), nil
+}
+
+// wordDecoder decodes RFC 2047 encoded words in any charset htmlindex
+// knows.
+var wordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(charset)
+		if err != nil {
+			return nil, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	},
+}
+
+// emailHeaders extracts the metadata of a message.
+func emailHeaders(h mail.Header) map[string]string {
+	meta := make(map[string]string)
+	decode := func(key string) string {
+		raw := h.Get(key)
+		if s, err := wordDecoder.DecodeHeader(raw); err == nil {
+			raw = s
+		}
+		return strings.TrimSpace(raw)
+	}
+	addresses := func(key string) string {
+		list, err := (&mail.AddressParser{WordDecoder: wordDecoder}).ParseList(h.Get(key))
+		if err != nil {
+			return decode(key)
+		}
+		out := make([]string, len(list))
+		for i, a := range list {
+			out[i] = a.String()
+			if a.Name == "" {
+				out[i] = a.Address
+			}
+		}
+		return strings.Join(out, ", ")
+	}
+	ids := func(key string) []string {
+		var out []string
+		for _, f := range strings.Fields(h.Get(key)) {
+			if id := strings.Trim(f, "<>,"); id != "" {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+
+	set := func(key, value string) {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+	set(MetaEmailFrom, addresses("From"))
+	set(MetaEmailTo, addresses("To"))
+	set(MetaEmailCc, addresses("Cc"))
+	set(MetaEmailSubject, decode("Subject"))
+	set("title", meta[MetaEmailSubject])
+	if date, err := h.Date(); err == nil {
+		meta[MetaEmailDate] = date.UTC().Format(time.RFC3339)
+	}
+
+	messageID := ids("Message-ID")
+	inReplyTo := ids("In-Reply-To")
+	references := ids("References")
+	if len(messageID) > 0 {
+		meta[MetaEmailMessageID] = messageID[0]
+	}
+	if len(inReplyTo) > 0 {
+		meta[MetaEmailInReplyTo] = inReplyTo[0]
+	}
+	set(MetaEmailReferences, strings.Join(references, " "))
+
+	// The thread is named after its first message.
+	switch {
+	case len(references) > 0:
+		meta[MetaEmailThreadID] = references[0]
+	case len(inReplyTo) > 0:
+		meta[MetaEmailThreadID] = inReplyTo[0]
+	case len(messageID) > 0:
+		meta[MetaEmailThreadID] = messageID[0]
+	}
+	return meta
+}
+
+// reAttribution matches the line, possibly wrapped, that introduces a
+// quoted reply.
+var reAttribution = regexp.MustCompile(`(?m)^(On [^\n]*(\n[^\n]*)?wrote:|-+ ?Original Message ?-+|_{20,})\s*$`)
+
+// stripQuotes removes the quoted reply chain from a message body.
+func stripQuotes(body string) string {
+	if loc := reAttribution.FindStringIndex(body); loc != nil {
+		body = body[:loc[0]]
+	}
+	lines := strings.Split(body, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), ">") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
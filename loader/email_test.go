@@ -0,0 +1,75 @@
+package loader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+const mbox = `From alice@example.com Mon Jan  1 10:00:00 2024
+From: Alice <alice@example.com>
+To: support@example.com
+Subject: =?UTF-8?Q?Caf=C3=A9_login?=
+Date: Mon, 1 Jan 2024 10:00:00 +0000
+Message-ID: <1@example.com>
+Content-Type: multipart/alternative; boundary="b1"
+
+--b1
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+I can't log in to the caf=E9 portal.
+>From what I can tell it started today.
+--b1
+Content-Type: text/html
+
+<p>HTML version</p>
+--b1--
+
+From support@example.com Mon Jan  1 11:00:00 2024
+From: support@example.com
+To: Alice <alice@example.com>
+Subject: Re: Cafe login
+Message-ID: <2@example.com>
+In-Reply-To: <1@example.com>
+References: <1@example.com>
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: base64
+
+PHA+UmVzZXQgeW91ciBwYXNzd29yZC48L3A+CjxwPk9uIE1vbiwgQWxpY2Ugd3JvdGU6PC9w
+Pgo8YmxvY2txdW90ZT4mZ3Q7IG9sZDwvYmxvY2txdW90ZT4=
+`
+
+func TestEmailLoaderMBOX(t *testing.T) {
+	l := loader.NewEmailLoader()
+	l.StripQuotes = true
+	results, err := l.LoadMBOX(context.Background(), strings.NewReader(mbox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d messages, want 2", len(results))
+	}
+
+	first := results[0]
+	if first.Content != "I can't log in to the café portal.\nFrom what I can tell it started today." {
+		t.Errorf("first content = %q", first.Content)
+	}
+	if first.Metadata[loader.MetaEmailSubject] != "Café login" ||
+		first.Metadata[loader.MetaEmailFrom] != `"Alice" <alice@example.com>` ||
+		first.Metadata[loader.MetaEmailDate] != "2024-01-01T10:00:00Z" ||
+		first.Metadata[loader.MetaEmailThreadID] != "1@example.com" {
+		t.Errorf("first metadata = %v", first.Metadata)
+	}
+
+	second := results[1]
+	if second.Content != "Reset your password." {
+		t.Errorf("second content = %q", second.Content)
+	}
+	if second.Metadata[loader.MetaEmailInReplyTo] != "1@example.com" ||
+		second.Metadata[loader.MetaEmailThreadID] != "1@example.com" {
+		t.Errorf("second metadata = %v", second.Metadata)
+	}
+}
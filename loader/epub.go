@@ -0,0 +1,310 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Metadata keys set by EPUBLoader.
+const (
+	// MetaChapterCount is the number of chapters with text.
+	MetaChapterCount = "chapter_count"
+	// MetaChapterOffsets lists the byte offset in Content at which each
+	// chapter starts, comma-separated. See ChapterOffsets.
+	MetaChapterOffsets = "chapter_offsets"
+	// MetaChapterTitles lists the title of each chapter, one per line.
+	MetaChapterTitles = "chapter_titles"
+)
+
+// EPUBLoader extracts the chapters of an EPUB book in spine order. Each
+// chapter is one entry in MetaChapterOffsets and MetaChapterTitles, titled
+// from the book's table of contents or else the chapter's first heading.
+type EPUBLoader struct{}
+
+// NewEPUBLoader creates a new EPUBLoader.
+func NewEPUBLoader() *EPUBLoader { return &EPUBLoader{} }
+
+// Load reads an .epub file and returns the text of its chapters.
+func (l *EPUBLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	zr, err := openZipPackage(ctx, reader)
+	if err != nil {
+		return nil, fmt.Errorf("weave: epub load: %w", err)
+	}
+
+	container, err := readPart(zr, "META-INF/container.xml")
+	if err != nil {
+		return nil, err
+	}
+	rootfiles := container.all("rootfile")
+	if len(rootfiles) == 0 {
+		return nil, errors.New("weave: epub load: missing package document")
+	}
+	opfPath := rootfiles[0].attr("full-path")
+	opf, err := readPart(zr, opfPath)
+	if err != nil {
+		return nil, err
+	}
+	if opf == nil {
+		return nil, fmt.Errorf("weave: epub load: missing %s", opfPath)
+	}
+
+	// Manifest items by ID, with hrefs resolved to archive paths.
+	type item struct{ href, mediaType, properties string }
+	items := make(map[string]item)
+	for _, it := range opf.child("manifest").all("item") {
+		items[it.attr("id")] = item{
+			href:       resolveHref(opfPath, it.attr("href")),
+			mediaType:  it.attr("media-type"),
+			properties: it.attr("properties"),
+		}
+	}
+
+	// Chapter titles from the EPUB 3 navigation document, or else the
+	// EPUB 2 NCX.
+	titles := make(map[string]string)
+	for _, it := range items {
+		if strings.Contains(" "+it.properties+" ", " nav ") {
+			if err := navTitles(zr, it.href, titles); err != nil {
+				return nil, err
+			}
+		}
+	}
+	spine := opf.child("spine")
+	if ncx, ok := items[spine.attr("toc")]; ok && len(titles) == 0 {
+		if err := ncxTitles(zr, ncx.href, titles); err != nil {
+			return nil, err
+		}
+	}
+
+	htmlLoader := NewHTMLLoader()
+	var b strings.Builder
+	var offsets, chapterTitles []string
+	for _, ref := range spine.all("itemref") {
+		it, ok := items[ref.attr("idref")]
+		if !ok || ref.attr("linear") == "no" {
+			continue
+		}
+		raw, err := readRaw(zr, it.href)
+		if err != nil {
+			return nil, err
+		}
+		res, err := htmlLoader.Load(ctx, bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("weave: epub load %s: %w", it.href, err)
+		}
+		if res.Content == "" {
+			continue
+		}
+		title := titles[it.href]
+		if title == "" {
+			title = firstHeading(raw)
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		offsets = append(offsets, strconv.Itoa(b.Len()))
+		chapterTitles = append(chapterTitles, strings.Join(strings.Fields(title), " "))
+		b.WriteString(res.Content)
+	}
+
+	meta := map[string]string{
+		MetaChapterCount:   strconv.Itoa(len(offsets)),
+		MetaChapterOffsets: strings.Join(offsets, ","),
+		MetaChapterTitles:  strings.Join(chapterTitles, "\n"),
+	}
+	md := opf.child("metadata")
+	if title := strings.TrimSpace(md.childText("title")); title != "" {
+		meta["title"] = title
+	}
+	if author := strings.TrimSpace(md.childText("creator")); author != "" {
+		meta["author"] = author
+	}
+	if lang := strings.TrimSpace(md.childText("language")); lang != "" {
+		meta["language"] = lang
+	}
+
+	return &LoadResult{
+		Content:  b.String(),
+		Metadata: meta,
+		MimeType: "application/epub+zip",
+	}, nil
+}
+
+// Supports returns true for EPUB.
+func (l *EPUBLoader) Supports(mimeType string) bool {
+	return mimeType == "application/epub+zip"
+}
+
+// ChapterOffsets parses MetaChapterOffsets from loader metadata. It
+// returns nil when the metadata has no chapter offsets.
+func ChapterOffsets(meta map[string]string) []int {
+	return parseOffsets(meta[MetaChapterOffsets])
+}
+
+// ChapterTitles parses MetaChapterTitles from loader metadata.
+func ChapterTitles(meta map[string]string) []string {
+	if raw, ok := meta[MetaChapterTitles]; ok {
+		return strings.Split(raw, "\n")
+	}
+	return nil
+}
+
+// resolveHref resolves an href relative to the part that contains it,
+// dropping any fragment.
+func resolveHref(base, href string) string {
+	href, _, _ = strings.Cut(href, "#")
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Join(path.Dir(base), href)
+}
+
+// readRaw returns the bytes of an archive member.
+func readRaw(zr *zipPackage, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := zr.openPart(f)
+		if err != nil {
+			return nil, fmt.Errorf("weave: epub load %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }()
+		raw, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("weave: epub load %s: %w", name, err)
+		}
+		return raw, nil
+	}
+	return nil, fmt.Errorf("weave: epub load %s: %w", name, fs.ErrNotExist)
+}
+
+// navTitles reads chapter titles from an EPUB 3 navigation document.
+func navTitles(zr *zipPackage, navPath string, titles map[string]string) error {
+	raw, err := readRaw(zr, navPath)
+	if err != nil {
+		return err
+	}
+	doc, err := html.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("weave: epub load %s: %w", navPath, err)
+	}
+
+	var walk func(n *html.Node, inTOC bool)
+	walk = func(n *html.Node, inTOC bool) {
+		if n.Type == html.ElementNode {
+			if n.Data == "nav" {
+				inTOC = htmlAttr(n, "epub:type") == "toc"
+			}
+			if inTOC && n.Data == "a" {
+				if href := htmlAttr(n, "href"); href != "" {
+					target := resolveHref(navPath, href)
+					if _, seen := titles[target]; !seen {
+						titles[target] = nodeText(n)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inTOC)
+		}
+	}
+	walk(doc, false)
+	return nil
+}
+
+// ncxTitles reads chapter titles from an EPUB 2 NCX table of contents.
+func ncxTitles(zr *zipPackage, ncxPath string, titles map[string]string) error {
+	ncx, err := readPart(zr, ncxPath)
+	if err != nil || ncx == nil {
+		return err
+	}
+	var walk func(n *xmlNode)
+	walk = func(n *xmlNode) {
+		for _, point := range n.Children {
+			if point.Name != "navPoint" {
+				continue
+			}
+			target := resolveHref(ncxPath, point.child("content").attr("src"))
+			if _, seen := titles[target]; !seen {
+				titles[target] = strings.TrimSpace(point.child("navLabel").childText("text"))
+			}
+			walk(point)
+		}
+	}
+	walk(ncx.child("navMap"))
+	return nil
+}
+
+// firstHeading returns the text of the first h1-h3 element of an HTML
+// document, or its <title>.
+func firstHeading(raw []byte) string {
+	doc, err := html.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return ""
+	}
+	var heading, title string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if heading != "" {
+			return
+		}
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "h1", "h2", "h3":
+				heading = nodeText(n)
+				return
+			case "title":
+				if title == "" {
+					title = nodeText(n)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if heading != "" {
+		return heading
+	}
+	return title
+}
+
+// htmlAttr returns the value of an attribute of an HTML element.
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key || (a.Namespace != "" && a.Namespace+":"+a.Key == key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// nodeText returns the text under an HTML node with whitespace collapsed.
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
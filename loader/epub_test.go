@@ -0,0 +1,40 @@
+package loader_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+func TestEPUBLoaderSpineOrder(t *testing.T) {
+	chapter := func(heading, body string) string {
+		return `<?xml version="1.0"?><html xmlns="http://www.w3.org/1999/xhtml"><body><h1>` + heading + `</h1><p>` + body + `</p></body></html>`
+	}
+	data := buildZip(t, map[string]string{
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="OEBPS/content.opf"/></rootfiles></container>`,
+		"OEBPS/content.opf": `<package><metadata><dc:title xmlns:dc="http://purl.org/dc/elements/1.1/">A Book</dc:title></metadata>
+<manifest><item id="nav" href="nav.xhtml" properties="nav"/><item id="c1" href="text/one.xhtml"/><item id="c2" href="text/two.xhtml"/></manifest>
+<spine><itemref idref="c2"/><itemref idref="c1"/></spine></package>`,
+		"OEBPS/nav.xhtml":      `<html><body><nav epub:type="toc"><ol><li><a href="text/two.xhtml">Beginnings</a></li></ol></nav></body></html>`,
+		"OEBPS/text/one.xhtml": chapter("Later", "The end."),
+		"OEBPS/text/two.xhtml": chapter("Start", "Once upon a time."),
+	})
+
+	res, err := loader.NewEPUBLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(res.Content, "Start") || !strings.Contains(res.Content, "The end.") ||
+		strings.Index(res.Content, "Once upon") > strings.Index(res.Content, "Later") {
+		t.Errorf("content = %q", res.Content)
+	}
+	if got := loader.ChapterTitles(res.Metadata); len(got) != 2 || got[0] != "Beginnings" || got[1] != "Later" {
+		t.Errorf("titles = %q", got)
+	}
+	if res.Metadata["title"] != "A Book" || len(loader.ChapterOffsets(res.Metadata)) != 2 {
+		t.Errorf("metadata = %v", res.Metadata)
+	}
+}
@@ -8,12 +8,24 @@ import (
 	"golang.org/x/net/html"
 )
 
-// HTMLLoader extracts text from HTML documents.
-type HTMLLoader struct{}
+// HTMLLoader extracts text from HTML documents. The page title, meta
+// description, canonical URL and language are recorded in the result
+// metadata as "title", "description", "canonical_url" and "language".
+type HTMLLoader struct {
+	// MainContent extracts only the main content of the page, scoring DOM
+	// blocks the way reader modes do and dropping navigation, headers,
+	// footers, sidebars, banners and link-dense blocks. Headings, lists,
+	// tables, code and quotes are rendered as Markdown.
+	MainContent bool
+}
 
 // NewHTMLLoader creates a new HTMLLoader.
 func NewHTMLLoader() *HTMLLoader { return &HTMLLoader{} }
 
+// NewMainContentHTMLLoader creates an HTMLLoader that keeps only the main
+// content of each page.
+func NewMainContentHTMLLoader() *HTMLLoader { return &HTMLLoader{MainContent: true} }
+
 // Load reads HTML and returns extracted text content.
 func (l *HTMLLoader) Load(_ context.Context, reader io.Reader) (*LoadResult, error) {
 	doc, err := html.Parse(reader)
@@ -21,11 +33,19 @@ func (l *HTMLLoader) Load(_ context.Context, reader io.Reader) (*LoadResult, err
 		return nil, err
 	}
 
-	var b strings.Builder
-	extractText(doc, &b)
+	meta := htmlMetadata(doc)
+	var content string
+	if l.MainContent {
+		content = renderMarkdown(mainContent(doc))
+	} else {
+		var b strings.Builder
+		extractText(doc, &b)
+		content = b.String()
+	}
 
 	return &LoadResult{
-		Content:  strings.TrimSpace(b.String()),
+		Content:  strings.TrimSpace(content),
+		Metadata: meta,
 		MimeType: "text/html",
 	}, nil
 }
@@ -65,3 +85,60 @@ func extractText(n *html.Node, b *strings.Builder) {
 		}
 	}
 }
+
+// htmlMetadata reads the title, description, canonical URL and language
+// of a page, preferring the page's own tags over Open Graph ones.
+func htmlMetadata(doc *html.Node) map[string]string {
+	meta := make(map[string]string)
+	og := make(map[string]string)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				if lang := htmlAttr(n, "lang"); lang != "" {
+					meta["language"] = lang
+				}
+			case "title":
+				if _, ok := meta["title"]; !ok {
+					meta["title"] = nodeText(n)
+				}
+			case "meta":
+				content := strings.TrimSpace(htmlAttr(n, "content"))
+				switch name := strings.ToLower(htmlAttr(n, "name") + htmlAttr(n, "property")); name {
+				case "description", "author":
+					meta[name] = content
+				case "og:title", "og:description", "og:url":
+					og[strings.TrimPrefix(name, "og:")] = content
+				}
+				if strings.EqualFold(htmlAttr(n, "http-equiv"), "content-language") && meta["language"] == "" {
+					meta["language"] = content
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(htmlAttr(n, "rel"))) {
+					if rel == "canonical" {
+						meta["canonical_url"] = htmlAttr(n, "href")
+					}
+				}
+			case "body":
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	for key, ogKey := range map[string]string{"title": "title", "description": "description", "canonical_url": "url"} {
+		if meta[key] == "" && og[ogKey] != "" {
+			meta[key] = og[ogKey]
+		}
+	}
+	for k, v := range meta {
+		if v == "" {
+			delete(meta, k)
+		}
+	}
+	return meta
+}
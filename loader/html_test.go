@@ -0,0 +1,62 @@
+package loader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html lang="en"><head>
+<title>Tuning the cache | Example Blog</title>
+<meta name="description" content="How we tuned our cache.">
+<link rel="canonical" href="https://example.com/blog/cache">
+</head><body>
+<header><nav><a href="/">Home</a> <a href="/blog">Blog</a> <a href="/about">About</a></nav></header>
+<div id="cookie-banner">We use cookies. <a href="/privacy">Learn more</a></div>
+<div class="layout">
+  <div class="post-content">
+    <h1>Tuning the cache</h1>
+    <p>Our cache hit rate dropped to forty percent last spring, and latency went up with it, so we looked into why.</p>
+    <p>The culprit was the eviction policy, which treated every key the same regardless of how often it was read.</p>
+    <h2>What we changed</h2>
+    <ul><li>Switched to LFU eviction</li><li>Raised the memory limit<ul><li>from 2 GB to 4 GB</li></ul></li></ul>
+    <pre><code>cache.SetPolicy(LFU)
+cache.SetLimit(4 &lt;&lt; 30)</code></pre>
+    <table><tr><th>Metric</th><th>Before</th><th>After</th></tr><tr><td>Hit rate</td><td>40%</td><td>92%</td></tr></table>
+  </div>
+  <div class="sidebar"><h3>Popular posts</h3><ul><li><a href="/a">Post A</a></li><li><a href="/b">Post B</a></li></ul></div>
+</div>
+<footer>Copyright 2024 Example Inc. All rights reserved.</footer>
+</body></html>`
+
+func TestHTMLLoaderMainContent(t *testing.T) {
+	res, err := loader.NewMainContentHTMLLoader().Load(context.Background(), strings.NewReader(articlePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Tuning the cache\n\n" +
+		"Our cache hit rate dropped to forty percent last spring, and latency went up with it, so we looked into why.\n\n" +
+		"The culprit was the eviction policy, which treated every key the same regardless of how often it was read.\n\n" +
+		"## What we changed\n\n" +
+		"- Switched to LFU eviction\n- Raised the memory limit\n  - from 2 GB to 4 GB\n\n" +
+		"```\ncache.SetPolicy(LFU)\ncache.SetLimit(4 << 30)\n```\n\n" +
+		"| Metric | Before | After |\n| --- | --- | --- |\n| Hit rate | 40% | 92% |"
+	if res.Content != want {
+		t.Errorf("content =\n%s\n\nwant\n%s", res.Content, want)
+	}
+
+	for key, value := range map[string]string{
+		"title":         "Tuning the cache | Example Blog",
+		"description":   "How we tuned our cache.",
+		"canonical_url": "https://example.com/blog/cache",
+		"language":      "en",
+	} {
+		if res.Metadata[key] != value {
+			t.Errorf("metadata[%s] = %q, want %q", key, res.Metadata[key], value)
+		}
+	}
+}
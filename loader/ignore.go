@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+)
+
+// DefaultIgnoreFiles are the ignore files most trees use. Set
+// DirectoryLoader.IgnoreFiles to them to honor both.
+var DefaultIgnoreFiles = []string{".gitignore", ".weaveignore"}
+
+// matchGlob reports whether a slash-separated relative path matches a
+// glob pattern. "*", "?" and "[...]" match within one path segment and
+// "**" matches any number of segments. A pattern without a slash matches
+// the last segment at any depth.
+func matchGlob(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignoreRule is one pattern line of a .gitignore-style file.
+type ignoreRule struct {
+	// base is the directory of the ignore file, relative to the walk root.
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// parseIgnore reads the rules of an ignore file found in base.
+func parseIgnore(base string, r io.Reader) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		// A slash anywhere but the end anchors the pattern to base.
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, sc.Err()
+}
+
+// ignored reports whether rules exclude the relative path rel. Rules are
+// applied in order and the last match wins.
+func ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	result := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		name := rel
+		if rule.base != "." {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			name = rel[len(rule.base)+1:]
+		}
+		var match bool
+		if rule.anchored {
+			match = matchSegments(strings.Split(rule.pattern, "/"), strings.Split(name, "/"))
+		} else {
+			match, _ = path.Match(rule.pattern, path.Base(name))
+		}
+		if match {
+			result = !rule.negate
+		}
+	}
+	return result
+}
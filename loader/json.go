@@ -3,15 +3,43 @@ package loader
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
-// JSONLoader loads JSON documents, optionally extracting specific fields.
+// MimeJSONL is the MIME type of JSON Lines (newline-delimited JSON).
+const MimeJSONL = "application/x-ndjson"
+
+// MetaRecordIndex is the 0-based position of a record in its source,
+// set by LoadRecords.
+const MetaRecordIndex = "record_index"
+
+// JSONLoader loads JSON and JSON Lines documents. Values are extracted in
+// the order they appear in the source.
+//
+// Selectors are a subset of JSONPath: a dotted path with an optional
+// leading "$", array indexes and "*" wildcards, for example
+// "$.product.name", "variants[*].sku" or "tags[0]". Keys containing dots
+// or spaces can be quoted as "['key name']".
 type JSONLoader struct {
-	// Fields to extract. Empty means extract all string values.
+	// Fields selects the values that make up the content. Empty means all
+	// string, number and boolean values. When the input is an array or a
+	// JSON Lines stream, the selectors apply to each element.
 	Fields []string
+	// MetadataFields maps metadata keys to selectors. Several matches are
+	// joined with ", "; objects and arrays are recorded as compact JSON.
+	MetadataFields map[string]string
+	// RecordPath selects the records for LoadRecords (default: the
+	// elements of a top-level array, each line of a JSON Lines stream, or
+	// else the whole document). A selector that matches an array yields
+	// its elements.
+	RecordPath string
+	// IDField selects the value recorded as MetaRecordID on each record.
+	// Records where it matches nothing fail.
+	IDField string
 }
 
 // NewJSONLoader creates a new JSONLoader.
@@ -19,67 +47,531 @@ func NewJSONLoader(fields ...string) *JSONLoader {
 	return &JSONLoader{Fields: fields}
 }
 
-// Load reads JSON and returns extracted text content.
-func (l *JSONLoader) Load(_ context.Context, reader io.Reader) (*LoadResult, error) {
-	data, err := io.ReadAll(reader)
+// Load reads JSON or JSON Lines and returns extracted text content. A
+// JSON Lines stream is treated as an array of its lines. Metadata
+// selectors are evaluated against the whole input.
+func (l *JSONLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	sel, err := l.selectors()
 	if err != nil {
 		return nil, err
 	}
-
-	var raw any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("weave: json load: %w", err)
+	values, err := decodeJSONStream(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	var root any = values
+	if len(values) == 1 {
+		root = values[0]
 	}
 
+	items := []any{root}
+	if arr, ok := root.([]any); ok {
+		items = arr
+	}
 	var b strings.Builder
-	if len(l.Fields) > 0 {
-		l.extractFields(raw, &b)
-	} else {
-		l.extractAll(raw, &b)
+	for _, item := range items {
+		sel.extract(item, &b)
+	}
+
+	result := &LoadResult{
+		Content:  strings.TrimSpace(b.String()),
+		MimeType: "application/json",
+	}
+	if len(values) > 1 {
+		result.MimeType = MimeJSONL
+	}
+	if result.Metadata, err = sel.metadata(root); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LoadRecords returns one load result per record. Use EachRecord for
+// inputs too large to hold in memory.
+func (l *JSONLoader) LoadRecords(ctx context.Context, reader io.Reader) ([]*LoadResult, error) {
+	var results []*LoadResult
+	err := l.EachRecord(ctx, reader, func(result *LoadResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// EachRecord streams the records of the input, calling fn with one load
+// result per record. Each result has its own content and metadata,
+// MetaRecordIndex, and MetaRecordID when IDField is set. The records are
+// the elements of each top-level array and the other top-level values,
+// so a JSON Lines stream yields one record per line; only one line or
+// array element is held in memory at a time. With RecordPath set, each
+// top-level value is decoded whole and the selector applied to it. An
+// error from fn stops the scan and is returned.
+func (l *JSONLoader) EachRecord(ctx context.Context, reader io.Reader, fn func(*LoadResult) error) error {
+	sel, err := l.selectors()
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	emit := func(record any) error {
+		result, err := sel.record(record, index)
+		if err != nil {
+			return err
+		}
+		index++
+		return fn(result)
+	}
+
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+	values := 0
+	for ; ; values++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return jsonValueError(values, err)
+		}
+
+		if tok == json.Delim('[') && sel.records == nil {
+			for dec.More() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				v, err := decodeJSONValue(dec)
+				if err != nil {
+					return jsonValueError(values, unexpectedEOF(err))
+				}
+				if err := emit(v); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return jsonValueError(values, unexpectedEOF(err))
+			}
+			continue
+		}
+
+		v, err := decodeJSONRest(dec, tok)
+		if err != nil {
+			return jsonValueError(values, err)
+		}
+		if sel.records == nil {
+			if err := emit(v); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, match := range sel.records.find(v) {
+			items := []any{match}
+			if arr, ok := match.([]any); ok {
+				items = arr
+			}
+			for _, item := range items {
+				if err := emit(item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if values == 0 {
+		return errors.New("weave: json load: empty input")
+	}
+	return nil
+}
+
+// Supports returns true for JSON and JSON Lines MIME types.
+func (l *JSONLoader) Supports(mimeType string) bool {
+	switch mimeType {
+	case "application/json", MimeJSONL, "application/jsonl", "application/x-jsonlines":
+		return true
 	}
+	return false
+}
 
+// jsonSelectors holds a JSONLoader's selectors, parsed once per load.
+type jsonSelectors struct {
+	fields   []jsonSelector
+	metaKeys []string
+	metaSels []jsonSelector
+	// records is set only when RecordPath is.
+	records *jsonSelector
+	id      jsonSelector
+	idField string
+}
+
+// selectors parses the loader's selectors.
+func (l *JSONLoader) selectors() (*jsonSelectors, error) {
+	s := &jsonSelectors{idField: l.IDField}
+	for _, field := range l.Fields {
+		sel, err := parseJSONSelector(field)
+		if err != nil {
+			return nil, err
+		}
+		s.fields = append(s.fields, sel)
+	}
+	for key, field := range l.MetadataFields {
+		sel, err := parseJSONSelector(field)
+		if err != nil {
+			return nil, err
+		}
+		s.metaKeys = append(s.metaKeys, key)
+		s.metaSels = append(s.metaSels, sel)
+	}
+	if l.RecordPath != "" {
+		sel, err := parseJSONSelector(l.RecordPath)
+		if err != nil {
+			return nil, err
+		}
+		s.records = &sel
+	}
+	if l.IDField != "" {
+		sel, err := parseJSONSelector(l.IDField)
+		if err != nil {
+			return nil, err
+		}
+		s.id = sel
+	}
+	return s, nil
+}
+
+// record builds the load result for the record at index.
+func (s *jsonSelectors) record(v any, index int) (*LoadResult, error) {
+	var b strings.Builder
+	s.extract(v, &b)
+	meta, err := s.metadata(v)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	meta[MetaRecordIndex] = strconv.Itoa(index)
+	if s.idField != "" {
+		var ids []string
+		for _, match := range s.id.find(v) {
+			if id, ok := jsonScalar(match); ok && id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("weave: json record %d: no %s", index, s.idField)
+		}
+		meta[MetaRecordID] = strings.Join(ids, ", ")
+	}
 	return &LoadResult{
 		Content:  strings.TrimSpace(b.String()),
+		Metadata: meta,
 		MimeType: "application/json",
 	}, nil
 }
 
-// Supports returns true for JSON MIME types.
-func (l *JSONLoader) Supports(mimeType string) bool {
-	return mimeType == "application/json"
+// extract writes the content of one value.
+func (s *jsonSelectors) extract(v any, b *strings.Builder) {
+	if len(s.fields) == 0 {
+		writeJSONText(v, b)
+		return
+	}
+	for _, sel := range s.fields {
+		for _, match := range sel.find(v) {
+			writeJSONText(match, b)
+		}
+	}
 }
 
-func (l *JSONLoader) extractFields(v any, b *strings.Builder) {
-	switch val := v.(type) {
-	case map[string]any:
-		for _, field := range l.Fields {
-			if fv, ok := val[field]; ok {
-				fmt.Fprintf(b, "%v", fv)
-				b.WriteString("\n")
+// metadata evaluates the metadata selectors against a value.
+func (s *jsonSelectors) metadata(v any) (map[string]string, error) {
+	if len(s.metaSels) == 0 {
+		return nil, nil
+	}
+	meta := make(map[string]string, len(s.metaSels))
+	for i, sel := range s.metaSels {
+		var parts []string
+		for _, match := range sel.find(v) {
+			if str, ok := jsonScalar(match); ok {
+				parts = append(parts, str)
+				continue
 			}
+			raw, err := json.Marshal(match)
+			if err != nil {
+				return nil, fmt.Errorf("weave: json load: %w", err)
+			}
+			parts = append(parts, string(raw))
 		}
-	case []any:
-		for _, item := range val {
-			l.extractFields(item, b)
+		if len(parts) > 0 {
+			meta[s.metaKeys[i]] = strings.Join(parts, ", ")
 		}
 	}
+	return meta, nil
 }
 
-func (l *JSONLoader) extractAll(v any, b *strings.Builder) {
+// writeJSONText writes every scalar under v, one per line, in source
+// order. Nulls are skipped.
+func writeJSONText(v any, b *strings.Builder) {
 	switch val := v.(type) {
-	case map[string]any:
-		for _, fv := range val {
-			l.extractAll(fv, b)
+	case *jsonObject:
+		for _, k := range val.keys {
+			writeJSONText(val.values[k], b)
 		}
 	case []any:
 		for _, item := range val {
-			l.extractAll(item, b)
+			writeJSONText(item, b)
+		}
+	default:
+		if s, ok := jsonScalar(val); ok {
+			b.WriteString(s)
+			b.WriteString("\n")
 		}
+	}
+}
+
+// jsonScalar formats a string, number or boolean.
+func jsonScalar(v any) (string, bool) {
+	switch val := v.(type) {
 	case string:
-		b.WriteString(val)
-		b.WriteString("\n")
-	case float64, bool:
-		fmt.Fprintf(b, "%v", val)
-		b.WriteString("\n")
+		return val, true
+	case json.Number:
+		return val.String(), true
+	case bool:
+		return strconv.FormatBool(val), true
+	}
+	return "", false
+}
+
+// jsonObject is a decoded JSON object that remembers its key order.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// MarshalJSON writes the object with its keys in source order.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// decodeJSONStream decodes every top-level value in reader, so a plain
+// JSON document yields one value and a JSON Lines stream one per line.
+func decodeJSONStream(ctx context.Context, reader io.Reader) ([]any, error) {
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+	var values []any
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		v, err := decodeJSONValue(dec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, jsonValueError(len(values), err)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("weave: json load: empty input")
+	}
+	return values, nil
+}
+
+// jsonValueError reports a failure to decode the top-level value after
+// the given number of values, naming the value when it is not the first.
+func jsonValueError(decoded int, err error) error {
+	if decoded > 0 {
+		return fmt.Errorf("weave: json load: value %d: %w", decoded+1, err)
+	}
+	return fmt.Errorf("weave: json load: %w", err)
+}
+
+// decodeJSONValue decodes the next value from dec, keeping object keys in
+// source order.
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONRest(dec, tok)
+}
+
+// decodeJSONRest decodes the value that begins with tok.
+func decodeJSONRest(dec *json.Decoder, tok json.Token) (any, error) {
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: make(map[string]any)}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			key, _ := keyTok.(string)
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return obj, nil
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			arr = append(arr, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return arr, nil
+	}
+	return tok, nil
+}
+
+// unexpectedEOF reports an EOF inside a value as io.ErrUnexpectedEOF, so
+// it is not mistaken for the end of the stream.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// jsonStep is one step of a selector: an object key, an array index, or
+// a wildcard matching every member.
+type jsonStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonSelector is a parsed JSONPath-like selector.
+type jsonSelector []jsonStep
+
+// parseJSONSelector parses a selector such as "$.items[*].name".
+func parseJSONSelector(expr string) (jsonSelector, error) {
+	bad := func(reason string) error {
+		return fmt.Errorf("weave: json selector %q: %s", expr, reason)
+	}
+	s := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	var sel jsonSelector
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, bad("empty key")
+			}
+			if key := s[:end]; key == "*" {
+				sel = append(sel, jsonStep{wildcard: true})
+			} else {
+				sel = append(sel, jsonStep{key: key})
+			}
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if q := s[1:min(2, len(s))]; q == "'" || q == `"` {
+				closing := strings.Index(s[2:], q+"]")
+				if closing < 0 {
+					return nil, bad("unterminated quoted key")
+				}
+				sel = append(sel, jsonStep{key: s[2 : 2+closing]})
+				s = s[2+closing+2:]
+				continue
+			}
+			if end < 0 {
+				return nil, bad("unterminated [")
+			}
+			inner := strings.TrimSpace(s[1:end])
+			if inner == "*" {
+				sel = append(sel, jsonStep{wildcard: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, bad("invalid index " + inner)
+				}
+				sel = append(sel, jsonStep{index: n, isIndex: true})
+			}
+			s = s[end+1:]
+		default:
+			// A leading key without a dot, as in "items[0]".
+			s = "." + s
+		}
+	}
+	return sel, nil
+}
+
+// find returns the values the selector matches under v. Negative indexes
+// count from the end of an array.
+func (sel jsonSelector) find(v any) []any {
+	current := []any{v}
+	for _, step := range sel {
+		var next []any
+		for _, c := range current {
+			switch val := c.(type) {
+			case *jsonObject:
+				switch {
+				case step.wildcard:
+					for _, k := range val.keys {
+						next = append(next, val.values[k])
+					}
+				case !step.isIndex:
+					if child, ok := val.values[step.key]; ok {
+						next = append(next, child)
+					}
+				}
+			case []any:
+				switch {
+				case step.wildcard:
+					next = append(next, val...)
+				case step.isIndex:
+					i := step.index
+					if i < 0 {
+						i += len(val)
+					}
+					if i >= 0 && i < len(val) {
+						next = append(next, val[i])
+					}
+				}
+			}
+		}
+		current = next
 	}
+	return current
 }
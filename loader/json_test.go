@@ -0,0 +1,128 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/xraph/weave/loader"
+)
+
+const catalogJSON = `{
+  "store": "north",
+  "products": [
+    {"sku": "A-1", "name": "Kettle", "price": 25.50, "tags": ["kitchen", "steel"], "specs": {"watts": 2200}},
+    {"sku": "B-2", "name": "Toaster", "price": 40, "tags": ["kitchen"], "specs": {"watts": 900}}
+  ]
+}`
+
+func TestJSONLoaderSelectors(t *testing.T) {
+	l := &loader.JSONLoader{
+		Fields:         []string{"$.products[*].name", "products[0].specs.watts"},
+		MetadataFields: map[string]string{"store": "store", "skus": "$.products[*].sku"},
+	}
+	res, err := l.Load(context.Background(), strings.NewReader(catalogJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Content != "Kettle\nToaster\n2200" {
+		t.Errorf("content = %q", res.Content)
+	}
+	if res.Metadata["store"] != "north" || res.Metadata["skus"] != "A-1, B-2" {
+		t.Errorf("metadata = %v", res.Metadata)
+	}
+
+	// Without selectors every value is extracted in source order.
+	res, err = loader.NewJSONLoader().Load(context.Background(), strings.NewReader(`{"b": "first", "a": ["second", 3, true, null]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Content != "first\nsecond\n3\ntrue" {
+		t.Errorf("content = %q", res.Content)
+	}
+
+	if _, err := (&loader.JSONLoader{Fields: []string{"items[x]"}}).Load(context.Background(), strings.NewReader(`{}`)); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+}
+
+func TestJSONLoaderRecords(t *testing.T) {
+	l := &loader.JSONLoader{
+		Fields:         []string{"name"},
+		MetadataFields: map[string]string{"sku": "sku", "tags": "tags[*]", "specs": "specs"},
+		RecordPath:     "$.products",
+	}
+	results, err := l.LoadRecords(context.Background(), strings.NewReader(catalogJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d records, want 2", len(results))
+	}
+	first := results[0]
+	if first.Content != "Kettle" || first.Metadata["sku"] != "A-1" || first.Metadata["tags"] != "kitchen, steel" ||
+		first.Metadata["specs"] != `{"watts":2200}` || first.Metadata[loader.MetaRecordIndex] != "0" {
+		t.Errorf("record 0 = %q %v", first.Content, first.Metadata)
+	}
+
+	jsonl := "{\"id\": 1, \"text\": \"alpha\"}\n\n{\"id\": 2, \"text\": \"beta\"}\n"
+	l = &loader.JSONLoader{Fields: []string{"text"}, MetadataFields: map[string]string{"id": "id"}}
+	results, err = l.LoadRecords(context.Background(), strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[1].Content != "beta" || results[1].Metadata["id"] != "2" {
+		t.Fatalf("JSONL records = %+v", results)
+	}
+
+	l.IDField = "id"
+	results, err = l.LoadRecords(context.Background(), strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Metadata[loader.MetaRecordID] != "1" || results[1].Metadata[loader.MetaRecordID] != "2" {
+		t.Errorf("record IDs = %v, %v", results[0].Metadata, results[1].Metadata)
+	}
+	if _, err := l.LoadRecords(context.Background(), strings.NewReader(`{"text": "no id"}`)); err == nil {
+		t.Error("expected error for a record without an ID")
+	}
+
+	res, err := l.Load(context.Background(), strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Content != "alpha\nbeta" || res.MimeType != loader.MimeJSONL {
+		t.Errorf("JSONL Load = %q (%s)", res.Content, res.MimeType)
+	}
+
+	if _, err := l.LoadRecords(context.Background(), strings.NewReader("{\"id\": 1}\n{\"id\": ")); err == nil {
+		t.Error("expected error for truncated line")
+	}
+}
+
+func TestJSONLoaderEachRecordStreams(t *testing.T) {
+	// The input fails after the first record, which must already have been
+	// delivered: records are decoded one at a time, not read up front.
+	broken := errors.New("connection reset")
+	for name, first := range map[string]string{
+		"jsonl": "{\"id\": 1, \"text\": \"alpha\"}\n",
+		"array": "[{\"id\": 1, \"text\": \"alpha\"},",
+	} {
+		l := &loader.JSONLoader{Fields: []string{"text"}, IDField: "id"}
+		var got []string
+		err := l.EachRecord(context.Background(), io.MultiReader(strings.NewReader(first), iotest.ErrReader(broken)),
+			func(r *loader.LoadResult) error {
+				got = append(got, r.Content+"#"+r.Metadata[loader.MetaRecordID])
+				return nil
+			})
+		if !errors.Is(err, broken) {
+			t.Errorf("%s: err = %v, want the read error", name, err)
+		}
+		if len(got) != 1 || got[0] != "alpha#1" {
+			t.Errorf("%s: records before the error = %q", name, got)
+		}
+	}
+}
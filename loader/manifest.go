@@ -0,0 +1,154 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileState is what a Manifest remembers about a file.
+type FileState struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+	// Hash is the hex SHA-256 of the file contents.
+	Hash string `json:"hash"`
+}
+
+// Manifest records the files a DirectoryLoader has loaded, so an
+// incremental scan returns only new and changed files. Files with the
+// same size and modification time are skipped without being read; files
+// whose contents hash the same are skipped after reading. It is safe for
+// concurrent use and can be persisted with Save and ReadManifest.
+type Manifest struct {
+	mu      sync.Mutex
+	files   map[string]FileState
+	seen    map[string]bool
+	kept    []string
+	removed []string
+}
+
+// NewManifest creates an empty manifest; the first scan with it loads
+// every file.
+func NewManifest() *Manifest {
+	return &Manifest{files: make(map[string]FileState)}
+}
+
+// ReadManifest reads a manifest written by Save.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	m := NewManifest()
+	if err := json.NewDecoder(r).Decode(&m.files); err != nil {
+		return nil, fmt.Errorf("weave: read manifest: %w", err)
+	}
+	if m.files == nil {
+		m.files = make(map[string]FileState)
+	}
+	return m, nil
+}
+
+// Save writes the manifest as JSON.
+func (m *Manifest) Save(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m.files); err != nil {
+		return fmt.Errorf("weave: save manifest: %w", err)
+	}
+	return nil
+}
+
+// Get returns the recorded state of a file by its path relative to the
+// scanned directory.
+func (m *Manifest) Get(rel string) (FileState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.files[rel]
+	return state, ok
+}
+
+// Len returns the number of files recorded.
+func (m *Manifest) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.files)
+}
+
+// Removed returns the files, sorted, that the last complete scan no longer
+// found and dropped from the manifest. Files under a directory the scan
+// could not read are kept, not removed.
+func (m *Manifest) Removed() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.removed...)
+}
+
+// begin starts a scan.
+func (m *Manifest) begin() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seen = make(map[string]bool)
+	m.kept = nil
+	m.removed = nil
+}
+
+// keep marks rel, and every file below it, as seen because the scan could
+// not read it. Its files stay recorded rather than counting as removed.
+func (m *Manifest) keep(rel string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kept = append(m.kept, rel)
+}
+
+// unchanged marks rel as seen and reports whether its size and
+// modification time match the recorded state.
+func (m *Manifest) unchanged(rel string, modTime time.Time, size int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seen[rel] = true
+	old, ok := m.files[rel]
+	return ok && old.Size == size && old.ModTime.Equal(modTime)
+}
+
+// changed reports whether hash differs from the recorded hash of rel.
+func (m *Manifest) changed(rel, hash string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, ok := m.files[rel]
+	return !ok || old.Hash != hash
+}
+
+// record stores the state of rel.
+func (m *Manifest) record(rel string, state FileState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[rel] = state
+}
+
+// finish drops the files the scan did not see.
+func (m *Manifest) finish() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for rel := range m.files {
+		if !m.seen[rel] && !m.isKept(rel) {
+			delete(m.files, rel)
+			m.removed = append(m.removed, rel)
+		}
+	}
+	sort.Strings(m.removed)
+	m.seen = nil
+	m.kept = nil
+}
+
+// isKept reports whether rel is or is below a kept path.
+func (m *Manifest) isKept(rel string) bool {
+	for _, k := range m.kept {
+		if k == "." || rel == k || strings.HasPrefix(rel, k+"/") {
+			return true
+		}
+	}
+	return false
+}
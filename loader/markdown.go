@@ -1,14 +1,32 @@
 package loader
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.yaml.in/yaml/v3"
 )
 
-// MarkdownLoader strips Markdown formatting and returns plain text.
-type MarkdownLoader struct{}
+// MetaLinks lists the link targets of a Markdown document, one per line,
+// in order of first appearance.
+const MetaLinks = "links"
+
+// MarkdownLoader strips Markdown formatting and returns plain text. YAML
+// ("---") or TOML ("+++") front matter is parsed into the result metadata
+// and removed from the content: nested keys are joined with dots and
+// lists of values with ", ". Link targets are recorded under MetaLinks.
+type MarkdownLoader struct {
+	// KeepStructure keeps headings and code, fenced and inline, so
+	// structure-aware chunkers can split on them. Other formatting is
+	// still stripped.
+	KeepStructure bool
+}
 
 // NewMarkdownLoader creates a new MarkdownLoader.
 func NewMarkdownLoader() *MarkdownLoader { return &MarkdownLoader{} }
@@ -23,6 +41,11 @@ var (
 	reHR         = regexp.MustCompile(`(?m)^[-*_]{3,}\s*$`)
 	reListMarker = regexp.MustCompile(`(?m)^\s*[-*+]\s+`)
 	reNumList    = regexp.MustCompile(`(?m)^\s*\d+\.\s+`)
+
+	reLinkTarget = regexp.MustCompile(`(!?)\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	reRefUse     = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	reRefLink    = regexp.MustCompile(`(?m)^\s{0,3}\[[^\]]+\]:\s*<?(\S+?)>?(?:\s+.*)?$`)
+	reAutoLink   = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
 )
 
 // Load reads Markdown and returns plain text.
@@ -32,25 +55,227 @@ func (l *MarkdownLoader) Load(_ context.Context, reader io.Reader) (*LoadResult,
 		return nil, err
 	}
 
-	text := string(data)
-	text = reCodeBlock.ReplaceAllString(text, "")
-	text = reCode.ReplaceAllString(text, "")
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\uFEFF"), "\r\n", "\n")
+	meta, text := frontMatter(text)
+
+	// Code is kept out of link collection and stripping.
+	var prose strings.Builder
+	segments := splitFences(text)
+	for _, seg := range segments {
+		if !seg.code {
+			prose.WriteString(reCode.ReplaceAllString(seg.text, ""))
+		}
+	}
+	if links := markdownLinks(prose.String()); len(links) > 0 {
+		meta[MetaLinks] = strings.Join(links, "\n")
+	}
+
+	var b strings.Builder
+	for _, seg := range segments {
+		switch {
+		case seg.code && l.KeepStructure:
+			b.WriteString(seg.text)
+		case seg.code:
+		default:
+			b.WriteString(l.strip(seg.text))
+		}
+	}
+
+	result := &LoadResult{
+		Content:  strings.TrimSpace(b.String()),
+		MimeType: "text/markdown",
+	}
+	if len(meta) > 0 {
+		result.Metadata = meta
+	}
+	return result, nil
+}
+
+// strip removes Markdown formatting from prose.
+func (l *MarkdownLoader) strip(text string) string {
+	var spans []string
+	if l.KeepStructure {
+		// Set inline code aside so the rules below leave it alone.
+		text = reCode.ReplaceAllStringFunc(text, func(code string) string {
+			spans = append(spans, code)
+			return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+		})
+	} else {
+		text = reCodeBlock.ReplaceAllString(text, "")
+		text = reCode.ReplaceAllString(text, "")
+		text = reHeaders.ReplaceAllString(text, "")
+	}
 	text = reImages.ReplaceAllString(text, "$1")
 	text = reLinks.ReplaceAllString(text, "$1")
-	text = reHeaders.ReplaceAllString(text, "")
+	text = reRefUse.ReplaceAllString(text, "$1")
+	text = reRefLink.ReplaceAllString(text, "")
+	text = reAutoLink.ReplaceAllString(text, "$1")
 	text = reBoldItalic.ReplaceAllString(text, "$1")
 	text = reHR.ReplaceAllString(text, "")
 	text = reListMarker.ReplaceAllString(text, "")
 	text = reNumList.ReplaceAllString(text, "")
-	text = strings.TrimSpace(text)
-
-	return &LoadResult{
-		Content:  text,
-		MimeType: "text/markdown",
-	}, nil
+	for i, code := range spans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), code, 1)
+	}
+	return text
 }
 
 // Supports returns true for Markdown MIME types.
 func (l *MarkdownLoader) Supports(mimeType string) bool {
 	return mimeType == "text/markdown" || mimeType == "text/x-markdown"
 }
+
+// mdSegment is a run of Markdown that is either a fenced code block or
+// prose.
+type mdSegment struct {
+	text string
+	code bool
+}
+
+// splitFences splits Markdown into prose and fenced code blocks. An
+// unclosed fence runs to the end of the text.
+func splitFences(text string) []mdSegment {
+	var segments []mdSegment
+	var cur strings.Builder
+	fence := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				if cur.Len() > 0 {
+					segments = append(segments, mdSegment{text: cur.String()})
+					cur.Reset()
+				}
+				fence = trimmed[:3]
+			}
+			cur.WriteString(line)
+			continue
+		}
+		cur.WriteString(line)
+		if strings.HasPrefix(strings.TrimSpace(trimmed), fence) && strings.Trim(strings.TrimSpace(trimmed), fence[:1]) == "" {
+			segments = append(segments, mdSegment{text: cur.String(), code: true})
+			cur.Reset()
+			fence = ""
+		}
+	}
+	if cur.Len() > 0 {
+		segments = append(segments, mdSegment{text: cur.String(), code: fence != ""})
+	}
+	return segments
+}
+
+// markdownLinks returns the distinct link targets in prose, skipping
+// images and in-page anchors.
+func markdownLinks(prose string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	add := func(target string) {
+		if target == "" || strings.HasPrefix(target, "#") || seen[target] {
+			return
+		}
+		seen[target] = true
+		links = append(links, target)
+	}
+	for _, m := range reLinkTarget.FindAllStringSubmatch(prose, -1) {
+		if m[1] == "" {
+			add(m[2])
+		}
+	}
+	for _, m := range reRefLink.FindAllStringSubmatch(prose, -1) {
+		add(m[1])
+	}
+	for _, m := range reAutoLink.FindAllStringSubmatch(prose, -1) {
+		add(m[1])
+	}
+	return links
+}
+
+// frontMatter parses a leading YAML or TOML front matter block into flat
+// metadata and returns the text after it. A block that does not parse as
+// a YAML mapping or TOML table, such as text between two horizontal
+// rules, is not front matter and is left in the text.
+func frontMatter(text string) (map[string]string, string) {
+	meta := make(map[string]string)
+	var delim string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delim = "+++"
+	default:
+		return meta, text
+	}
+
+	rest := text[len(delim)+1:]
+	var block string
+	for offset := 0; ; {
+		line, _, found := strings.Cut(rest[offset:], "\n")
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == delim || (delim == "---" && trimmed == "...") {
+			block = rest[:offset]
+			rest = rest[min(offset+len(line)+1, len(rest)):]
+			break
+		}
+		if !found {
+			// No closing delimiter: not front matter.
+			return meta, text
+		}
+		offset += len(line) + 1
+	}
+
+	values := make(map[string]any)
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal([]byte(block), &values)
+	} else {
+		_, err = toml.NewDecoder(bytes.NewReader([]byte(block))).Decode(&values)
+	}
+	if err != nil {
+		return meta, text
+	}
+	for k, v := range values {
+		flattenMeta(k, v, meta)
+	}
+	return meta, rest
+}
+
+// flattenMeta stores a front matter value under key, joining nested keys
+// with dots and lists of scalars with ", ".
+func flattenMeta(key string, value any, meta map[string]string) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]any:
+		for k, child := range v {
+			flattenMeta(key+"."+k, child, meta)
+		}
+	case []map[string]any:
+		for i, child := range v {
+			flattenMeta(fmt.Sprintf("%s.%d", key, i), child, meta)
+		}
+	case []any:
+		scalars := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, []any, []map[string]any:
+				for i, item := range v {
+					flattenMeta(fmt.Sprintf("%s.%d", key, i), item, meta)
+				}
+				return
+			}
+			scalars = append(scalars, metaString(item))
+		}
+		meta[key] = strings.Join(scalars, ", ")
+	default:
+		meta[key] = metaString(v)
+	}
+}
+
+// metaString formats a scalar front matter value.
+func metaString(v any) string {
+	if t, ok := v.(time.Time); ok {
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
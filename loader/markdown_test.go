@@ -0,0 +1,96 @@
+package loader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+const markdownDoc = "---\r\n" +
+	"title: Cache tuning\r\n" +
+	"date: 2024-03-01\r\n" +
+	"tags: [cache, performance]\r\n" +
+	"author:\r\n" +
+	"  name: Ada\r\n" +
+	"---\r\n" +
+	"# Overview\r\n" +
+	"\r\n" +
+	"See the [guide](https://example.com/guide) and [setup][s], or jump to [usage](#usage).\r\n" +
+	"![diagram](img/cache.png) and <https://example.com/faq>.\r\n" +
+	"\r\n" +
+	"```go\r\n" +
+	"cache.SetPolicy(LFU) // [not a link](https://example.com/code)\r\n" +
+	"```\r\n" +
+	"\r\n" +
+	"[s]: https://example.com/setup\r\n"
+
+func TestMarkdownLoaderFrontMatter(t *testing.T) {
+	res, err := loader.NewMarkdownLoader().Load(context.Background(), strings.NewReader(markdownDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{
+		"title":          "Cache tuning",
+		"date":           "2024-03-01",
+		"tags":           "cache, performance",
+		"author.name":    "Ada",
+		loader.MetaLinks: "https://example.com/guide\nhttps://example.com/setup\nhttps://example.com/faq",
+	} {
+		if got := res.Metadata[k]; got != want {
+			t.Errorf("Metadata[%q] = %q, want %q", k, got, want)
+		}
+	}
+	for _, noise := range []string{"title:", "---", "# Overview", "cache.SetPolicy", "https://example.com/setup"} {
+		if strings.Contains(res.Content, noise) {
+			t.Errorf("content contains %q:\n%s", noise, res.Content)
+		}
+	}
+	if !strings.HasPrefix(res.Content, "Overview\n\nSee the guide and setup") {
+		t.Errorf("content = %q", res.Content)
+	}
+
+	keep := &loader.MarkdownLoader{KeepStructure: true}
+	res, err = keep.Load(context.Background(), strings.NewReader(markdownDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Overview", "```go\ncache.SetPolicy(LFU) // [not a link](https://example.com/code)\n```", "See the guide"} {
+		if !strings.Contains(res.Content, want) {
+			t.Errorf("KeepStructure content missing %q:\n%s", want, res.Content)
+		}
+	}
+}
+
+func TestMarkdownLoaderTOMLFrontMatter(t *testing.T) {
+	doc := "+++\ntitle = \"Release notes\"\n[params]\nversion = 2\n+++\nBody text.\n"
+	res, err := loader.NewMarkdownLoader().Load(context.Background(), strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Metadata["title"] != "Release notes" || res.Metadata["params.version"] != "2" {
+		t.Errorf("metadata = %v", res.Metadata)
+	}
+	if res.Content != "Body text." {
+		t.Errorf("content = %q", res.Content)
+	}
+
+}
+
+func TestMarkdownLoaderRuleIsNotFrontMatter(t *testing.T) {
+	// A document opening with a horizontal rule, or with a block that does
+	// not parse, keeps the block as content.
+	for doc, want := range map[string]string{
+		"---\nIntro paragraph, with a comma.\n\n---\nMore text.\n": "Intro paragraph, with a comma.",
+		"---\ntitle: [broken\n---\nx\n":                            "title: [broken",
+	} {
+		res, err := loader.NewMarkdownLoader().Load(context.Background(), strings.NewReader(doc))
+		if err != nil {
+			t.Fatalf("%q: %v", doc, err)
+		}
+		if len(res.Metadata) != 0 || !strings.HasPrefix(res.Content, want) {
+			t.Errorf("%q: metadata = %v, content = %q", doc, res.Metadata, res.Content)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package loader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// MIME types of the Office Open XML formats.
+const (
+	MimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MimePPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	MimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// OfficeLoader loads any Office Open XML document, choosing the DOCX,
+// PPTX or XLSX loader from the parts inside the package. Use it when one
+// loader has to accept all three formats, as with engine.WithLoader.
+type OfficeLoader struct {
+	DOCX *DOCXLoader
+	PPTX *PPTXLoader
+	XLSX *XLSXLoader
+}
+
+// NewOfficeLoader creates an OfficeLoader with default format loaders.
+func NewOfficeLoader() *OfficeLoader {
+	return &OfficeLoader{DOCX: NewDOCXLoader(), PPTX: NewPPTXLoader(), XLSX: NewXLSXLoader()}
+}
+
+// Load detects the document format and delegates to its loader.
+func (l *OfficeLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := openOffice(ctx, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var delegate Loader
+	switch {
+	case zipHas(zr, "word/document.xml"):
+		delegate = l.DOCX
+	case zipHas(zr, "ppt/presentation.xml"):
+		delegate = l.PPTX
+	case zipHas(zr, "xl/workbook.xml"):
+		delegate = l.XLSX
+	}
+	if delegate == nil {
+		return nil, errors.New("weave: office load: unrecognised document")
+	}
+	return delegate.Load(ctx, bytes.NewReader(data))
+}
+
+// Supports returns true for the DOCX, PPTX and XLSX MIME types.
+func (l *OfficeLoader) Supports(mimeType string) bool {
+	return mimeType == MimeDOCX || mimeType == MimePPTX || mimeType == MimeXLSX
+}
+
+// DefaultPackageMaxSize caps the decompressed size of the parts read from
+// one Office or EPUB package. Inside an archive, the ArchiveLoader limits
+// apply instead when they are lower.
+const DefaultPackageMaxSize = 256 << 20
+
+// zipPackage is a zip-based document package, such as an Office Open XML
+// or EPUB file. Its parts are decompressed against a size budget, so a
+// small package cannot expand into unbounded memory.
+type zipPackage struct {
+	*zip.Reader
+	budget *sizeBudget
+}
+
+// sizeBudget is the number of bytes that may still be decompressed from
+// packages during one load. Packages nested in an archive share the
+// archive's budget.
+type sizeBudget struct {
+	limit     int64
+	remaining int64
+}
+
+type sizeBudgetKey struct{}
+
+// withSizeBudget returns a context whose package loads draw on budget.
+func withSizeBudget(ctx context.Context, budget *sizeBudget) context.Context {
+	return context.WithValue(ctx, sizeBudgetKey{}, budget)
+}
+
+// openZipPackage reads a zip-based package into memory, with the size
+// budget of ctx or else DefaultPackageMaxSize.
+func openZipPackage(ctx context.Context, reader io.Reader) (*zipPackage, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	budget, ok := ctx.Value(sizeBudgetKey{}).(*sizeBudget)
+	if !ok {
+		budget = &sizeBudget{limit: DefaultPackageMaxSize, remaining: DefaultPackageMaxSize}
+	}
+	return &zipPackage{Reader: zr, budget: budget}, nil
+}
+
+// openPart opens a part for reading. Reads fail with ErrArchiveLimit
+// once the package has decompressed more than its budget.
+func (p *zipPackage) openPart(f *zip.File) (io.ReadCloser, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &budgetReader{ReadCloser: rc, budget: p.budget}, nil
+}
+
+// budgetReader counts the bytes read from a part against a sizeBudget.
+type budgetReader struct {
+	io.ReadCloser
+	budget *sizeBudget
+}
+
+func (r *budgetReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	if r.budget.remaining -= int64(n); r.budget.remaining < 0 {
+		return n, fmt.Errorf("%w: package decompresses to more than %d bytes", ErrArchiveLimit, r.budget.limit)
+	}
+	return n, err
+}
+
+// openOffice reads an Office Open XML package into memory.
+func openOffice(ctx context.Context, reader io.Reader) (*zipPackage, error) {
+	zr, err := openZipPackage(ctx, reader)
+	if err != nil {
+		return nil, fmt.Errorf("weave: office load: %w", err)
+	}
+	return zr, nil
+}
+
+// zipHas reports whether the package contains the named part.
+func zipHas(zr *zipPackage, name string) bool {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readPart parses the named part as XML. A missing part yields nil and
+// no error, since most parts are optional.
+func readPart(zr *zipPackage, name string) (*xmlNode, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := zr.openPart(f)
+		if err != nil {
+			return nil, fmt.Errorf("weave: office load %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }()
+		node, err := parseXML(rc)
+		if err != nil {
+			return nil, fmt.Errorf("weave: office load %s: %w", name, err)
+		}
+		return node, nil
+	}
+	return nil, nil
+}
+
+// partRels returns the relationships of a part, keyed by relationship ID,
+// with targets resolved to part names.
+func partRels(zr *zipPackage, part string) (map[string]officeRel, error) {
+	relsName := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+	root, err := readPart(zr, relsName)
+	if err != nil || root == nil {
+		return nil, err
+	}
+	rels := make(map[string]officeRel)
+	for _, r := range root.all("Relationship") {
+		target := r.attr("Target")
+		if r.attr("TargetMode") == "External" {
+			continue
+		}
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(path.Dir(part), target)
+		}
+		rels[r.attr("Id")] = officeRel{Type: r.attr("Type"), Target: target}
+	}
+	return rels, nil
+}
+
+// officeRel is a relationship from one package part to another.
+type officeRel struct {
+	Type   string
+	Target string
+}
+
+// coreProperties returns the title and author from docProps/core.xml.
+func coreProperties(zr *zipPackage, meta map[string]string) error {
+	core, err := readPart(zr, "docProps/core.xml")
+	if err != nil || core == nil {
+		return err
+	}
+	if title := strings.TrimSpace(core.childText("title")); title != "" {
+		meta["title"] = title
+	}
+	if author := strings.TrimSpace(core.childText("creator")); author != "" {
+		meta["author"] = author
+	}
+	return nil
+}
+
+// markdownTable renders rows as a Markdown table with the first row as
+// the header.
+func markdownTable(rows [][]string) string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i := range width {
+			cell := ""
+			if i < len(row) {
+				cell = strings.Join(strings.Fields(row[i]), " ")
+				cell = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// relationshipsNS is the namespace of attributes that reference other
+// package parts, such as r:id.
+const relationshipsNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+// xmlNode is a parsed XML element. Names are local names; namespaces are
+// dropped because Office parts use fixed, well-known prefixes, except that
+// relationship attributes are keyed "r:<name>" to keep r:id apart from id.
+type xmlNode struct {
+	Name     string
+	Attrs    map[string]string
+	Children []*xmlNode
+	// Text is set on character data nodes, which have no Name.
+	Text string
+}
+
+// parseXML parses an XML document into a tree.
+func parseXML(r io.Reader) (*xmlNode, error) {
+	dec := xml.NewDecoder(r)
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{Name: t.Name.Local, Attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				key := a.Name.Local
+				if a.Name.Space == relationshipsNS {
+					key = "r:" + key
+				}
+				n.Attrs[key] = a.Value
+			}
+			top.Children = append(top.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			top.Children = append(top.Children, &xmlNode{Text: string(t)})
+		}
+	}
+	for _, n := range root.Children {
+		if n.Name != "" {
+			return n, nil
+		}
+	}
+	return nil, errors.New("empty document")
+}
+
+// attr returns the value of an attribute by local name.
+func (n *xmlNode) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	return n.Attrs[name]
+}
+
+// child returns the first child element with the given name, or nil.
+func (n *xmlNode) child(name string) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// text returns the character data directly inside n.
+func (n *xmlNode) text() string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, t := range n.Children {
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
+
+// childText returns the character data of the named child element.
+func (n *xmlNode) childText(name string) string { return n.child(name).text() }
+
+// all returns every descendant element with the given name, in document
+// order, without descending into matches.
+func (n *xmlNode) all(name string) []*xmlNode {
+	var out []*xmlNode
+	var walk func(*xmlNode)
+	walk = func(n *xmlNode) {
+		for _, c := range n.Children {
+			if c.Name == name {
+				out = append(out, c)
+				continue
+			}
+			walk(c)
+		}
+	}
+	if n != nil {
+		walk(n)
+	}
+	return out
+}
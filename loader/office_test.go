@@ -0,0 +1,126 @@
+package loader_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+// buildZip packs the given parts into an Office Open XML package.
+func buildZip(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const (
+	nsW = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+	nsR = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	nsP = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"`
+)
+
+func TestDOCXLoader(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"word/document.xml": `<w:document ` + nsW + `><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Install</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Run the </w:t></w:r><w:r><w:t>installer.</w:t></w:r><w:r><w:delText>old</w:delText></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>First</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Second</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Key</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Value</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>port</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>8080</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`,
+		"word/styles.xml": `<w:styles ` + nsW + `><w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style></w:styles>`,
+		"word/numbering.xml": `<w:numbering ` + nsW + `><w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>
+<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`,
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Guide</dc:title></cp:coreProperties>`,
+	})
+
+	res, err := loader.NewOfficeLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Install\n\nRun the installer.\n\n1. First\n2. Second\n\n| Key | Value |\n| --- | --- |\n| port | 8080 |"
+	if res.Content != want {
+		t.Errorf("content = %q, want %q", res.Content, want)
+	}
+	if res.Metadata["title"] != "Guide" || res.MimeType != loader.MimeDOCX {
+		t.Errorf("metadata = %v, mime = %q", res.Metadata, res.MimeType)
+	}
+}
+
+func TestPPTXLoader(t *testing.T) {
+	slide := func(title, body string) string {
+		return `<p:sld ` + nsP + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="title"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + title + `</a:t></a:r></a:p></p:txBody></p:sp>
+<p:sp><p:nvSpPr><p:nvPr/></p:nvSpPr><p:txBody><a:p><a:r><a:t>` + body + `</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:sld>`
+	}
+	rel := func(id, typ, target string) string {
+		return `<Relationship Id="` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/` + typ + `" Target="` + target + `"/>`
+	}
+	// rId9 has no relationship, so that slide is skipped and the others
+	// are numbered as they appear.
+	data := buildZip(t, map[string]string{
+		"ppt/presentation.xml":             `<p:presentation ` + nsP + ` ` + nsR + `><p:sldIdLst><p:sldId id="255" r:id="rId9"/><p:sldId id="256" r:id="rId2"/><p:sldId id="257" r:id="rId1"/></p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels":  `<Relationships>` + rel("rId1", "slide", "slides/slide2.xml") + rel("rId2", "slide", "slides/slide1.xml") + `</Relationships>`,
+		"ppt/slides/slide1.xml":            slide("Welcome", "Agenda"),
+		"ppt/slides/slide2.xml":            slide("Pricing", "Three tiers"),
+		"ppt/slides/_rels/slide2.xml.rels": `<Relationships>` + rel("rId1", "notesSlide", "../notesSlides/notesSlide1.xml") + `</Relationships>`,
+		"ppt/notesSlides/notesSlide1.xml": `<p:notes ` + nsP + `><p:cSld><p:spTree>
+<p:sp><p:nvSpPr><p:nvPr><p:ph type="body"/></p:nvPr></p:nvSpPr><p:txBody><a:p><a:r><a:t>Mention the discount.</a:t></a:r></a:p></p:txBody></p:sp>
+</p:spTree></p:cSld></p:notes>`,
+	})
+
+	res, err := loader.NewPPTXLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Slide 1: Welcome\n\nAgenda\n\n## Slide 2: Pricing\n\nThree tiers\n\nNotes: Mention the discount."
+	if res.Content != want {
+		t.Errorf("content = %q, want %q", res.Content, want)
+	}
+	offsets := loader.PageOffsets(res.Metadata)
+	if res.Metadata[loader.MetaSlideCount] != "2" || len(offsets) != 2 ||
+		!strings.HasPrefix(res.Content[offsets[1]:], "## Slide 2") {
+		t.Errorf("metadata = %v", res.Metadata)
+	}
+}
+
+func TestXLSXLoader(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` + nsR + `><sheets><sheet name="Orders" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>Item</t></si><si><t>Date</t></si><si><r><t>Wid</t></r><r><t>get</t></r></si></sst>`,
+		"xl/styles.xml":              `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cellXfs><xf numFmtId="0"/><xf numFmtId="14"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="2"><c r="B2" t="s"><v>0</v></c><c r="C2" t="s"><v>1</v></c></row>
+<row r="3"><c r="B3" t="s"><v>2</v></c><c r="C3" s="1"><v>45292</v></c></row>
+</sheetData></worksheet>`,
+	})
+
+	res, err := loader.NewXLSXLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Orders\n\n| Item | Date |\n| --- | --- |\n| Widget | 2024-01-01 |"
+	if res.Content != want {
+		t.Errorf("content = %q, want %q", res.Content, want)
+	}
+}
@@ -0,0 +1,357 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Metadata keys set by PDFLoader.
+const (
+	// MetaPageCount is the number of pages in the document.
+	MetaPageCount = "page_count"
+	// MetaPageOffsets lists the byte offset in Content at which each page
+	// starts, comma-separated. See PageOffsets and PageAt.
+	MetaPageOffsets = "page_offsets"
+)
+
+var (
+	// ErrPDFEncrypted is returned for password-protected PDFs that cannot
+	// be opened with the configured password.
+	ErrPDFEncrypted = errors.New("weave: pdf is encrypted")
+	// ErrPDFNoText is returned for PDFs without extractable text, such as
+	// scanned documents that contain only images.
+	ErrPDFNoText = errors.New("weave: pdf has no extractable text")
+)
+
+// PDFLoader extracts text from PDF documents page by page. Glyphs are
+// grouped into lines by their position on the page, so text drawn out of
+// order still reads left to right, top to bottom. Fonts using the standard
+// encodings and ToUnicode maps are decoded; scanned pages are not OCRed.
+type PDFLoader struct {
+	// Password opens encrypted documents. Documents encrypted with an
+	// empty user password open without it.
+	Password string
+	// PageSeparator is written between pages (default "\n\n").
+	PageSeparator string
+}
+
+// NewPDFLoader creates a new PDFLoader.
+func NewPDFLoader() *PDFLoader { return &PDFLoader{} }
+
+// Load reads a PDF and returns its text. The result metadata records the
+// page count, the offset of every page in Content, and the title and
+// author from the document information dictionary when present.
+func (l *PDFLoader) Load(_ context.Context, reader io.Reader) (result *LoadResult, err error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// The PDF parser panics on some malformed input.
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("weave: malformed pdf: %v", r)
+		}
+	}()
+
+	var pw func() string
+	if l.Password != "" {
+		tried := false
+		pw = func() string {
+			if tried {
+				return ""
+			}
+			tried = true
+			return l.Password
+		}
+	}
+	r, err := pdf.NewReaderEncrypted(bytes.NewReader(data), int64(len(data)), pw)
+	if err != nil {
+		// The parser reports unsupported encryption with untyped errors,
+		// so look for the trailer's /Encrypt entry instead.
+		if errors.Is(err, pdf.ErrInvalidPassword) || encrypted(data) {
+			return nil, ErrPDFEncrypted
+		}
+		return nil, fmt.Errorf("weave: read pdf: %w", err)
+	}
+
+	sep := l.PageSeparator
+	if sep == "" {
+		sep = "\n\n"
+	}
+
+	var b strings.Builder
+	pages := r.NumPage()
+	offsets := make([]string, 0, pages)
+	for i := 1; i <= pages; i++ {
+		text := pageText(r.Page(i))
+		if b.Len() > 0 && text != "" {
+			b.WriteString(sep)
+		}
+		offsets = append(offsets, strconv.Itoa(b.Len()))
+		b.WriteString(text)
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return nil, ErrPDFNoText
+	}
+
+	meta := map[string]string{
+		MetaPageCount:   strconv.Itoa(pages),
+		MetaPageOffsets: strings.Join(offsets, ","),
+	}
+	info := r.Trailer().Key("Info")
+	if title := strings.TrimSpace(info.Key("Title").Text()); title != "" {
+		meta["title"] = title
+	}
+	if author := strings.TrimSpace(info.Key("Author").Text()); author != "" {
+		meta["author"] = author
+	}
+
+	return &LoadResult{
+		Content:  b.String(),
+		Metadata: meta,
+		MimeType: "application/pdf",
+	}, nil
+}
+
+// Supports returns true for PDF.
+func (l *PDFLoader) Supports(mimeType string) bool {
+	return mimeType == "application/pdf"
+}
+
+// encryptEntry matches an /Encrypt key followed by a reference or an
+// inline dictionary.
+var encryptEntry = regexp.MustCompile(`/Encrypt(\s+\d|\s*<<)`)
+
+// encrypted reports whether a trailer dictionary of the PDF has an
+// /Encrypt entry. Trailers follow the "trailer" keyword or, with PDF 1.5
+// cross-reference streams, are the dictionary of the /XRef stream object.
+func encrypted(data []byte) bool {
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		i += j + len("trailer")
+		if encryptEntry.Match(pdfDict(data, i)) {
+			return true
+		}
+	}
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("/XRef"))
+		if j < 0 {
+			break
+		}
+		if obj := bytes.LastIndex(data[:i+j], []byte("obj")); obj >= 0 && encryptEntry.Match(pdfDict(data, obj)) {
+			return true
+		}
+		i += j + len("/XRef")
+	}
+	return false
+}
+
+// pdfDict returns the first dictionary at or after offset, including any
+// nested dictionaries, or nil when there is none.
+func pdfDict(data []byte, offset int) []byte {
+	start := bytes.Index(data[offset:], []byte("<<"))
+	if start < 0 {
+		return nil
+	}
+	start += offset
+	depth := 0
+	for i := start; i+1 < len(data); i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return data[start : i+1]
+			}
+		}
+	}
+	return nil
+}
+
+// PageOffsets parses MetaPageOffsets from loader metadata. It returns nil
+// when the metadata has no page offsets.
+func PageOffsets(meta map[string]string) []int {
+	return parseOffsets(meta[MetaPageOffsets])
+}
+
+// parseOffsets parses a comma-separated list of byte offsets.
+func parseOffsets(raw string) []int {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	offsets := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil
+		}
+		offsets = append(offsets, n)
+	}
+	return offsets
+}
+
+// PageAt returns the 1-based page containing the byte offset, given the
+// page offsets returned by PageOffsets. It works the same way for the
+// chapters returned by ChapterOffsets.
+func PageAt(offsets []int, offset int) int {
+	return sort.Search(len(offsets), func(i int) bool { return offsets[i] > offset })
+}
+
+// textLine is a run of glyphs sharing a baseline.
+type textLine struct {
+	text   string
+	x0, x1 float64
+	y      float64
+	size   float64
+}
+
+// pageText returns the text of a page in reading order.
+func pageText(p pdf.Page) string {
+	if p.V.IsNull() {
+		return ""
+	}
+
+	// Group glyphs into lines, starting a new line when the baseline moves.
+	var groups [][]pdf.Text
+	for _, t := range p.Content().Text {
+		if t.S == "" {
+			continue
+		}
+		if n := len(groups); n > 0 {
+			last := groups[n-1][len(groups[n-1])-1]
+			if math.Abs(t.Y-last.Y) < math.Max(math.Max(t.FontSize, last.FontSize), 1)/2 {
+				groups[n-1] = append(groups[n-1], t)
+				continue
+			}
+		}
+		groups = append(groups, []pdf.Text{t})
+	}
+	lines := make([]textLine, 0, len(groups))
+	for _, g := range groups {
+		if line := newTextLine(g); line.text != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	var b strings.Builder
+	lines = readingOrder(lines)
+	for i, line := range lines {
+		if i > 0 {
+			// A gap of more than one and a half lines, or a jump back up
+			// to the next column, starts a paragraph.
+			prev := lines[i-1]
+			if gap := prev.y - line.y; gap < 0 || gap > 1.5*math.Max(prev.size, 1) {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString(line.text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// newTextLine joins glyphs left to right, inserting a space where the gap
+// between glyphs is wider than a fraction of the font size.
+func newTextLine(glyphs []pdf.Text) textLine {
+	sort.SliceStable(glyphs, func(i, j int) bool { return glyphs[i].X < glyphs[j].X })
+
+	var b strings.Builder
+	line := textLine{x0: glyphs[0].X, y: glyphs[0].Y}
+	for i, t := range glyphs {
+		if i > 0 {
+			prev := glyphs[i-1]
+			gap := t.X - (prev.X + prev.W)
+			if gap > 0.15*math.Max(prev.FontSize, 1) &&
+				!strings.HasSuffix(prev.S, " ") && !strings.HasPrefix(t.S, " ") {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(t.S)
+		line.x1 = math.Max(line.x1, t.X+t.W)
+		line.size = math.Max(line.size, t.FontSize)
+	}
+	line.text = strings.TrimSpace(b.String())
+	return line
+}
+
+// readingOrder sorts lines top to bottom. When the page is laid out in
+// columns, found as vertical gutters no line crosses, each column is read
+// to the bottom before the next; lines spanning several columns, such as
+// headings, separate bands that are read in turn.
+func readingOrder(lines []textLine) []textLine {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].y > lines[j].y })
+	if len(lines) < 2 {
+		return lines
+	}
+
+	// Merge the horizontal extents of lines narrower than 60% of the text
+	// block into columns.
+	left, right := lines[0].x0, lines[0].x1
+	for _, l := range lines {
+		left, right = math.Min(left, l.x0), math.Max(right, l.x1)
+	}
+	var narrow []textLine
+	for _, l := range lines {
+		if l.x1-l.x0 < 0.6*(right-left) {
+			narrow = append(narrow, l)
+		}
+	}
+	sort.Slice(narrow, func(i, j int) bool { return narrow[i].x0 < narrow[j].x0 })
+	var columns [][2]float64
+	for _, l := range narrow {
+		if n := len(columns); n > 0 && l.x0 <= columns[n-1][1] {
+			columns[n-1][1] = math.Max(columns[n-1][1], l.x1)
+			continue
+		}
+		columns = append(columns, [2]float64{l.x0, l.x1})
+	}
+	if len(columns) < 2 {
+		return lines
+	}
+
+	column := func(l textLine) int {
+		for i, c := range columns {
+			if l.x0 >= c[0] && l.x1 <= c[1] {
+				return i
+			}
+		}
+		return -1
+	}
+	ordered := make([]textLine, 0, len(lines))
+	band := make([][]textLine, len(columns))
+	flush := func() {
+		for i := range band {
+			ordered = append(ordered, band[i]...)
+			band[i] = nil
+		}
+	}
+	for _, l := range lines {
+		if c := column(l); c >= 0 {
+			band[c] = append(band[c], l)
+			continue
+		}
+		flush()
+		ordered = append(ordered, l)
+	}
+	flush()
+	return ordered
+}
@@ -0,0 +1,119 @@
+package loader_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/loader"
+)
+
+// buildPDF writes a minimal PDF with one Helvetica text stream per page.
+func buildPDF(pages ...string) []byte {
+	n := len(pages)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // pages tree, filled in below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	kids := make([]string, n)
+	for i, stream := range pages {
+		page := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", page+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), n)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+func TestPDFLoaderPages(t *testing.T) {
+	// The second page draws its lines bottom first.
+	data := buildPDF(
+		"BT /F1 12 Tf 72 720 Td (Hello world) Tj 0 -14 Td (second line) Tj ET",
+		"BT /F1 12 Tf 72 686 Td (last) Tj ET BT /F1 12 Tf 72 700 Td (Page two) Tj ET",
+	)
+
+	res, err := loader.NewPDFLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Metadata[loader.MetaPageCount] != "2" {
+		t.Errorf("page count = %q", res.Metadata[loader.MetaPageCount])
+	}
+	offsets := loader.PageOffsets(res.Metadata)
+	if len(offsets) != 2 {
+		t.Fatalf("offsets = %v", offsets)
+	}
+	page1, page2 := res.Content[:offsets[1]], res.Content[offsets[1]:]
+	if !strings.Contains(page1, "Hello world\nsecond line") {
+		t.Errorf("page 1 = %q", page1)
+	}
+	if !strings.HasPrefix(page2, "Page two") {
+		t.Errorf("page 2 = %q", page2)
+	}
+	if got := loader.PageAt(offsets, offsets[1]+2); got != 2 {
+		t.Errorf("PageAt = %d, want 2", got)
+	}
+}
+
+func TestPDFLoaderNoText(t *testing.T) {
+	data := buildPDF("0 0 m 100 100 l S")
+	_, err := loader.NewPDFLoader().Load(context.Background(), bytes.NewReader(data))
+	if !errors.Is(err, loader.ErrPDFNoText) {
+		t.Errorf("err = %v, want ErrPDFNoText", err)
+	}
+}
+
+func TestPDFLoaderEncrypted(t *testing.T) {
+	// AES-256 (V=5) is not supported by the parser, which reports it with
+	// an untyped error.
+	data := bytes.Replace(buildPDF("BT /F1 12 Tf 72 720 Td (secret) Tj ET"),
+		[]byte("/Root 1 0 R >>"),
+		[]byte("/Root 1 0 R /Encrypt << /Filter /Standard /V 5 /R 6 /Length 256 >> >>"), 1)
+	_, err := loader.NewPDFLoader().Load(context.Background(), bytes.NewReader(data))
+	if !errors.Is(err, loader.ErrPDFEncrypted) {
+		t.Errorf("err = %v, want ErrPDFEncrypted", err)
+	}
+
+	// A broken file that is not encrypted is not reported as encrypted.
+	data = bytes.Replace(buildPDF("BT /F1 12 Tf 72 720 Td (plain) Tj ET"), []byte("/Root 1 0 R"), []byte("/Root 9 0 R"), 1)
+	_, err = loader.NewPDFLoader().Load(context.Background(), bytes.NewReader(data))
+	if err == nil || errors.Is(err, loader.ErrPDFEncrypted) {
+		t.Errorf("err = %v, want a read error", err)
+	}
+}
+
+func TestPDFLoaderColumns(t *testing.T) {
+	data := buildPDF("BT /F1 12 Tf 72 740 Td (A two column page title that spans the page) Tj ET " +
+		"BT /F1 12 Tf 72 700 Td (left one) Tj 0 -14 Td (left two) Tj ET " +
+		"BT /F1 12 Tf 320 700 Td (right one) Tj 0 -14 Td (right two) Tj ET")
+
+	res, err := loader.NewPDFLoader().Load(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "A two column page title that spans the page\n\nleft one\nleft two\n\nright one\nright two"
+	if res.Content != want {
+		t.Errorf("content = %q, want %q", res.Content, want)
+	}
+}
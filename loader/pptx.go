@@ -0,0 +1,215 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MetaSlideCount is the number of slides in a presentation. PPTXLoader
+// also records MetaPageOffsets with one entry per slide, so chunks are
+// annotated with the slide they come from.
+const MetaSlideCount = "slide_count"
+
+// PPTXLoader extracts text from PowerPoint presentations slide by slide.
+// Each slide starts with a "## Slide N" heading carrying its title,
+// followed by the text of its shapes and tables and then its speaker
+// notes.
+type PPTXLoader struct {
+	// SkipNotes leaves speaker notes out of the content.
+	SkipNotes bool
+}
+
+// NewPPTXLoader creates a new PPTXLoader.
+func NewPPTXLoader() *PPTXLoader { return &PPTXLoader{} }
+
+// Load reads a .pptx package and returns its text.
+func (l *PPTXLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	zr, err := openOffice(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	const presentation = "ppt/presentation.xml"
+	pres, err := readPart(zr, presentation)
+	if err != nil {
+		return nil, err
+	}
+	if pres == nil {
+		return nil, errors.New("weave: pptx load: missing presentation")
+	}
+	rels, err := partRels(zr, presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	var offsets []string
+	slides := pres.child("sldIdLst").all("sldId")
+	for _, ref := range slides {
+		rel, ok := rels[ref.attr("r:id")]
+		if !ok {
+			continue
+		}
+		text, err := l.slideText(zr, rel.Target, len(offsets)+1)
+		if err != nil {
+			return nil, err
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		offsets = append(offsets, strconv.Itoa(b.Len()))
+		b.WriteString(text)
+	}
+
+	meta := map[string]string{
+		MetaSlideCount:  strconv.Itoa(len(offsets)),
+		MetaPageOffsets: strings.Join(offsets, ","),
+	}
+	if err := coreProperties(zr, meta); err != nil {
+		return nil, err
+	}
+	return &LoadResult{
+		Content:  b.String(),
+		Metadata: meta,
+		MimeType: MimePPTX,
+	}, nil
+}
+
+// Supports returns true for PPTX.
+func (l *PPTXLoader) Supports(mimeType string) bool {
+	return mimeType == MimePPTX
+}
+
+// slideText renders one slide and its notes.
+func (l *PPTXLoader) slideText(zr *zipPackage, part string, number int) (string, error) {
+	slide, err := readPart(zr, part)
+	if err != nil {
+		return "", err
+	}
+
+	var title string
+	var blocks []string
+	for _, sp := range pptxShapes(slide.child("cSld").child("spTree")) {
+		if sp.Name == "graphicFrame" {
+			if tbl := sp.all("tbl"); len(tbl) > 0 {
+				blocks = append(blocks, markdownTable(pptxTableRows(tbl[0])))
+			}
+			continue
+		}
+		text := pptxText(sp.child("txBody"))
+		if text == "" {
+			continue
+		}
+		switch pptxPlaceholder(sp) {
+		case "title", "ctrTitle":
+			if title == "" {
+				title = strings.Join(strings.Fields(text), " ")
+				continue
+			}
+		case "sldNum", "dt", "ftr":
+			continue
+		}
+		blocks = append(blocks, text)
+	}
+
+	heading := "## Slide " + strconv.Itoa(number)
+	if title != "" {
+		heading += ": " + title
+	}
+	blocks = append([]string{heading}, blocks...)
+
+	if !l.SkipNotes {
+		rels, err := partRels(zr, part)
+		if err != nil {
+			return "", err
+		}
+		for _, rel := range rels {
+			if !strings.HasSuffix(rel.Type, "/notesSlide") {
+				continue
+			}
+			notes, err := readPart(zr, rel.Target)
+			if err != nil {
+				return "", err
+			}
+			var parts []string
+			for _, sp := range pptxShapes(notes.child("cSld").child("spTree")) {
+				if pptxPlaceholder(sp) != "body" {
+					continue
+				}
+				if text := pptxText(sp.child("txBody")); text != "" {
+					parts = append(parts, text)
+				}
+			}
+			if len(parts) > 0 {
+				blocks = append(blocks, "Notes: "+strings.Join(parts, "\n"))
+			}
+		}
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// pptxShapes returns the shapes and graphic frames of a shape tree in
+// drawing order, flattening groups.
+func pptxShapes(tree *xmlNode) []*xmlNode {
+	if tree == nil {
+		return nil
+	}
+	var shapes []*xmlNode
+	for _, c := range tree.Children {
+		switch c.Name {
+		case "sp", "graphicFrame":
+			shapes = append(shapes, c)
+		case "grpSp":
+			shapes = append(shapes, pptxShapes(c)...)
+		}
+	}
+	return shapes
+}
+
+// pptxPlaceholder returns the placeholder type of a shape, "body" for
+// untyped placeholders, or "" when the shape is not a placeholder.
+func pptxPlaceholder(sp *xmlNode) string {
+	ph := sp.child("nvSpPr").child("nvPr").child("ph")
+	if ph == nil {
+		return ""
+	}
+	if t := ph.attr("type"); t != "" {
+		return t
+	}
+	return "body"
+}
+
+// pptxText returns the paragraphs of a text body, one per line.
+func pptxText(body *xmlNode) string {
+	var lines []string
+	for _, p := range body.all("p") {
+		var b strings.Builder
+		for _, c := range p.Children {
+			switch c.Name {
+			case "r", "fld":
+				b.WriteString(c.childText("t"))
+			case "br":
+				b.WriteString("\n")
+			}
+		}
+		if line := strings.TrimSpace(b.String()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pptxTableRows returns the cell text of a DrawingML table.
+func pptxTableRows(tbl *xmlNode) [][]string {
+	var rows [][]string
+	for _, tr := range tbl.all("tr") {
+		var row []string
+		for _, tc := range tr.all("tc") {
+			row = append(row, pptxText(tc.child("txBody")))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,485 @@
+package loader
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Class and ID patterns used to weigh DOM blocks when looking for the main
+// content of a page, after Mozilla's Readability.
+var (
+	reUnlikely = regexp.MustCompile(`(?i)-ad-|ad-break|agegate|banner|breadcrumb|combx|comment|community|consent|cookie|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|newsletter|pager|pagination|popup|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental`)
+	reMaybe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	rePositive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	reNegative = regexp.MustCompile(`(?i)-ad-|banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|modal|nav|outbrain|popup|promo|related|scroll|share|shoutbox|sidebar|skyscraper|social|sponsor|shopping|tags|tool|widget`)
+)
+
+// mainContent returns the nodes that hold the main content of a page,
+// with boilerplate removed from the tree.
+func mainContent(doc *html.Node) []*html.Node {
+	body := findElement(doc, "body")
+	if body == nil {
+		body = doc
+	}
+	removeBoilerplate(body, false)
+
+	// A single <article> or <main> is taken at its word.
+	for _, tag := range []string{"article", "main"} {
+		if found := findAll(body, tag); len(found) == 1 && len(nodeText(found[0])) >= 200 {
+			cleanConditionally(found[0])
+			return []*html.Node{found[0]}
+		}
+	}
+
+	// Score paragraphs into their parents and grandparents.
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+	candidate := func(n *html.Node) {
+		if _, ok := scores[n]; !ok {
+			scores[n] = tagWeight(n) + classWeight(n)
+			order = append(order, n)
+		}
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch {
+			case c.Data == "p" || c.Data == "pre" || c.Data == "td" || c.Data == "blockquote" ||
+				(c.Data == "div" && !hasBlockChild(c)):
+				text := nodeText(c)
+				if len(text) < 25 || c.Parent == nil {
+					break
+				}
+				score := 1 + float64(strings.Count(text, ",")) + float64(min(len(text)/100, 3))
+				candidate(c.Parent)
+				scores[c.Parent] += score
+				if gp := c.Parent.Parent; gp != nil && gp.Type == html.ElementNode {
+					candidate(gp)
+					scores[gp] += score / 2
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(body)
+
+	var top *html.Node
+	best := 0.0
+	for _, n := range order {
+		scores[n] *= 1 - linkDensity(n)
+		if top == nil || scores[n] > best {
+			top, best = n, scores[n]
+		}
+	}
+	if top == nil {
+		return []*html.Node{body}
+	}
+
+	// Siblings that score well, or read like prose, belong to the content.
+	nodes := []*html.Node{top}
+	if parent := top.Parent; parent != nil {
+		nodes = nodes[:0]
+		threshold := max(10, best*0.2)
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			include := c == top
+			if s, ok := scores[c]; ok && s >= threshold {
+				include = true
+			}
+			if c.Data == "p" {
+				text := nodeText(c)
+				ld := linkDensity(c)
+				include = include || (len(text) > 80 && ld < 0.25) ||
+					(len(text) > 0 && ld == 0 && strings.ContainsAny(text, ".!?"))
+			}
+			if include {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	for _, n := range nodes {
+		cleanConditionally(n)
+	}
+
+	// Fall back to the whole body when the chosen content is a sliver.
+	length := 0
+	for _, n := range nodes {
+		length += len(nodeText(n))
+	}
+	if length < 250 && len(nodeText(body)) > 2*length {
+		return []*html.Node{body}
+	}
+	return nodes
+}
+
+// removeBoilerplate drops elements that never hold main content:
+// scripts, navigation, hidden elements, and blocks whose class or ID marks
+// them as banners, sidebars and the like. Headers and footers inside an
+// article are kept, since they carry its title and byline.
+func removeBoilerplate(n *html.Node, inArticle bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode {
+			n.RemoveChild(c)
+		} else if c.Type == html.ElementNode {
+			if isBoilerplate(c, inArticle) {
+				n.RemoveChild(c)
+			} else {
+				removeBoilerplate(c, inArticle || c.Data == "article" || c.Data == "main")
+			}
+		}
+		c = next
+	}
+}
+
+func isBoilerplate(n *html.Node, inArticle bool) bool {
+	switch n.Data {
+	case "script", "style", "noscript", "template", "svg", "canvas", "iframe", "object", "embed",
+		"form", "button", "input", "select", "textarea", "nav", "aside", "dialog":
+		return true
+	case "header", "footer":
+		if !inArticle {
+			return true
+		}
+	}
+	if _, hidden := attrValue(n, "hidden"); hidden || htmlAttr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if style := strings.ReplaceAll(htmlAttr(n, "style"), " ", ""); strings.Contains(style, "display:none") {
+		return true
+	}
+	switch htmlAttr(n, "role") {
+	case "navigation", "banner", "contentinfo", "complementary", "dialog", "alertdialog", "menu", "menubar":
+		return true
+	}
+	if n.Data == "body" || n.Data == "article" || n.Data == "main" || n.Data == "a" {
+		return false
+	}
+	match := htmlAttr(n, "class") + " " + htmlAttr(n, "id")
+	return reUnlikely.MatchString(match) && !reMaybe.MatchString(match)
+}
+
+// cleanConditionally removes link-dense and negatively weighted blocks
+// from within the main content.
+func cleanConditionally(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			switch c.Data {
+			case "div", "section", "ul", "ol", "table", "dl":
+				text := nodeText(c)
+				ld := linkDensity(c)
+				if classWeight(c) < 0 || ld > 0.5 || (ld > 0.3 && len(text) < 100) ||
+					(len(text) == 0 && findElement(c, "img") == nil) {
+					n.RemoveChild(c)
+					c = next
+					continue
+				}
+			}
+			cleanConditionally(c)
+		}
+		c = next
+	}
+}
+
+// tagWeight is the starting score of a candidate by element type.
+func tagWeight(n *html.Node) float64 {
+	switch n.Data {
+	case "div", "article", "main":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+// classWeight scores the class and ID of an element.
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, v := range []string{htmlAttr(n, "class"), htmlAttr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if reNegative.MatchString(v) {
+			weight -= 25
+		}
+		if rePositive.MatchString(v) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of an element's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len(nodeText(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	for _, a := range findAll(n, "a") {
+		linked += len(nodeText(a))
+	}
+	return float64(linked) / float64(total)
+}
+
+// blockTags are the elements rendered as blocks of their own.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true, "dd": true,
+	"details": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "ol": true, "p": true, "pre": true,
+	"section": true, "summary": true, "table": true, "ul": true,
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockTags[c.Data] {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownWriter renders HTML as Markdown-like text: headings, lists,
+// tables, code and quotes keep their structure, and inline markup is
+// reduced to its text.
+type markdownWriter struct {
+	blocks []string
+	inline strings.Builder
+}
+
+// renderMarkdown renders the nodes as Markdown-like text.
+func renderMarkdown(nodes []*html.Node) string {
+	w := &markdownWriter{}
+	for _, n := range nodes {
+		w.block(n)
+	}
+	w.flush()
+	return strings.Join(w.blocks, "\n\n")
+}
+
+// flush ends the current paragraph of inline content.
+func (w *markdownWriter) flush() {
+	text := collapseSpaces(w.inline.String())
+	w.inline.Reset()
+	if text != "" {
+		w.blocks = append(w.blocks, text)
+	}
+}
+
+func (w *markdownWriter) emit(text string) {
+	w.flush()
+	if text = strings.TrimSpace(text); text != "" {
+		w.blocks = append(w.blocks, text)
+	}
+}
+
+// block renders a node that may contain block-level children.
+func (w *markdownWriter) block(n *html.Node) {
+	if n.Type == html.TextNode {
+		w.inline.WriteString(n.Data)
+		return
+	}
+	if n.Type != html.ElementNode && n.Type != html.DocumentNode {
+		return
+	}
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(n.Data[1:])
+		if text := collapseSpaces(inlineText(n)); text != "" {
+			w.emit(strings.Repeat("#", level) + " " + text)
+		}
+	case "p":
+		w.emit(collapseSpaces(inlineText(n)))
+	case "pre":
+		w.emit("```\n" + strings.Trim(rawText(n), "\n") + "\n```")
+	case "ul", "ol":
+		w.emit(strings.Join(listLines(n, 0), "\n"))
+	case "table":
+		var rows [][]string
+		for _, tr := range findAll(n, "tr") {
+			var row []string
+			for c := tr.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+					row = append(row, collapseSpaces(inlineText(c)))
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+		}
+		if len(rows) > 0 {
+			w.emit(markdownTable(rows))
+		}
+	case "blockquote":
+		inner := renderMarkdown(children(n))
+		if inner != "" {
+			w.emit("> " + strings.ReplaceAll(inner, "\n", "\n> "))
+		}
+	case "hr", "img":
+		w.flush()
+	case "br":
+		w.inline.WriteString("\n")
+	default:
+		if !blockTags[n.Data] && n.Type == html.ElementNode {
+			w.inline.WriteString(inlineText(n))
+			return
+		}
+		w.flush()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.block(c)
+		}
+		w.flush()
+	}
+}
+
+// listLines renders a list, indenting nested lists by depth.
+func listLines(list *html.Node, depth int) []string {
+	var lines []string
+	number := 0
+	for li := list.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		number++
+		marker := "-"
+		if list.Data == "ol" {
+			marker = strconv.Itoa(number) + "."
+		}
+
+		var text strings.Builder
+		var nested []string
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.Data == "ul" || c.Data == "ol") {
+				nested = append(nested, listLines(c, depth+1)...)
+				continue
+			}
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			} else {
+				text.WriteString(" " + inlineText(c) + " ")
+			}
+		}
+		if item := collapseSpaces(text.String()); item != "" {
+			lines = append(lines, strings.Repeat("  ", depth)+marker+" "+item)
+		}
+		lines = append(lines, nested...)
+	}
+	return lines
+}
+
+// inlineText returns the text of an element, marking inline code with
+// backticks and keeping line breaks.
+func inlineText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case n.Type != html.ElementNode:
+			return
+		case n.Data == "br":
+			b.WriteString("\n")
+			return
+		case n.Data == "code" || n.Data == "kbd" || n.Data == "samp":
+			if code := strings.TrimSpace(rawText(n)); code != "" {
+				b.WriteString("`" + code + "`")
+			}
+			return
+		case blockTags[n.Data]:
+			b.WriteString(" ")
+			defer b.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// rawText returns the text under n exactly as written.
+func rawText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// collapseSpaces collapses runs of whitespace within each line and drops
+// blank lines.
+func collapseSpaces(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func children(n *html.Node) []*html.Node {
+	var out []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		out = append(out, c)
+	}
+	return out
+}
+
+// findElement returns the first element with the given tag under n.
+func findElement(n *html.Node, tag string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			return c
+		}
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAll returns every element with the given tag under n.
+func findAll(n *html.Node, tag string) []*html.Node {
+	var out []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			out = append(out, c)
+		}
+		out = append(out, findAll(c, tag)...)
+	}
+	return out
+}
+
+// attrValue returns an attribute and whether it is present.
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// robotsRules are the robots.txt rules that apply to one user agent.
+type robotsRules struct {
+	rules []robotsRule
+	// disallowAll is set when robots.txt could not be fetched because of a
+	// server error, which RFC 9309 treats as a full disallow.
+	disallowAll bool
+}
+
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// parseRobots reads the group of a robots.txt file that applies to agent,
+// falling back to the "*" group.
+func parseRobots(r io.Reader, agent string) (*robotsRules, error) {
+	agent = strings.ToLower(agent)
+	var own, star []robotsRule
+	var hasOwn bool
+	var groupAgents []string
+	inRules := false
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group.
+			if inRules {
+				groupAgents, inRules = nil, false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // An empty disallow allows everything.
+			}
+			rule := robotsRule{pattern: value, allow: key == "allow"}
+			for _, ua := range groupAgents {
+				switch {
+				case ua == "*":
+					star = append(star, rule)
+				case strings.Contains(agent, ua):
+					own = append(own, rule)
+					hasOwn = true
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if hasOwn {
+		return &robotsRules{rules: own}, nil
+	}
+	return &robotsRules{rules: star}, nil
+}
+
+// allowed reports whether the rules allow a URL. The longest matching
+// pattern wins, and allow wins a tie.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	if r == nil {
+		return true
+	}
+	if r.disallowAll {
+		return false
+	}
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, target) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			best, allow = n, rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a robots.txt path pattern, where "*" matches any
+// run of characters and a trailing "$" anchors the end.
+func robotsMatch(pattern, target string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(target, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for _, part := range parts[1:] {
+		i := strings.Index(target[pos:], part)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(part)
+	}
+	if !anchored {
+		return true
+	}
+	if len(parts) > 1 && parts[len(parts)-1] != "" {
+		return strings.HasSuffix(target, parts[len(parts)-1])
+	}
+	return pos == len(target) || len(parts) > 1
+}
@@ -1,56 +1,65 @@
 package loader
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
 )
 
-// URLLoader fetches content from a URL and delegates to an appropriate loader.
+var _ Loader = (*URLLoader)(nil)
+
+// URLLoader fetches content from a URL and loads it with the delegate
+// that supports the response's Content-Type. When the response has no
+// usable Content-Type, the type is guessed from the URL's extension.
 type URLLoader struct {
-	client   *http.Client
-	delegate Loader
+	client  *http.Client
+	loaders []Loader
+
+	// UserAgent is sent with every request when set.
+	UserAgent string
+	// MaxBodySize fails responses larger than this many bytes. Zero means
+	// no limit.
+	MaxBodySize int64
 }
 
-// NewURLLoader creates a URLLoader that uses the given loader for content extraction.
-func NewURLLoader(delegate Loader) *URLLoader {
+// NewURLLoader creates a URLLoader that uses the given loaders for content extraction.
+func NewURLLoader(loaders ...Loader) *URLLoader {
 	return &URLLoader{
-		client:   http.DefaultClient,
-		delegate: delegate,
+		client:  http.DefaultClient,
+		loaders: loaders,
 	}
 }
 
 // NewURLLoaderWithClient creates a URLLoader with a custom HTTP client.
-func NewURLLoaderWithClient(client *http.Client, delegate Loader) *URLLoader {
+func NewURLLoaderWithClient(client *http.Client, loaders ...Loader) *URLLoader {
 	return &URLLoader{
-		client:   client,
-		delegate: delegate,
+		client:  client,
+		loaders: loaders,
 	}
 }
 
-// Load fetches the URL and delegates content extraction.
-func (l *URLLoader) Load(_ context.Context, _ /* unused reader */ interface{ Read([]byte) (int, error) }) (*LoadResult, error) {
+// Load is not supported for URLLoader — use LoadURL instead.
+func (l *URLLoader) Load(_ context.Context, _ io.Reader) (*LoadResult, error) {
 	return nil, fmt.Errorf("weave: URLLoader.Load requires a URL; use LoadURL instead")
 }
 
 // LoadURL fetches a URL and extracts content using the delegate loader.
 func (l *URLLoader) LoadURL(ctx context.Context, url string) (*LoadResult, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("weave: url load: %w", err)
-	}
-
-	resp, err := l.client.Do(req)
+	page, err := l.fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("weave: url load: %w", err)
+		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("weave: url load: status %d", resp.StatusCode)
+	loader := pickLoader(l.loaders, page.mimeType)
+	if loader == nil {
+		return nil, fmt.Errorf("weave: url load: no loader for %s", page.mimeType)
 	}
 
-	result, err := l.delegate.Load(ctx, resp.Body)
+	result, err := loader.Load(ctx, bytes.NewReader(page.body))
 	if err != nil {
 		return nil, err
 	}
@@ -66,3 +75,57 @@ func (l *URLLoader) LoadURL(ctx context.Context, url string) (*LoadResult, error
 func (l *URLLoader) Supports(mimeType string) bool {
 	return mimeType == "text/uri-list"
 }
+
+// statusError is a response status other than 200 OK.
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("status %d", int(e)) }
+
+// fetchedPage is a successful response.
+type fetchedPage struct {
+	// url is the final URL, after redirects.
+	url      *url.URL
+	mimeType string
+	body     []byte
+}
+
+// fetch GETs a URL and reads the response body.
+func (l *URLLoader) fetch(ctx context.Context, rawURL string) (*fetchedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("weave: url load: %w", err)
+	}
+	if l.UserAgent != "" {
+		req.Header.Set("User-Agent", l.UserAgent)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("weave: url load: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weave: url load: %w", statusError(resp.StatusCode))
+	}
+
+	var body io.Reader = resp.Body
+	if l.MaxBodySize > 0 {
+		body = io.LimitReader(resp.Body, l.MaxBodySize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("weave: url load: %w", err)
+	}
+	if l.MaxBodySize > 0 && int64(len(data)) > l.MaxBodySize {
+		return nil, fmt.Errorf("weave: url load: body larger than %d bytes", l.MaxBodySize)
+	}
+
+	page := &fetchedPage{url: resp.Request.URL, body: data}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType != "application/octet-stream" {
+		page.mimeType = mediaType
+	} else {
+		page.mimeType = mimeFromExt(path.Ext(page.url.Path))
+	}
+	return page, nil
+}
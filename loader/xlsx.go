@@ -0,0 +1,277 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetaSheetCount is the number of sheets in a workbook. XLSXLoader also
+// records MetaPageOffsets with one entry per sheet, so chunks are
+// annotated with the sheet they come from.
+const MetaSheetCount = "sheet_count"
+
+// XLSXLoader extracts Excel workbooks sheet by sheet. Each sheet starts
+// with a "## <sheet name>" heading followed by its used range as a
+// Markdown table whose first row is the header. Cells show their cached
+// values; date-formatted numbers are written as ISO dates.
+type XLSXLoader struct{}
+
+// NewXLSXLoader creates a new XLSXLoader.
+func NewXLSXLoader() *XLSXLoader { return &XLSXLoader{} }
+
+// Load reads a .xlsx package and returns its text.
+func (l *XLSXLoader) Load(ctx context.Context, reader io.Reader) (*LoadResult, error) {
+	zr, err := openOffice(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	const workbook = "xl/workbook.xml"
+	wb, err := readPart(zr, workbook)
+	if err != nil {
+		return nil, err
+	}
+	if wb == nil {
+		return nil, errors.New("weave: xlsx load: missing workbook")
+	}
+	rels, err := partRels(zr, workbook)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxReader{}
+	if x.shared, err = xlsxSharedStrings(zr); err != nil {
+		return nil, err
+	}
+	if x.dateStyles, err = xlsxDateStyles(zr); err != nil {
+		return nil, err
+	}
+	x.epoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if v := wb.child("workbookPr").attr("date1904"); v == "1" || v == "true" {
+		x.epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var b strings.Builder
+	var offsets []string
+	for _, sheet := range wb.child("sheets").all("sheet") {
+		rel, ok := rels[sheet.attr("r:id")]
+		if !ok {
+			continue
+		}
+		ws, err := readPart(zr, rel.Target)
+		if err != nil {
+			return nil, err
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		offsets = append(offsets, strconv.Itoa(b.Len()))
+		b.WriteString("## " + sheet.attr("name"))
+		if table := markdownTable(x.rows(ws)); table != "" {
+			b.WriteString("\n\n" + table)
+		}
+	}
+
+	meta := map[string]string{
+		MetaSheetCount:  strconv.Itoa(len(offsets)),
+		MetaPageOffsets: strings.Join(offsets, ","),
+	}
+	if err := coreProperties(zr, meta); err != nil {
+		return nil, err
+	}
+	return &LoadResult{
+		Content:  b.String(),
+		Metadata: meta,
+		MimeType: MimeXLSX,
+	}, nil
+}
+
+// Supports returns true for XLSX.
+func (l *XLSXLoader) Supports(mimeType string) bool {
+	return mimeType == MimeXLSX
+}
+
+// xlsxReader converts worksheet cells to text.
+type xlsxReader struct {
+	shared     []string
+	dateStyles map[int]bool
+	epoch      time.Time
+}
+
+// rows returns the cell values of a worksheet as a grid, dropping empty
+// rows and the empty columns left of the used range.
+func (x *xlsxReader) rows(ws *xmlNode) [][]string {
+	var rows [][]string
+	width := 0
+	for _, row := range ws.child("sheetData").all("row") {
+		var values []string
+		for i, c := range row.all("c") {
+			col := i
+			if ref := c.attr("r"); ref != "" {
+				col = xlsxColumn(ref)
+			}
+			value := x.value(c)
+			if value == "" {
+				continue
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+			values[col] = value
+		}
+		if len(values) == 0 {
+			continue
+		}
+		width = max(width, len(values))
+		rows = append(rows, values)
+	}
+
+	// Drop leading columns that are empty in every row.
+	first := width
+	for _, row := range rows {
+		for i, v := range row {
+			if v != "" {
+				first = min(first, i)
+				break
+			}
+		}
+	}
+	for i := range rows {
+		rows[i] = rows[i][first:]
+	}
+	return rows
+}
+
+// value returns the display text of a cell.
+func (x *xlsxReader) value(c *xmlNode) string {
+	v := c.childText("v")
+	switch c.attr("t") {
+	case "s":
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n >= len(x.shared) {
+			return ""
+		}
+		return x.shared[n]
+	case "inlineStr":
+		return xlsxRichText(c.child("is"))
+	case "b":
+		if v == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e":
+		return v
+	}
+
+	style, _ := strconv.Atoi(c.attr("s"))
+	if !x.dateStyles[style] || v == "" {
+		return v
+	}
+	serial, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	days, frac := math.Modf(serial)
+	t := x.epoch.AddDate(0, 0, int(days)).Add(time.Duration(math.Round(frac*86400)) * time.Second)
+	switch {
+	case frac == 0:
+		return t.Format(time.DateOnly)
+	case days == 0:
+		return t.Format(time.TimeOnly)
+	default:
+		return t.Format(time.DateTime)
+	}
+}
+
+// xlsxColumn returns the zero-based column of a cell reference like "C7".
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	return col - 1
+}
+
+// xlsxRichText returns the text of a string item, skipping phonetic runs.
+func xlsxRichText(si *xmlNode) string {
+	if si == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range si.Children {
+		switch c.Name {
+		case "t":
+			b.WriteString(c.text())
+		case "r":
+			b.WriteString(c.childText("t"))
+		}
+	}
+	return b.String()
+}
+
+// xlsxSharedStrings reads the shared string table.
+func xlsxSharedStrings(zr *zipPackage) ([]string, error) {
+	root, err := readPart(zr, "xl/sharedStrings.xml")
+	if err != nil || root == nil {
+		return nil, err
+	}
+	items := root.all("si")
+	values := make([]string, len(items))
+	for i, si := range items {
+		values[i] = xlsxRichText(si)
+	}
+	return values, nil
+}
+
+// xlsxDateStyles returns the cell style indexes whose number format
+// displays a date or time.
+func xlsxDateStyles(zr *zipPackage) (map[int]bool, error) {
+	root, err := readPart(zr, "xl/styles.xml")
+	if err != nil || root == nil {
+		return nil, err
+	}
+	custom := make(map[string]bool)
+	for _, f := range root.child("numFmts").all("numFmt") {
+		custom[f.attr("numFmtId")] = isDateFormat(f.attr("formatCode"))
+	}
+	styles := make(map[int]bool)
+	for i, xf := range root.child("cellXfs").all("xf") {
+		id := xf.attr("numFmtId")
+		if date, ok := custom[id]; ok {
+			styles[i] = date
+			continue
+		}
+		// Built-in date and time formats.
+		n, _ := strconv.Atoi(id)
+		styles[i] = (n >= 14 && n <= 22) || (n >= 45 && n <= 47)
+	}
+	return styles, nil
+}
+
+// isDateFormat reports whether a custom number format shows a date or
+// time, ignoring quoted literals and colour or locale sections.
+func isDateFormat(code string) bool {
+	inQuote, inBracket := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case r == 'y' || r == 'd' || r == 'h' || r == 's':
+			return true
+		}
+	}
+	return false
+}
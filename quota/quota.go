@@ -0,0 +1,135 @@
+// Package quota defines per-tenant resource limits and usage.
+package quota
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xraph/weave"
+)
+
+// Resource identifies a quota-limited resource.
+type Resource string
+
+const (
+	ResourceCollections     Resource = "collections"
+	ResourceDocuments       Resource = "documents"
+	ResourceChunks          Resource = "chunks"
+	ResourceStoredBytes     Resource = "stored_bytes"
+	ResourceEmbeddingTokens Resource = "embedding_tokens"
+)
+
+// Periodic reports whether usage of the resource resets every period
+// rather than accumulating.
+func (r Resource) Periodic() bool { return r == ResourceEmbeddingTokens }
+
+// Limits holds the maximum amount of each resource a tenant may use.
+// A zero value means unlimited.
+type Limits struct {
+	MaxCollections             int64 `json:"max_collections" mapstructure:"max_collections" yaml:"max_collections"`
+	MaxDocuments               int64 `json:"max_documents" mapstructure:"max_documents" yaml:"max_documents"`
+	MaxChunks                  int64 `json:"max_chunks" mapstructure:"max_chunks" yaml:"max_chunks"`
+	MaxStoredBytes             int64 `json:"max_stored_bytes" mapstructure:"max_stored_bytes" yaml:"max_stored_bytes"`
+	MaxEmbeddingTokensPerMonth int64 `json:"max_embedding_tokens_per_month" mapstructure:"max_embedding_tokens_per_month" yaml:"max_embedding_tokens_per_month"`
+}
+
+// Limit returns the limit for the given resource.
+func (l Limits) Limit(r Resource) int64 {
+	switch r {
+	case ResourceCollections:
+		return l.MaxCollections
+	case ResourceDocuments:
+		return l.MaxDocuments
+	case ResourceChunks:
+		return l.MaxChunks
+	case ResourceStoredBytes:
+		return l.MaxStoredBytes
+	case ResourceEmbeddingTokens:
+		return l.MaxEmbeddingTokensPerMonth
+	default:
+		return 0
+	}
+}
+
+// Unlimited reports whether no resource is limited.
+func (l Limits) Unlimited() bool { return l == Limits{} }
+
+// Quota overrides the default limits for a single tenant.
+type Quota struct {
+	weave.Entity
+
+	TenantID string `json:"tenant_id" bun:"tenant_id,pk"`
+	Limits   Limits `json:"limits"`
+}
+
+// Usage is a tenant's current resource consumption. Collections,
+// documents, chunks, and stored bytes include trashed items until they
+// are purged; embedding tokens cover the current period only.
+type Usage struct {
+	TenantID        string `json:"tenant_id"`
+	Period          string `json:"period"`
+	Collections     int64  `json:"collections"`
+	Documents       int64  `json:"documents"`
+	Chunks          int64  `json:"chunks"`
+	StoredBytes     int64  `json:"stored_bytes"`
+	EmbeddingTokens int64  `json:"embedding_tokens"`
+}
+
+// Used returns the current usage of the given resource.
+func (u *Usage) Used(r Resource) int64 {
+	switch r {
+	case ResourceCollections:
+		return u.Collections
+	case ResourceDocuments:
+		return u.Documents
+	case ResourceChunks:
+		return u.Chunks
+	case ResourceStoredBytes:
+		return u.StoredBytes
+	case ResourceEmbeddingTokens:
+		return u.EmbeddingTokens
+	default:
+		return 0
+	}
+}
+
+// Period returns the monthly period key (e.g. "2024-03") containing t.
+func Period(t time.Time) string { return t.UTC().Format("2006-01") }
+
+// ExceededError is returned when an operation would take a tenant over
+// one of its limits. It matches weave.ErrQuotaExceeded with errors.Is.
+type ExceededError struct {
+	TenantID  string   `json:"tenant_id"`
+	Resource  Resource `json:"resource"`
+	Limit     int64    `json:"limit"`
+	Used      int64    `json:"used"`
+	Requested int64    `json:"requested"`
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("weave: quota exceeded for tenant %q: %s used %d + requested %d exceeds limit %d",
+		e.TenantID, e.Resource, e.Used, e.Requested, e.Limit)
+}
+
+// Unwrap returns weave.ErrQuotaExceeded.
+func (e *ExceededError) Unwrap() error { return weave.ErrQuotaExceeded }
+
+// Check returns an *ExceededError if requesting n more of the resource
+// would exceed limits, or nil otherwise.
+func Check(limits Limits, usage *Usage, r Resource, n int64) error {
+	limit := limits.Limit(r)
+	if limit <= 0 {
+		return nil
+	}
+	used := usage.Used(r)
+	if used+n <= limit {
+		return nil
+	}
+	return &ExceededError{
+		TenantID:  usage.TenantID,
+		Resource:  r,
+		Limit:     limit,
+		Used:      used,
+		Requested: n,
+	}
+}
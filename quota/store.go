@@ -0,0 +1,27 @@
+package quota
+
+import "context"
+
+// Store defines the persistence contract for tenant quotas and usage.
+type Store interface {
+	// GetQuota retrieves the quota override for a tenant.
+	// Returns weave.ErrQuotaNotFound if the tenant has none.
+	GetQuota(ctx context.Context, tenantID string) (*Quota, error)
+
+	// SetQuota creates or replaces the quota override for a tenant.
+	SetQuota(ctx context.Context, q *Quota) error
+
+	// DeleteQuota removes the quota override for a tenant.
+	DeleteQuota(ctx context.Context, tenantID string) error
+
+	// ListQuotas returns all tenant quota overrides.
+	ListQuotas(ctx context.Context) ([]*Quota, error)
+
+	// GetUsage computes a tenant's current resource usage, with embedding
+	// tokens counted for the given period.
+	GetUsage(ctx context.Context, tenantID, period string) (*Usage, error)
+
+	// AddEmbeddingTokens adds to a tenant's embedding token count for the
+	// given period.
+	AddEmbeddingTokens(ctx context.Context, tenantID, period string, tokens int64) error
+}
@@ -0,0 +1,110 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/xraph/weave"
+	"github.com/xraph/weave/chunk"
+	"github.com/xraph/weave/id"
+)
+
+// ParentRetriever implements small-to-big retrieval over chunks produced
+// by chunker.HierarchicalChunker. It searches the small child chunks with
+// the base retriever and returns their parents instead, deduplicated and
+// scored by their best-matching child. Results without a parent, or whose
+// parent is no longer in the store, pass through unchanged.
+type ParentRetriever struct {
+	base  Retriever
+	store chunk.Store
+}
+
+// NewParentRetriever creates a ParentRetriever that searches with base and
+// loads parent chunks from store.
+func NewParentRetriever(base Retriever, store chunk.Store) *ParentRetriever {
+	return &ParentRetriever{base: base, store: store}
+}
+
+// Retrieve searches child chunks and returns their parents.
+func (r *ParentRetriever) Retrieve(ctx context.Context, query string, opts *Options) ([]Result, error) {
+	// Fetch more children than requested since several may share a parent.
+	baseOpts := *opts
+	baseOpts.TopK = opts.TopK * 3
+	if baseOpts.TopK < 20 {
+		baseOpts.TopK = 20
+	}
+
+	children, err := r.base.Retrieve(ctx, query, &baseOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	seen := make(map[string]int) // parent ID -> index in results
+	for _, child := range children {
+		parentID := ""
+		if child.Chunk != nil {
+			parentID = child.Chunk.Metadata["parent_id"]
+		}
+		if parentID == "" {
+			results = append(results, child)
+			continue
+		}
+		if i, ok := seen[parentID]; ok {
+			if child.Score > results[i].Score {
+				results[i].Score = child.Score
+			}
+			continue
+		}
+
+		parent, err := r.loadParent(ctx, parentID, child.Chunk.Metadata)
+		if errors.Is(err, weave.ErrChunkNotFound) {
+			results = append(results, child)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		seen[parentID] = len(results)
+		results = append(results, Result{Chunk: parent, Score: child.Score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results, nil
+}
+
+// loadParent fetches a parent chunk. Its metadata starts from the child's
+// vector metadata, so collection, document and tenant keys used for
+// filtering carry over, overlaid with the parent's own ID, index and
+// metadata.
+func (r *ParentRetriever) loadParent(ctx context.Context, parentID string, childMeta map[string]string) (*chunk.Chunk, error) {
+	cid, err := id.ParseChunkID(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("weave: parent retrieve: %w", err)
+	}
+	stored, err := r.store.GetChunk(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("weave: parent retrieve: %w", err)
+	}
+
+	parent := *stored
+	parent.Metadata = make(map[string]string, len(childMeta)+len(stored.Metadata))
+	for k, v := range childMeta {
+		parent.Metadata[k] = v
+	}
+	delete(parent.Metadata, "parent_id")
+	delete(parent.Metadata, "variant")
+	parent.Metadata["chunk_id"] = stored.ID.String()
+	parent.Metadata["chunk_index"] = fmt.Sprintf("%d", stored.Index)
+	for k, v := range stored.Metadata {
+		parent.Metadata[k] = v
+	}
+	return &parent, nil
+}
@@ -0,0 +1,86 @@
+package retriever_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xraph/weave/chunk"
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/retriever"
+	"github.com/xraph/weave/store/memory"
+)
+
+// fixedRetriever returns its results for every query and records the
+// options it was called with.
+type fixedRetriever struct {
+	results []retriever.Result
+	opts    *retriever.Options
+}
+
+func (r *fixedRetriever) Retrieve(_ context.Context, _ string, opts *retriever.Options) ([]retriever.Result, error) {
+	r.opts = opts
+	return r.results, nil
+}
+
+func child(content, parentID string, score float64) retriever.Result {
+	meta := map[string]string{"collection_id": "col_1", "chunk_id": id.NewChunkID().String()}
+	if parentID != "" {
+		meta["parent_id"] = parentID
+	}
+	return retriever.Result{Chunk: &chunk.Chunk{Content: content, Metadata: meta}, Score: score}
+}
+
+func TestParentRetriever(t *testing.T) {
+	ctx := context.Background()
+	st := memory.New()
+	parents := []*chunk.Chunk{
+		{ID: id.NewChunkID(), Content: "parent one", Index: 0, Metadata: map[string]string{}},
+		{ID: id.NewChunkID(), Content: "parent two", Index: 1, Metadata: map[string]string{}},
+	}
+	if err := st.CreateChunkBatch(ctx, parents); err != nil {
+		t.Fatal(err)
+	}
+	one, two := parents[0].ID.String(), parents[1].ID.String()
+
+	base := &fixedRetriever{results: []retriever.Result{
+		child("one a", one, 0.6),
+		child("one b", one, 0.9),
+		child("no parent", "", 0.8),
+		child("missing parent", id.NewChunkID().String(), 0.7),
+		child("two a", two, 0.5),
+	}}
+	r := retriever.NewParentRetriever(base, st)
+
+	results, err := r.Retrieve(ctx, "query", &retriever.Options{TopK: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.opts.TopK < 3*3 {
+		t.Errorf("base TopK = %d, want an over-fetch", base.opts.TopK)
+	}
+
+	want := []struct {
+		content string
+		score   float64
+	}{
+		// The siblings collapse into their parent with the best score.
+		{"parent one", 0.9},
+		{"no parent", 0.8},
+		// A child whose parent is gone is returned itself.
+		{"missing parent", 0.7},
+		// "parent two" is trimmed by TopK.
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		if results[i].Chunk.Content != w.content || results[i].Score != w.score {
+			t.Errorf("result %d = %q (%v), want %q (%v)", i, results[i].Chunk.Content, results[i].Score, w.content, w.score)
+		}
+	}
+
+	meta := results[0].Chunk.Metadata
+	if meta["chunk_id"] != one || meta["collection_id"] != "col_1" || meta["parent_id"] != "" {
+		t.Errorf("parent metadata = %v", meta)
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
 	"github.com/xraph/weave/store"
+	"github.com/xraph/weave/usage"
 )
 
 // Compile-time interface check.
@@ -26,6 +28,9 @@ type Store struct {
 	collections map[string]*collection.Collection
 	documents   map[string]*document.Document
 	chunks      map[string]*chunk.Chunk
+	quotas      map[string]*quota.Quota
+	tokens      map[string]int64
+	usage       []*usage.Record
 }
 
 // New creates a new in-memory store.
@@ -34,6 +39,8 @@ func New() *Store {
 		collections: make(map[string]*collection.Collection),
 		documents:   make(map[string]*document.Document),
 		chunks:      make(map[string]*chunk.Chunk),
+		quotas:      make(map[string]*quota.Quota),
+		tokens:      make(map[string]int64),
 	}
 }
 
@@ -147,13 +154,20 @@ func (s *Store) ListCollections(_ context.Context, filter *collection.ListFilter
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	trashed := filter != nil && filter.Trashed
 	result := make([]*collection.Collection, 0, len(s.collections))
 	for _, col := range s.collections {
+		if col.Trashed() != trashed {
+			continue
+		}
 		if filter != nil && filter.Search != "" {
 			if !strings.Contains(strings.ToLower(col.Name), strings.ToLower(filter.Search)) {
 				continue
 			}
 		}
+		if filter != nil && filter.TenantID != "" && col.TenantID != filter.TenantID {
+			continue
+		}
 		result = append(result, col)
 	}
 
@@ -179,8 +193,12 @@ func (s *Store) CountCollections(_ context.Context, filter *collection.CountFilt
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	trashed := filter != nil && filter.Trashed
 	var count int64
 	for _, col := range s.collections {
+		if col.Trashed() != trashed {
+			continue
+		}
 		if filter != nil {
 			if filter.Search != "" && !strings.Contains(strings.ToLower(col.Name), strings.ToLower(filter.Search)) {
 				continue
@@ -271,18 +289,28 @@ func (s *Store) ListDocuments(_ context.Context, filter *document.ListFilter) ([
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	trashed := filter != nil && filter.Trashed
 	result := make([]*document.Document, 0, len(s.documents))
 	for _, doc := range s.documents {
+		if doc.Trashed() != trashed {
+			continue
+		}
 		if filter != nil {
 			if filter.CollectionID.String() != "" && doc.CollectionID.String() != filter.CollectionID.String() {
 				continue
 			}
+			if filter.TenantID != "" && doc.TenantID != filter.TenantID {
+				continue
+			}
 			if filter.State != "" && doc.State != filter.State {
 				continue
 			}
 			if filter.Search != "" && !strings.Contains(strings.ToLower(doc.Title), strings.ToLower(filter.Search)) {
 				continue
 			}
+			if !matchMetadata(doc.Metadata, filter.Metadata) {
+				continue
+			}
 		}
 		result = append(result, doc)
 	}
@@ -309,8 +337,12 @@ func (s *Store) CountDocuments(_ context.Context, filter *document.CountFilter)
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	trashed := filter != nil && filter.Trashed
 	var count int64
 	for _, doc := range s.documents {
+		if doc.Trashed() != trashed {
+			continue
+		}
 		if filter != nil {
 			if filter.CollectionID.String() != "" && doc.CollectionID.String() != filter.CollectionID.String() {
 				continue
@@ -438,3 +470,210 @@ func (s *Store) CountChunks(_ context.Context, filter *chunk.CountFilter) (int64
 	}
 	return count, nil
 }
+
+// ──────────────────────────────────────────────────
+// Quota operations
+// ──────────────────────────────────────────────────
+
+// GetQuota retrieves the quota override for a tenant.
+func (s *Store) GetQuota(_ context.Context, tenantID string) (*quota.Quota, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.quotas[tenantID]
+	if !ok {
+		return nil, weave.ErrQuotaNotFound
+	}
+	return q, nil
+}
+
+// SetQuota creates or replaces the quota override for a tenant.
+func (s *Store) SetQuota(_ context.Context, q *quota.Quota) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	if existing, ok := s.quotas[q.TenantID]; ok {
+		q.CreatedAt = existing.CreatedAt
+	} else {
+		q.CreatedAt = now
+	}
+	q.UpdatedAt = now
+	s.quotas[q.TenantID] = q
+	return nil
+}
+
+// DeleteQuota removes the quota override for a tenant.
+func (s *Store) DeleteQuota(_ context.Context, tenantID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.quotas[tenantID]; !ok {
+		return weave.ErrQuotaNotFound
+	}
+	delete(s.quotas, tenantID)
+	return nil
+}
+
+// ListQuotas returns all tenant quota overrides, ordered by tenant ID.
+func (s *Store) ListQuotas(_ context.Context) ([]*quota.Quota, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*quota.Quota, 0, len(s.quotas))
+	for _, q := range s.quotas {
+		result = append(result, q)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TenantID < result[j].TenantID
+	})
+	return result, nil
+}
+
+// GetUsage computes a tenant's current resource usage.
+func (s *Store) GetUsage(_ context.Context, tenantID, period string) (*quota.Usage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u := &quota.Usage{
+		TenantID:        tenantID,
+		Period:          period,
+		EmbeddingTokens: s.tokens[tenantID+"/"+period],
+	}
+	for _, col := range s.collections {
+		if col.TenantID == tenantID {
+			u.Collections++
+		}
+	}
+	for _, doc := range s.documents {
+		if doc.TenantID == tenantID {
+			u.Documents++
+			u.StoredBytes += int64(doc.ContentLength)
+		}
+	}
+	for _, ch := range s.chunks {
+		if ch.TenantID == tenantID {
+			u.Chunks++
+		}
+	}
+	return u, nil
+}
+
+// AddEmbeddingTokens adds to a tenant's embedding token count for a period.
+func (s *Store) AddEmbeddingTokens(_ context.Context, tenantID, period string, tokens int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[tenantID+"/"+period] += tokens
+	return nil
+}
+
+// ──────────────────────────────────────────────────
+// Usage operations
+// ──────────────────────────────────────────────────
+
+// RecordUsage appends a usage record.
+func (s *Store) RecordUsage(_ context.Context, r *usage.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	s.usage = append(s.usage, r)
+	return nil
+}
+
+// QueryUsage aggregates usage records matching the query.
+func (s *Store) QueryUsage(_ context.Context, q *usage.Query) ([]*usage.Summary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type key struct {
+		period, tenant, app, collection string
+		op                              usage.Operation
+		model                           string
+	}
+	groups := make(map[key]*usage.Summary)
+	for _, r := range s.usage {
+		if !matchesUsageQuery(r, q) {
+			continue
+		}
+		k := key{
+			period:     q.Granularity.Period(r.CreatedAt),
+			tenant:     r.TenantID,
+			app:        r.AppID,
+			collection: r.CollectionID.String(),
+			op:         r.Operation,
+			model:      r.Model,
+		}
+		sum, ok := groups[k]
+		if !ok {
+			sum = &usage.Summary{
+				Period:       k.period,
+				TenantID:     r.TenantID,
+				AppID:        r.AppID,
+				CollectionID: r.CollectionID,
+				Operation:    r.Operation,
+				Model:        r.Model,
+			}
+			groups[k] = sum
+		}
+		sum.Tokens += r.Tokens
+		sum.Requests++
+	}
+
+	result := make([]*usage.Summary, 0, len(groups))
+	for _, sum := range groups {
+		result = append(result, sum)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch {
+		case a.Period != b.Period:
+			return a.Period < b.Period
+		case a.TenantID != b.TenantID:
+			return a.TenantID < b.TenantID
+		case a.AppID != b.AppID:
+			return a.AppID < b.AppID
+		case a.CollectionID.String() != b.CollectionID.String():
+			return a.CollectionID.String() < b.CollectionID.String()
+		case a.Operation != b.Operation:
+			return a.Operation < b.Operation
+		default:
+			return a.Model < b.Model
+		}
+	})
+	return result, nil
+}
+
+func matchesUsageQuery(r *usage.Record, q *usage.Query) bool {
+	if q.TenantID != "" && r.TenantID != q.TenantID {
+		return false
+	}
+	if q.AppID != "" && r.AppID != q.AppID {
+		return false
+	}
+	if !q.CollectionID.IsNil() && r.CollectionID.String() != q.CollectionID.String() {
+		return false
+	}
+	if q.Operation != "" && r.Operation != q.Operation {
+		return false
+	}
+	if !q.From.IsZero() && r.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !r.CreatedAt.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+func matchMetadata(meta, filter map[string]string) bool {
+	for k, v := range filter {
+		if got, ok := meta[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
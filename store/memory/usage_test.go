@@ -0,0 +1,76 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/store/memory"
+	"github.com/xraph/weave/usage"
+)
+
+func TestQueryUsage(t *testing.T) {
+	ctx := context.Background()
+	st := memory.New()
+	docs, wiki := id.NewCollectionID(), id.NewCollectionID()
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	for _, r := range []*usage.Record{
+		{TenantID: "acme", AppID: "portal", CollectionID: docs, Operation: usage.OperationIngest, Model: "m", Tokens: 10, CreatedAt: day(1)},
+		{TenantID: "acme", AppID: "portal", CollectionID: docs, Operation: usage.OperationIngest, Model: "m", Tokens: 5, CreatedAt: day(1)},
+		{TenantID: "acme", AppID: "portal", CollectionID: docs, Operation: usage.OperationIngest, Model: "m", Tokens: 20, CreatedAt: day(2)},
+		{TenantID: "acme", AppID: "admin", CollectionID: wiki, Operation: usage.OperationQuery, Model: "m", Tokens: 3, CreatedAt: day(2)},
+		{TenantID: "acme", AppID: "portal", CollectionID: docs, Operation: usage.OperationIngest, Model: "m", Tokens: 40, CreatedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{TenantID: "globex", AppID: "portal", CollectionID: docs, Operation: usage.OperationIngest, Model: "m", Tokens: 100, CreatedAt: day(1)},
+	} {
+		r.ID = id.NewUsageID()
+		if err := st.RecordUsage(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query usage.Query
+		want  []string
+	}{
+		{"tenant by day", usage.Query{TenantID: "acme", Granularity: usage.GranularityDay}, []string{
+			"2024-03-01 portal ingest 15/2",
+			"2024-03-02 admin query 3/1",
+			"2024-03-02 portal ingest 20/1",
+			"2024-04-01 portal ingest 40/1",
+		}},
+		{"tenant by month", usage.Query{TenantID: "acme", Granularity: usage.GranularityMonth}, []string{
+			"2024-03 admin query 3/1",
+			"2024-03 portal ingest 35/3",
+			"2024-04 portal ingest 40/1",
+		}},
+		{"app", usage.Query{TenantID: "acme", AppID: "admin", Granularity: usage.GranularityMonth}, []string{
+			"2024-03 admin query 3/1",
+		}},
+		{"collection in range", usage.Query{
+			CollectionID: docs,
+			From:         day(1),
+			To:           day(3),
+			Granularity:  usage.GranularityMonth,
+		}, []string{
+			"2024-03 portal ingest 35/3",
+			"2024-03 portal ingest 100/1",
+		}},
+	}
+	for _, tt := range tests {
+		summaries, err := st.QueryUsage(ctx, &tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(summaries))
+		for i, s := range summaries {
+			got[i] = fmt.Sprintf("%s %s %s %d/%d", s.Period, s.AppID, s.Operation, s.Tokens, s.Requests)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
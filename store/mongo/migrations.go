@@ -141,5 +141,143 @@ func init() {
 				return mexec.DropCollection(ctx, (*chunkModel)(nil))
 			},
 		},
+		&migrate.Migration{
+			Name:    "add_weave_deleted_at",
+			Version: "20240101000003",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.RefreshValidator(ctx, (*collectionModel)(nil)); err != nil {
+					return err
+				}
+				if err := mexec.RefreshValidator(ctx, (*documentModel)(nil)); err != nil {
+					return err
+				}
+
+				if err := mexec.CreateIndexes(ctx, colCollections, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+						Options: options.Index().SetName("idx_weave_collections_deleted"),
+					},
+				}); err != nil {
+					return err
+				}
+				return mexec.CreateIndexes(ctx, colDocuments, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+						Options: options.Index().SetName("idx_weave_documents_deleted"),
+					},
+				})
+			},
+			Down: func(_ context.Context, _ migrate.Executor) error {
+				return nil
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_quotas",
+			Version: "20240101000004",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*quotaModel)(nil)); err != nil {
+					return err
+				}
+				if err := mexec.CreateCollection(ctx, (*tenantTokensModel)(nil)); err != nil {
+					return err
+				}
+
+				return mexec.CreateIndexes(ctx, colTokens, []mongo.IndexModel{
+					{
+						Keys: bson.D{
+							{Key: "tenant_id", Value: 1},
+							{Key: "period", Value: 1},
+						},
+						Options: options.Index().SetName("idx_weave_tenant_tokens_tenant_period"),
+					},
+				})
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				if err := mexec.DropCollection(ctx, (*tenantTokensModel)(nil)); err != nil {
+					return err
+				}
+				return mexec.DropCollection(ctx, (*quotaModel)(nil))
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_usage",
+			Version: "20240101000005",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*usageModel)(nil)); err != nil {
+					return err
+				}
+
+				return mexec.CreateIndexes(ctx, colUsage, []mongo.IndexModel{
+					{
+						Keys: bson.D{
+							{Key: "tenant_id", Value: 1},
+							{Key: "day", Value: 1},
+						},
+						Options: options.Index().SetName("idx_weave_usage_tenant_day"),
+					},
+					{
+						Keys: bson.D{
+							{Key: "collection_id", Value: 1},
+							{Key: "day", Value: 1},
+						},
+						Options: options.Index().SetName("idx_weave_usage_collection_day"),
+					},
+				})
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				return mexec.DropCollection(ctx, (*usageModel)(nil))
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_language",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				return mexec.RefreshValidator(ctx, (*collectionModel)(nil))
+			},
+			Down: func(_ context.Context, _ migrate.Executor) error {
+				return nil
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_embed_template",
+			Version: "20240101000007",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				return mexec.RefreshValidator(ctx, (*collectionModel)(nil))
+			},
+			Down: func(_ context.Context, _ migrate.Executor) error {
+				return nil
+			},
+		},
 	)
 }
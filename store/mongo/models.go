@@ -9,6 +9,8 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
 )
 
 // Collection model
@@ -26,11 +28,14 @@ type collectionModel struct {
 	ChunkStrategy  string            `grove:"chunk_strategy,notnull" bson:"chunk_strategy"`
 	ChunkSize      int               `grove:"chunk_size,notnull" bson:"chunk_size"`
 	ChunkOverlap   int               `grove:"chunk_overlap,notnull" bson:"chunk_overlap"`
+	Language       string            `grove:"language" bson:"language"`
+	EmbedTemplate  string            `grove:"embed_template" bson:"embed_template"`
 	Metadata       map[string]string `grove:"metadata" bson:"metadata"`
 	DocumentCount  int64             `grove:"document_count,notnull" bson:"document_count"`
 	ChunkCount     int64             `grove:"chunk_count,notnull" bson:"chunk_count"`
 	CreatedAt      time.Time         `grove:"created_at,notnull" bson:"created_at"`
 	UpdatedAt      time.Time         `grove:"updated_at,notnull" bson:"updated_at"`
+	DeletedAt      *time.Time        `grove:"deleted_at" bson:"deleted_at"`
 }
 
 func collectionToModel(c *collection.Collection) *collectionModel {
@@ -45,11 +50,14 @@ func collectionToModel(c *collection.Collection) *collectionModel {
 		ChunkStrategy:  c.ChunkStrategy,
 		ChunkSize:      c.ChunkSize,
 		ChunkOverlap:   c.ChunkOverlap,
+		Language:       c.Language,
+		EmbedTemplate:  c.EmbedTemplate,
 		Metadata:       c.Metadata,
 		DocumentCount:  c.DocumentCount,
 		ChunkCount:     c.ChunkCount,
 		CreatedAt:      c.CreatedAt,
 		UpdatedAt:      c.UpdatedAt,
+		DeletedAt:      c.DeletedAt,
 	}
 }
 
@@ -69,9 +77,12 @@ func collectionFromModel(m *collectionModel) (*collection.Collection, error) {
 		ChunkStrategy:  m.ChunkStrategy,
 		ChunkSize:      m.ChunkSize,
 		ChunkOverlap:   m.ChunkOverlap,
+		Language:       m.Language,
+		EmbedTemplate:  m.EmbedTemplate,
 		Metadata:       m.Metadata,
 		DocumentCount:  m.DocumentCount,
 		ChunkCount:     m.ChunkCount,
+		DeletedAt:      m.DeletedAt,
 	}, nil
 }
 
@@ -94,6 +105,7 @@ type documentModel struct {
 	Error         string            `grove:"error" bson:"error"`
 	CreatedAt     time.Time         `grove:"created_at,notnull" bson:"created_at"`
 	UpdatedAt     time.Time         `grove:"updated_at,notnull" bson:"updated_at"`
+	DeletedAt     *time.Time        `grove:"deleted_at" bson:"deleted_at"`
 }
 
 func documentToModel(d *document.Document) *documentModel {
@@ -112,6 +124,7 @@ func documentToModel(d *document.Document) *documentModel {
 		Error:         d.Error,
 		CreatedAt:     d.CreatedAt,
 		UpdatedAt:     d.UpdatedAt,
+		DeletedAt:     d.DeletedAt,
 	}
 }
 
@@ -137,6 +150,7 @@ func documentFromModel(m *documentModel) (*document.Document, error) {
 		Metadata:      m.Metadata,
 		State:         document.State(m.State),
 		Error:         m.Error,
+		DeletedAt:     m.DeletedAt,
 	}, nil
 }
 
@@ -204,3 +218,73 @@ func chunkFromModel(m *chunkModel) (*chunk.Chunk, error) {
 		CreatedAt:    m.CreatedAt,
 	}, nil
 }
+
+// Quota models
+
+type quotaModel struct {
+	grove.BaseModel `grove:"table:weave_quotas"`
+
+	TenantID                   string    `grove:"tenant_id,pk" bson:"_id"`
+	MaxCollections             int64     `grove:"max_collections,notnull" bson:"max_collections"`
+	MaxDocuments               int64     `grove:"max_documents,notnull" bson:"max_documents"`
+	MaxChunks                  int64     `grove:"max_chunks,notnull" bson:"max_chunks"`
+	MaxStoredBytes             int64     `grove:"max_stored_bytes,notnull" bson:"max_stored_bytes"`
+	MaxEmbeddingTokensPerMonth int64     `grove:"max_embedding_tokens_per_month,notnull" bson:"max_embedding_tokens_per_month"`
+	CreatedAt                  time.Time `grove:"created_at,notnull" bson:"created_at"`
+	UpdatedAt                  time.Time `grove:"updated_at,notnull" bson:"updated_at"`
+}
+
+func quotaFromModel(m *quotaModel) *quota.Quota {
+	q := &quota.Quota{
+		TenantID: m.TenantID,
+		Limits: quota.Limits{
+			MaxCollections:             m.MaxCollections,
+			MaxDocuments:               m.MaxDocuments,
+			MaxChunks:                  m.MaxChunks,
+			MaxStoredBytes:             m.MaxStoredBytes,
+			MaxEmbeddingTokensPerMonth: m.MaxEmbeddingTokensPerMonth,
+		},
+	}
+	q.CreatedAt = m.CreatedAt
+	q.UpdatedAt = m.UpdatedAt
+	return q
+}
+
+type tenantTokensModel struct {
+	grove.BaseModel `grove:"table:weave_tenant_tokens"`
+
+	ID       string `grove:"id,pk" bson:"_id"`
+	TenantID string `grove:"tenant_id,notnull" bson:"tenant_id"`
+	Period   string `grove:"period,notnull" bson:"period"`
+	Tokens   int64  `grove:"tokens,notnull" bson:"tokens"`
+}
+
+// Usage model
+
+type usageModel struct {
+	grove.BaseModel `grove:"table:weave_usage"`
+
+	ID           string    `grove:"id,pk" bson:"_id"`
+	TenantID     string    `grove:"tenant_id,notnull" bson:"tenant_id"`
+	AppID        string    `grove:"app_id,notnull" bson:"app_id"`
+	CollectionID string    `grove:"collection_id,notnull" bson:"collection_id"`
+	Operation    string    `grove:"operation,notnull" bson:"operation"`
+	Model        string    `grove:"model,notnull" bson:"model"`
+	Tokens       int64     `grove:"tokens,notnull" bson:"tokens"`
+	Day          string    `grove:"day,notnull" bson:"day"`
+	CreatedAt    time.Time `grove:"created_at,notnull" bson:"created_at"`
+}
+
+func usageToModel(r *usage.Record) *usageModel {
+	return &usageModel{
+		ID:           r.ID.String(),
+		TenantID:     r.TenantID,
+		AppID:        r.AppID,
+		CollectionID: r.CollectionID.String(),
+		Operation:    string(r.Operation),
+		Model:        r.Model,
+		Tokens:       r.Tokens,
+		Day:          usage.Day(r.CreatedAt),
+		CreatedAt:    r.CreatedAt,
+	}
+}
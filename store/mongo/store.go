@@ -20,13 +20,18 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
 	"github.com/xraph/weave/store"
+	"github.com/xraph/weave/usage"
 )
 
 const (
 	colCollections = "weave_collections"
 	colDocuments   = "weave_documents"
 	colChunks      = "weave_chunks"
+	colQuotas      = "weave_quotas"
+	colTokens      = "weave_tenant_tokens"
+	colUsage       = "weave_usage"
 )
 
 // Compile-time interface check.
@@ -142,12 +147,16 @@ func (s *Store) DeleteCollection(ctx context.Context, colID id.CollectionID) err
 
 func (s *Store) ListCollections(ctx context.Context, filter *collection.ListFilter) ([]*collection.Collection, error) {
 	var models []collectionModel
-	q := s.mdb.NewFind(&models).Sort(bson.D{{Key: "created_at", Value: 1}})
+	q := s.mdb.NewFind(&models).Sort(bson.D{{Key: "created_at", Value: 1}}).
+		Filter(deletedFilter(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.Search != "" {
 			q = q.Filter(bson.M{"name": bson.M{"$regex": filter.Search, "$options": "i"}})
 		}
+		if filter.TenantID != "" {
+			q = q.Filter(bson.M{"tenant_id": filter.TenantID})
+		}
 		if filter.Limit > 0 {
 			q = q.Limit(int64(filter.Limit))
 		}
@@ -172,7 +181,8 @@ func (s *Store) ListCollections(ctx context.Context, filter *collection.ListFilt
 }
 
 func (s *Store) CountCollections(ctx context.Context, filter *collection.CountFilter) (int64, error) {
-	q := s.mdb.NewFind((*collectionModel)(nil))
+	q := s.mdb.NewFind((*collectionModel)(nil)).
+		Filter(deletedFilter(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.Search != "" {
@@ -245,18 +255,25 @@ func (s *Store) DeleteDocument(ctx context.Context, docID id.DocumentID) error {
 
 func (s *Store) ListDocuments(ctx context.Context, filter *document.ListFilter) ([]*document.Document, error) {
 	var models []documentModel
-	q := s.mdb.NewFind(&models).Sort(bson.D{{Key: "created_at", Value: 1}})
+	q := s.mdb.NewFind(&models).Sort(bson.D{{Key: "created_at", Value: 1}}).
+		Filter(deletedFilter(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.CollectionID.String() != "" {
 			q = q.Filter(bson.M{"collection_id": filter.CollectionID.String()})
 		}
+		if filter.TenantID != "" {
+			q = q.Filter(bson.M{"tenant_id": filter.TenantID})
+		}
 		if filter.State != "" {
 			q = q.Filter(bson.M{"state": string(filter.State)})
 		}
 		if filter.Search != "" {
 			q = q.Filter(bson.M{"title": bson.M{"$regex": filter.Search, "$options": "i"}})
 		}
+		for key, value := range filter.Metadata {
+			q = q.Filter(bson.M{"metadata." + key: value})
+		}
 		if filter.Limit > 0 {
 			q = q.Limit(int64(filter.Limit))
 		}
@@ -281,7 +298,8 @@ func (s *Store) ListDocuments(ctx context.Context, filter *document.ListFilter)
 }
 
 func (s *Store) CountDocuments(ctx context.Context, filter *document.CountFilter) (int64, error) {
-	q := s.mdb.NewFind((*documentModel)(nil))
+	q := s.mdb.NewFind((*documentModel)(nil)).
+		Filter(deletedFilter(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.CollectionID.String() != "" {
@@ -407,6 +425,246 @@ func (s *Store) CountChunks(ctx context.Context, filter *chunk.CountFilter) (int
 	return count, nil
 }
 
+// ──────────────────────────────────────────────────
+// Quota operations
+// ──────────────────────────────────────────────────
+
+func (s *Store) GetQuota(ctx context.Context, tenantID string) (*quota.Quota, error) {
+	var m quotaModel
+	err := s.mdb.NewFind(&m).Filter(bson.M{"_id": tenantID}).Scan(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, weave.ErrQuotaNotFound
+		}
+		return nil, fmt.Errorf("weave: get quota: %w", err)
+	}
+	return quotaFromModel(&m), nil
+}
+
+func (s *Store) SetQuota(ctx context.Context, q *quota.Quota) error {
+	now := time.Now().UTC()
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = now
+	}
+	q.UpdatedAt = now
+
+	_, err := s.mdb.NewUpdate((*quotaModel)(nil)).
+		Filter(bson.M{"_id": q.TenantID}).
+		SetUpdate(bson.M{
+			"$set": bson.M{
+				"max_collections":                q.Limits.MaxCollections,
+				"max_documents":                  q.Limits.MaxDocuments,
+				"max_chunks":                     q.Limits.MaxChunks,
+				"max_stored_bytes":               q.Limits.MaxStoredBytes,
+				"max_embedding_tokens_per_month": q.Limits.MaxEmbeddingTokensPerMonth,
+				"updated_at":                     q.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{"created_at": q.CreatedAt},
+		}).
+		Upsert().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: set quota: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteQuota(ctx context.Context, tenantID string) error {
+	res, err := s.mdb.NewDelete((*quotaModel)(nil)).
+		Filter(bson.M{"_id": tenantID}).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: delete quota: %w", err)
+	}
+	if n := res.DeletedCount(); n == 0 {
+		return weave.ErrQuotaNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListQuotas(ctx context.Context) ([]*quota.Quota, error) {
+	var models []quotaModel
+	err := s.mdb.NewFind(&models).
+		Sort(bson.D{{Key: "_id", Value: 1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("weave: list quotas: %w", err)
+	}
+
+	result := make([]*quota.Quota, len(models))
+	for i := range models {
+		result[i] = quotaFromModel(&models[i])
+	}
+	return result, nil
+}
+
+func (s *Store) GetUsage(ctx context.Context, tenantID, period string) (*quota.Usage, error) {
+	u := &quota.Usage{TenantID: tenantID, Period: period}
+	tenant := bson.M{"tenant_id": tenantID}
+
+	var err error
+	if u.Collections, err = s.mdb.NewFind((*collectionModel)(nil)).Filter(tenant).Count(ctx); err != nil {
+		return nil, fmt.Errorf("weave: count collections for usage: %w", err)
+	}
+	if u.Documents, err = s.mdb.NewFind((*documentModel)(nil)).Filter(tenant).Count(ctx); err != nil {
+		return nil, fmt.Errorf("weave: count documents for usage: %w", err)
+	}
+	if u.Chunks, err = s.mdb.NewFind((*chunkModel)(nil)).Filter(tenant).Count(ctx); err != nil {
+		return nil, fmt.Errorf("weave: count chunks for usage: %w", err)
+	}
+
+	var sums []struct {
+		Total int64 `bson:"total"`
+	}
+	err = s.mdb.NewAggregate(colDocuments).
+		Match(tenant).
+		Group(bson.M{"_id": nil, "total": bson.M{"$sum": "$content_length"}}).
+		Scan(ctx, &sums)
+	if err != nil {
+		return nil, fmt.Errorf("weave: sum stored bytes for usage: %w", err)
+	}
+	if len(sums) > 0 {
+		u.StoredBytes = sums[0].Total
+	}
+
+	var tokens tenantTokensModel
+	err = s.mdb.NewFind(&tokens).Filter(bson.M{"_id": tenantID + "/" + period}).Scan(ctx)
+	switch {
+	case err == nil:
+		u.EmbeddingTokens = tokens.Tokens
+	case !isNotFound(err):
+		return nil, fmt.Errorf("weave: get embedding tokens for usage: %w", err)
+	}
+	return u, nil
+}
+
+func (s *Store) AddEmbeddingTokens(ctx context.Context, tenantID, period string, tokens int64) error {
+	_, err := s.mdb.NewUpdate((*tenantTokensModel)(nil)).
+		Filter(bson.M{"_id": tenantID + "/" + period}).
+		SetUpdate(bson.M{
+			"$inc":         bson.M{"tokens": tokens},
+			"$setOnInsert": bson.M{"tenant_id": tenantID, "period": period},
+		}).
+		Upsert().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: add embedding tokens: %w", err)
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────
+// Usage operations
+// ──────────────────────────────────────────────────
+
+func (s *Store) RecordUsage(ctx context.Context, r *usage.Record) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	m := usageToModel(r)
+	if _, err := s.mdb.NewInsert(m).Exec(ctx); err != nil {
+		return fmt.Errorf("weave: record usage: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) QueryUsage(ctx context.Context, q *usage.Query) ([]*usage.Summary, error) {
+	match := bson.M{}
+	if q.TenantID != "" {
+		match["tenant_id"] = q.TenantID
+	}
+	if q.AppID != "" {
+		match["app_id"] = q.AppID
+	}
+	if !q.CollectionID.IsNil() {
+		match["collection_id"] = q.CollectionID.String()
+	}
+	if q.Operation != "" {
+		match["operation"] = string(q.Operation)
+	}
+	created := bson.M{}
+	if !q.From.IsZero() {
+		created["$gte"] = q.From.UTC()
+	}
+	if !q.To.IsZero() {
+		created["$lt"] = q.To.UTC()
+	}
+	if len(created) > 0 {
+		match["created_at"] = created
+	}
+
+	var period any = "$day"
+	if q.Granularity == usage.GranularityMonth {
+		period = bson.M{"$substrBytes": bson.A{"$day", 0, 7}}
+	}
+
+	var rows []struct {
+		Key struct {
+			Period       string `bson:"period"`
+			TenantID     string `bson:"tenant_id"`
+			AppID        string `bson:"app_id"`
+			CollectionID string `bson:"collection_id"`
+			Operation    string `bson:"operation"`
+			Model        string `bson:"model"`
+		} `bson:"_id"`
+		Tokens   int64 `bson:"tokens"`
+		Requests int64 `bson:"requests"`
+	}
+	err := s.mdb.NewAggregate(colUsage).
+		Match(match).
+		Group(bson.M{
+			"_id": bson.M{
+				"period":        period,
+				"tenant_id":     "$tenant_id",
+				"app_id":        "$app_id",
+				"collection_id": "$collection_id",
+				"operation":     "$operation",
+				"model":         "$model",
+			},
+			"tokens":   bson.M{"$sum": "$tokens"},
+			"requests": bson.M{"$sum": 1},
+		}).
+		Sort(bson.D{
+			{Key: "_id.period", Value: 1},
+			{Key: "_id.tenant_id", Value: 1},
+			{Key: "_id.app_id", Value: 1},
+			{Key: "_id.collection_id", Value: 1},
+			{Key: "_id.operation", Value: 1},
+			{Key: "_id.model", Value: 1},
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("weave: query usage: %w", err)
+	}
+
+	result := make([]*usage.Summary, len(rows))
+	for i, r := range rows {
+		sum := &usage.Summary{
+			Period:    r.Key.Period,
+			TenantID:  r.Key.TenantID,
+			AppID:     r.Key.AppID,
+			Operation: usage.Operation(r.Key.Operation),
+			Model:     r.Key.Model,
+			Tokens:    r.Tokens,
+			Requests:  r.Requests,
+		}
+		if r.Key.CollectionID != "" {
+			sum.CollectionID, _ = id.ParseCollectionID(r.Key.CollectionID) //nolint:errcheck // DB rows always contain valid IDs
+		}
+		result[i] = sum
+	}
+	return result, nil
+}
+
+// deletedFilter returns the filter selecting live documents, or
+// soft-deleted documents when trashed is true.
+func deletedFilter(trashed bool) bson.M {
+	if trashed {
+		return bson.M{"deleted_at": bson.M{"$ne": nil}}
+	}
+	return bson.M{"deleted_at": nil}
+}
+
 // isNotFound checks whether an error indicates no documents were found.
 func isNotFound(err error) bool {
 	return errors.Is(err, mongo.ErrNoDocuments) ||
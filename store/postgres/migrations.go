@@ -110,5 +110,113 @@ CREATE INDEX IF NOT EXISTS idx_weave_chunks_tenant ON weave_chunks (tenant_id);
 				return err
 			},
 		},
+		&migrate.Migration{
+			Name:    "add_weave_deleted_at",
+			Version: "20240101000003",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE weave_documents ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_weave_collections_deleted ON weave_collections (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_weave_documents_deleted ON weave_documents (deleted_at) WHERE deleted_at IS NOT NULL;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP INDEX IF EXISTS idx_weave_documents_deleted;
+DROP INDEX IF EXISTS idx_weave_collections_deleted;
+ALTER TABLE weave_documents DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE weave_collections DROP COLUMN IF EXISTS deleted_at;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_quotas",
+			Version: "20240101000004",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS weave_quotas (
+    tenant_id                       TEXT PRIMARY KEY,
+    max_collections                 BIGINT NOT NULL DEFAULT 0,
+    max_documents                   BIGINT NOT NULL DEFAULT 0,
+    max_chunks                      BIGINT NOT NULL DEFAULT 0,
+    max_stored_bytes                BIGINT NOT NULL DEFAULT 0,
+    max_embedding_tokens_per_month  BIGINT NOT NULL DEFAULT 0,
+    created_at                      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS weave_tenant_tokens (
+    tenant_id   TEXT NOT NULL,
+    period      TEXT NOT NULL,
+    tokens      BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (tenant_id, period)
+);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS weave_tenant_tokens;
+DROP TABLE IF EXISTS weave_quotas;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_usage",
+			Version: "20240101000005",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS weave_usage (
+    id              TEXT PRIMARY KEY,
+    tenant_id       TEXT NOT NULL,
+    app_id          TEXT NOT NULL DEFAULT '',
+    collection_id   TEXT NOT NULL DEFAULT '',
+    operation       TEXT NOT NULL,
+    model           TEXT NOT NULL DEFAULT '',
+    tokens          BIGINT NOT NULL DEFAULT 0,
+    day             TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_weave_usage_tenant_day ON weave_usage (tenant_id, day);
+CREATE INDEX IF NOT EXISTS idx_weave_usage_collection_day ON weave_usage (collection_id, day);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `DROP TABLE IF EXISTS weave_usage`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_language",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections DROP COLUMN IF EXISTS language`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_embed_template",
+			Version: "20240101000007",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS embed_template TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections DROP COLUMN IF EXISTS embed_template`)
+				return err
+			},
+		},
 	)
 }
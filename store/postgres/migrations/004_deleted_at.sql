@@ -0,0 +1,5 @@
+ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE weave_documents ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_weave_collections_deleted ON weave_collections (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_weave_documents_deleted ON weave_documents (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS weave_quotas (
+    tenant_id                       TEXT PRIMARY KEY,
+    max_collections                 BIGINT NOT NULL DEFAULT 0,
+    max_documents                   BIGINT NOT NULL DEFAULT 0,
+    max_chunks                      BIGINT NOT NULL DEFAULT 0,
+    max_stored_bytes                BIGINT NOT NULL DEFAULT 0,
+    max_embedding_tokens_per_month  BIGINT NOT NULL DEFAULT 0,
+    created_at                      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS weave_tenant_tokens (
+    tenant_id   TEXT NOT NULL,
+    period      TEXT NOT NULL,
+    tokens      BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (tenant_id, period)
+);
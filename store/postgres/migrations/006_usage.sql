@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS weave_usage (
+    id              TEXT PRIMARY KEY,
+    tenant_id       TEXT NOT NULL,
+    app_id          TEXT NOT NULL DEFAULT '',
+    collection_id   TEXT NOT NULL DEFAULT '',
+    operation       TEXT NOT NULL,
+    model           TEXT NOT NULL DEFAULT '',
+    tokens          BIGINT NOT NULL DEFAULT 0,
+    day             TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_weave_usage_tenant_day ON weave_usage (tenant_id, day);
+CREATE INDEX IF NOT EXISTS idx_weave_usage_collection_day ON weave_usage (collection_id, day);
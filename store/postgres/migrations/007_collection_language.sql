@@ -0,0 +1 @@
+ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE weave_collections ADD COLUMN IF NOT EXISTS embed_template TEXT NOT NULL DEFAULT '';
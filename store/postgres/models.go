@@ -9,6 +9,8 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
 )
 
 // ──────────────────────────────────────────────────
@@ -28,11 +30,14 @@ type collectionModel struct {
 	ChunkStrategy  string            `grove:"chunk_strategy,notnull"`
 	ChunkSize      int               `grove:"chunk_size,notnull"`
 	ChunkOverlap   int               `grove:"chunk_overlap,notnull"`
+	Language       string            `grove:"language"`
+	EmbedTemplate  string            `grove:"embed_template"`
 	Metadata       map[string]string `grove:"metadata,type:jsonb"`
 	DocumentCount  int64             `grove:"document_count,notnull"`
 	ChunkCount     int64             `grove:"chunk_count,notnull"`
 	CreatedAt      time.Time         `grove:"created_at,notnull"`
 	UpdatedAt      time.Time         `grove:"updated_at,notnull"`
+	DeletedAt      *time.Time        `grove:"deleted_at"`
 }
 
 func collectionToModel(c *collection.Collection) *collectionModel {
@@ -47,11 +52,14 @@ func collectionToModel(c *collection.Collection) *collectionModel {
 		ChunkStrategy:  c.ChunkStrategy,
 		ChunkSize:      c.ChunkSize,
 		ChunkOverlap:   c.ChunkOverlap,
+		Language:       c.Language,
+		EmbedTemplate:  c.EmbedTemplate,
 		Metadata:       c.Metadata,
 		DocumentCount:  c.DocumentCount,
 		ChunkCount:     c.ChunkCount,
 		CreatedAt:      c.CreatedAt,
 		UpdatedAt:      c.UpdatedAt,
+		DeletedAt:      c.DeletedAt,
 	}
 }
 
@@ -68,9 +76,12 @@ func collectionFromModel(m *collectionModel) *collection.Collection {
 		ChunkStrategy:  m.ChunkStrategy,
 		ChunkSize:      m.ChunkSize,
 		ChunkOverlap:   m.ChunkOverlap,
+		Language:       m.Language,
+		EmbedTemplate:  m.EmbedTemplate,
 		Metadata:       m.Metadata,
 		DocumentCount:  m.DocumentCount,
 		ChunkCount:     m.ChunkCount,
+		DeletedAt:      m.DeletedAt,
 	}
 }
 
@@ -95,6 +106,7 @@ type documentModel struct {
 	Error         string            `grove:"error"`
 	CreatedAt     time.Time         `grove:"created_at,notnull"`
 	UpdatedAt     time.Time         `grove:"updated_at,notnull"`
+	DeletedAt     *time.Time        `grove:"deleted_at"`
 }
 
 func documentToModel(d *document.Document) *documentModel {
@@ -113,6 +125,7 @@ func documentToModel(d *document.Document) *documentModel {
 		Error:         d.Error,
 		CreatedAt:     d.CreatedAt,
 		UpdatedAt:     d.UpdatedAt,
+		DeletedAt:     d.DeletedAt,
 	}
 }
 
@@ -132,6 +145,7 @@ func documentFromModel(m *documentModel) *document.Document {
 		Metadata:      m.Metadata,
 		State:         document.State(m.State),
 		Error:         m.Error,
+		DeletedAt:     m.DeletedAt,
 	}
 }
 
@@ -192,3 +206,89 @@ func chunkFromModel(m *chunkModel) *chunk.Chunk {
 		CreatedAt:    m.CreatedAt,
 	}
 }
+
+// ──────────────────────────────────────────────────
+// Quota models
+// ──────────────────────────────────────────────────
+
+type quotaModel struct {
+	grove.BaseModel `grove:"table:weave_quotas"`
+
+	TenantID                   string    `grove:"tenant_id,pk"`
+	MaxCollections             int64     `grove:"max_collections,notnull"`
+	MaxDocuments               int64     `grove:"max_documents,notnull"`
+	MaxChunks                  int64     `grove:"max_chunks,notnull"`
+	MaxStoredBytes             int64     `grove:"max_stored_bytes,notnull"`
+	MaxEmbeddingTokensPerMonth int64     `grove:"max_embedding_tokens_per_month,notnull"`
+	CreatedAt                  time.Time `grove:"created_at,notnull"`
+	UpdatedAt                  time.Time `grove:"updated_at,notnull"`
+}
+
+func quotaToModel(q *quota.Quota) *quotaModel {
+	return &quotaModel{
+		TenantID:                   q.TenantID,
+		MaxCollections:             q.Limits.MaxCollections,
+		MaxDocuments:               q.Limits.MaxDocuments,
+		MaxChunks:                  q.Limits.MaxChunks,
+		MaxStoredBytes:             q.Limits.MaxStoredBytes,
+		MaxEmbeddingTokensPerMonth: q.Limits.MaxEmbeddingTokensPerMonth,
+		CreatedAt:                  q.CreatedAt,
+		UpdatedAt:                  q.UpdatedAt,
+	}
+}
+
+func quotaFromModel(m *quotaModel) *quota.Quota {
+	q := &quota.Quota{
+		TenantID: m.TenantID,
+		Limits: quota.Limits{
+			MaxCollections:             m.MaxCollections,
+			MaxDocuments:               m.MaxDocuments,
+			MaxChunks:                  m.MaxChunks,
+			MaxStoredBytes:             m.MaxStoredBytes,
+			MaxEmbeddingTokensPerMonth: m.MaxEmbeddingTokensPerMonth,
+		},
+	}
+	q.CreatedAt = m.CreatedAt
+	q.UpdatedAt = m.UpdatedAt
+	return q
+}
+
+type tenantTokensModel struct {
+	grove.BaseModel `grove:"table:weave_tenant_tokens"`
+
+	TenantID string `grove:"tenant_id,pk"`
+	Period   string `grove:"period,pk"`
+	Tokens   int64  `grove:"tokens,notnull"`
+}
+
+// ──────────────────────────────────────────────────
+// Usage model
+// ──────────────────────────────────────────────────
+
+type usageModel struct {
+	grove.BaseModel `grove:"table:weave_usage"`
+
+	ID           string    `grove:"id,pk"`
+	TenantID     string    `grove:"tenant_id,notnull"`
+	AppID        string    `grove:"app_id,notnull"`
+	CollectionID string    `grove:"collection_id,notnull"`
+	Operation    string    `grove:"operation,notnull"`
+	Model        string    `grove:"model,notnull"`
+	Tokens       int64     `grove:"tokens,notnull"`
+	Day          string    `grove:"day,notnull"`
+	CreatedAt    time.Time `grove:"created_at,notnull"`
+}
+
+func usageToModel(r *usage.Record) *usageModel {
+	return &usageModel{
+		ID:           r.ID.String(),
+		TenantID:     r.TenantID,
+		AppID:        r.AppID,
+		CollectionID: r.CollectionID.String(),
+		Operation:    string(r.Operation),
+		Model:        r.Model,
+		Tokens:       r.Tokens,
+		Day:          usage.Day(r.CreatedAt),
+		CreatedAt:    r.CreatedAt,
+	}
+}
@@ -110,5 +110,113 @@ CREATE INDEX IF NOT EXISTS idx_weave_chunks_tenant ON weave_chunks (tenant_id);
 				return err
 			},
 		},
+		&migrate.Migration{
+			Name:    "add_weave_deleted_at",
+			Version: "20240101000003",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE weave_collections ADD COLUMN deleted_at TEXT;
+ALTER TABLE weave_documents ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_weave_collections_deleted ON weave_collections (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_weave_documents_deleted ON weave_documents (deleted_at);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP INDEX IF EXISTS idx_weave_documents_deleted;
+DROP INDEX IF EXISTS idx_weave_collections_deleted;
+ALTER TABLE weave_documents DROP COLUMN deleted_at;
+ALTER TABLE weave_collections DROP COLUMN deleted_at;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_quotas",
+			Version: "20240101000004",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS weave_quotas (
+    tenant_id                       TEXT PRIMARY KEY,
+    max_collections                 INTEGER NOT NULL DEFAULT 0,
+    max_documents                   INTEGER NOT NULL DEFAULT 0,
+    max_chunks                      INTEGER NOT NULL DEFAULT 0,
+    max_stored_bytes                INTEGER NOT NULL DEFAULT 0,
+    max_embedding_tokens_per_month  INTEGER NOT NULL DEFAULT 0,
+    created_at                      TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at                      TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE IF NOT EXISTS weave_tenant_tokens (
+    tenant_id   TEXT NOT NULL,
+    period      TEXT NOT NULL,
+    tokens      INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (tenant_id, period)
+);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS weave_tenant_tokens;
+DROP TABLE IF EXISTS weave_quotas;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_weave_usage",
+			Version: "20240101000005",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS weave_usage (
+    id              TEXT PRIMARY KEY,
+    tenant_id       TEXT NOT NULL,
+    app_id          TEXT NOT NULL DEFAULT '',
+    collection_id   TEXT NOT NULL DEFAULT '',
+    operation       TEXT NOT NULL,
+    model           TEXT NOT NULL DEFAULT '',
+    tokens          INTEGER NOT NULL DEFAULT 0,
+    day             TEXT NOT NULL,
+    created_at      TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_weave_usage_tenant_day ON weave_usage (tenant_id, day);
+CREATE INDEX IF NOT EXISTS idx_weave_usage_collection_day ON weave_usage (collection_id, day);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `DROP TABLE IF EXISTS weave_usage`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_language",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections ADD COLUMN language TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections DROP COLUMN language`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_weave_collection_embed_template",
+			Version: "20240101000007",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections ADD COLUMN embed_template TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `ALTER TABLE weave_collections DROP COLUMN embed_template`)
+				return err
+			},
+		},
 	)
 }
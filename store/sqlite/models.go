@@ -10,6 +10,8 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
 )
 
 // ──────────────────────────────────────────────────
@@ -19,21 +21,24 @@ import (
 type collectionModel struct {
 	grove.BaseModel `grove:"table:weave_collections"`
 
-	ID             string    `grove:"id,pk"`
-	Name           string    `grove:"name,notnull"`
-	Description    string    `grove:"description"`
-	TenantID       string    `grove:"tenant_id,notnull"`
-	AppID          string    `grove:"app_id,notnull"`
-	EmbeddingModel string    `grove:"embedding_model,notnull"`
-	EmbeddingDims  int       `grove:"embedding_dims,notnull"`
-	ChunkStrategy  string    `grove:"chunk_strategy,notnull"`
-	ChunkSize      int       `grove:"chunk_size,notnull"`
-	ChunkOverlap   int       `grove:"chunk_overlap,notnull"`
-	Metadata       string    `grove:"metadata"`
-	DocumentCount  int64     `grove:"document_count,notnull"`
-	ChunkCount     int64     `grove:"chunk_count,notnull"`
-	CreatedAt      time.Time `grove:"created_at,notnull"`
-	UpdatedAt      time.Time `grove:"updated_at,notnull"`
+	ID             string     `grove:"id,pk"`
+	Name           string     `grove:"name,notnull"`
+	Description    string     `grove:"description"`
+	TenantID       string     `grove:"tenant_id,notnull"`
+	AppID          string     `grove:"app_id,notnull"`
+	EmbeddingModel string     `grove:"embedding_model,notnull"`
+	EmbeddingDims  int        `grove:"embedding_dims,notnull"`
+	ChunkStrategy  string     `grove:"chunk_strategy,notnull"`
+	ChunkSize      int        `grove:"chunk_size,notnull"`
+	ChunkOverlap   int        `grove:"chunk_overlap,notnull"`
+	Language       string     `grove:"language"`
+	EmbedTemplate  string     `grove:"embed_template"`
+	Metadata       string     `grove:"metadata"`
+	DocumentCount  int64      `grove:"document_count,notnull"`
+	ChunkCount     int64      `grove:"chunk_count,notnull"`
+	CreatedAt      time.Time  `grove:"created_at,notnull"`
+	UpdatedAt      time.Time  `grove:"updated_at,notnull"`
+	DeletedAt      *time.Time `grove:"deleted_at"`
 }
 
 func collectionToModel(c *collection.Collection) *collectionModel {
@@ -52,11 +57,14 @@ func collectionToModel(c *collection.Collection) *collectionModel {
 		ChunkStrategy:  c.ChunkStrategy,
 		ChunkSize:      c.ChunkSize,
 		ChunkOverlap:   c.ChunkOverlap,
+		Language:       c.Language,
+		EmbedTemplate:  c.EmbedTemplate,
 		Metadata:       string(metadata),
 		DocumentCount:  c.DocumentCount,
 		ChunkCount:     c.ChunkCount,
 		CreatedAt:      c.CreatedAt,
 		UpdatedAt:      c.UpdatedAt,
+		DeletedAt:      c.DeletedAt,
 	}
 }
 
@@ -80,9 +88,12 @@ func collectionFromModel(m *collectionModel) (*collection.Collection, error) {
 		ChunkStrategy:  m.ChunkStrategy,
 		ChunkSize:      m.ChunkSize,
 		ChunkOverlap:   m.ChunkOverlap,
+		Language:       m.Language,
+		EmbedTemplate:  m.EmbedTemplate,
 		Metadata:       metadata,
 		DocumentCount:  m.DocumentCount,
 		ChunkCount:     m.ChunkCount,
+		DeletedAt:      m.DeletedAt,
 	}, nil
 }
 
@@ -93,20 +104,21 @@ func collectionFromModel(m *collectionModel) (*collection.Collection, error) {
 type documentModel struct {
 	grove.BaseModel `grove:"table:weave_documents"`
 
-	ID            string    `grove:"id,pk"`
-	CollectionID  string    `grove:"collection_id,notnull"`
-	TenantID      string    `grove:"tenant_id,notnull"`
-	Title         string    `grove:"title"`
-	Source        string    `grove:"source"`
-	SourceType    string    `grove:"source_type"`
-	ContentHash   string    `grove:"content_hash,notnull"`
-	ContentLength int       `grove:"content_length,notnull"`
-	ChunkCount    int       `grove:"chunk_count,notnull"`
-	Metadata      string    `grove:"metadata"`
-	State         string    `grove:"state,notnull"`
-	Error         string    `grove:"error"`
-	CreatedAt     time.Time `grove:"created_at,notnull"`
-	UpdatedAt     time.Time `grove:"updated_at,notnull"`
+	ID            string     `grove:"id,pk"`
+	CollectionID  string     `grove:"collection_id,notnull"`
+	TenantID      string     `grove:"tenant_id,notnull"`
+	Title         string     `grove:"title"`
+	Source        string     `grove:"source"`
+	SourceType    string     `grove:"source_type"`
+	ContentHash   string     `grove:"content_hash,notnull"`
+	ContentLength int        `grove:"content_length,notnull"`
+	ChunkCount    int        `grove:"chunk_count,notnull"`
+	Metadata      string     `grove:"metadata"`
+	State         string     `grove:"state,notnull"`
+	Error         string     `grove:"error"`
+	CreatedAt     time.Time  `grove:"created_at,notnull"`
+	UpdatedAt     time.Time  `grove:"updated_at,notnull"`
+	DeletedAt     *time.Time `grove:"deleted_at"`
 }
 
 func documentToModel(d *document.Document) *documentModel {
@@ -129,6 +141,7 @@ func documentToModel(d *document.Document) *documentModel {
 		Error:         d.Error,
 		CreatedAt:     d.CreatedAt,
 		UpdatedAt:     d.UpdatedAt,
+		DeletedAt:     d.DeletedAt,
 	}
 }
 
@@ -158,6 +171,7 @@ func documentFromModel(m *documentModel) (*document.Document, error) {
 		Metadata:      metadata,
 		State:         document.State(m.State),
 		Error:         m.Error,
+		DeletedAt:     m.DeletedAt,
 	}, nil
 }
 
@@ -235,3 +249,89 @@ func chunkFromModel(m *chunkModel) (*chunk.Chunk, error) {
 		CreatedAt:    m.CreatedAt,
 	}, nil
 }
+
+// ──────────────────────────────────────────────────
+// Quota models
+// ──────────────────────────────────────────────────
+
+type quotaModel struct {
+	grove.BaseModel `grove:"table:weave_quotas"`
+
+	TenantID                   string    `grove:"tenant_id,pk"`
+	MaxCollections             int64     `grove:"max_collections,notnull"`
+	MaxDocuments               int64     `grove:"max_documents,notnull"`
+	MaxChunks                  int64     `grove:"max_chunks,notnull"`
+	MaxStoredBytes             int64     `grove:"max_stored_bytes,notnull"`
+	MaxEmbeddingTokensPerMonth int64     `grove:"max_embedding_tokens_per_month,notnull"`
+	CreatedAt                  time.Time `grove:"created_at,notnull"`
+	UpdatedAt                  time.Time `grove:"updated_at,notnull"`
+}
+
+func quotaToModel(q *quota.Quota) *quotaModel {
+	return &quotaModel{
+		TenantID:                   q.TenantID,
+		MaxCollections:             q.Limits.MaxCollections,
+		MaxDocuments:               q.Limits.MaxDocuments,
+		MaxChunks:                  q.Limits.MaxChunks,
+		MaxStoredBytes:             q.Limits.MaxStoredBytes,
+		MaxEmbeddingTokensPerMonth: q.Limits.MaxEmbeddingTokensPerMonth,
+		CreatedAt:                  q.CreatedAt,
+		UpdatedAt:                  q.UpdatedAt,
+	}
+}
+
+func quotaFromModel(m *quotaModel) *quota.Quota {
+	q := &quota.Quota{
+		TenantID: m.TenantID,
+		Limits: quota.Limits{
+			MaxCollections:             m.MaxCollections,
+			MaxDocuments:               m.MaxDocuments,
+			MaxChunks:                  m.MaxChunks,
+			MaxStoredBytes:             m.MaxStoredBytes,
+			MaxEmbeddingTokensPerMonth: m.MaxEmbeddingTokensPerMonth,
+		},
+	}
+	q.CreatedAt = m.CreatedAt
+	q.UpdatedAt = m.UpdatedAt
+	return q
+}
+
+type tenantTokensModel struct {
+	grove.BaseModel `grove:"table:weave_tenant_tokens"`
+
+	TenantID string `grove:"tenant_id,pk"`
+	Period   string `grove:"period,pk"`
+	Tokens   int64  `grove:"tokens,notnull"`
+}
+
+// ──────────────────────────────────────────────────
+// Usage model
+// ──────────────────────────────────────────────────
+
+type usageModel struct {
+	grove.BaseModel `grove:"table:weave_usage"`
+
+	ID           string    `grove:"id,pk"`
+	TenantID     string    `grove:"tenant_id,notnull"`
+	AppID        string    `grove:"app_id,notnull"`
+	CollectionID string    `grove:"collection_id,notnull"`
+	Operation    string    `grove:"operation,notnull"`
+	Model        string    `grove:"model,notnull"`
+	Tokens       int64     `grove:"tokens,notnull"`
+	Day          string    `grove:"day,notnull"`
+	CreatedAt    time.Time `grove:"created_at,notnull"`
+}
+
+func usageToModel(r *usage.Record) *usageModel {
+	return &usageModel{
+		ID:           r.ID.String(),
+		TenantID:     r.TenantID,
+		AppID:        r.AppID,
+		CollectionID: r.CollectionID.String(),
+		Operation:    string(r.Operation),
+		Model:        r.Model,
+		Tokens:       r.Tokens,
+		Day:          usage.Day(r.CreatedAt),
+		CreatedAt:    r.CreatedAt,
+	}
+}
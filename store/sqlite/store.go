@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/xraph/grove"
@@ -16,7 +17,9 @@ import (
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
 	"github.com/xraph/weave/id"
+	"github.com/xraph/weave/quota"
 	"github.com/xraph/weave/store"
+	"github.com/xraph/weave/usage"
 )
 
 // Compile-time interface check.
@@ -144,12 +147,16 @@ func (s *Store) DeleteCollection(ctx context.Context, colID id.CollectionID) err
 
 func (s *Store) ListCollections(ctx context.Context, filter *collection.ListFilter) ([]*collection.Collection, error) {
 	var models []collectionModel
-	q := s.sdb.NewSelect(&models).OrderExpr("created_at ASC")
+	q := s.sdb.NewSelect(&models).OrderExpr("created_at ASC").
+		Where(deletedClause(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.Search != "" {
 			q = q.Where("name LIKE '%' || ? || '%'", filter.Search)
 		}
+		if filter.TenantID != "" {
+			q = q.Where("tenant_id = ?", filter.TenantID)
+		}
 		if filter.Limit > 0 {
 			q = q.Limit(filter.Limit)
 		}
@@ -174,7 +181,8 @@ func (s *Store) ListCollections(ctx context.Context, filter *collection.ListFilt
 }
 
 func (s *Store) CountCollections(ctx context.Context, filter *collection.CountFilter) (int64, error) {
-	q := s.sdb.NewSelect((*collectionModel)(nil))
+	q := s.sdb.NewSelect((*collectionModel)(nil)).
+		Where(deletedClause(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.Search != "" {
@@ -255,18 +263,25 @@ func (s *Store) DeleteDocument(ctx context.Context, docID id.DocumentID) error {
 
 func (s *Store) ListDocuments(ctx context.Context, filter *document.ListFilter) ([]*document.Document, error) {
 	var models []documentModel
-	q := s.sdb.NewSelect(&models).OrderExpr("created_at ASC")
+	q := s.sdb.NewSelect(&models).OrderExpr("created_at ASC").
+		Where(deletedClause(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.CollectionID.String() != "" {
 			q = q.Where("collection_id = ?", filter.CollectionID.String())
 		}
+		if filter.TenantID != "" {
+			q = q.Where("tenant_id = ?", filter.TenantID)
+		}
 		if filter.State != "" {
 			q = q.Where("state = ?", string(filter.State))
 		}
 		if filter.Search != "" {
 			q = q.Where("title LIKE '%' || ? || '%'", filter.Search)
 		}
+		for key, value := range filter.Metadata {
+			q = q.Where("json_extract(metadata, ?) = ?", `$."`+strings.ReplaceAll(key, `"`, `\"`)+`"`, value)
+		}
 		if filter.Limit > 0 {
 			q = q.Limit(filter.Limit)
 		}
@@ -291,7 +306,8 @@ func (s *Store) ListDocuments(ctx context.Context, filter *document.ListFilter)
 }
 
 func (s *Store) CountDocuments(ctx context.Context, filter *document.CountFilter) (int64, error) {
-	q := s.sdb.NewSelect((*documentModel)(nil))
+	q := s.sdb.NewSelect((*documentModel)(nil)).
+		Where(deletedClause(filter != nil && filter.Trashed))
 
 	if filter != nil {
 		if filter.CollectionID.String() != "" {
@@ -414,6 +430,196 @@ func (s *Store) CountChunks(ctx context.Context, filter *chunk.CountFilter) (int
 	return count, nil
 }
 
+// ──────────────────────────────────────────────────
+// Quota operations
+// ──────────────────────────────────────────────────
+
+func (s *Store) GetQuota(ctx context.Context, tenantID string) (*quota.Quota, error) {
+	m := new(quotaModel)
+	err := s.sdb.NewSelect(m).Where("tenant_id = ?", tenantID).Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, weave.ErrQuotaNotFound
+		}
+		return nil, fmt.Errorf("weave: get quota: %w", err)
+	}
+	return quotaFromModel(m), nil
+}
+
+func (s *Store) SetQuota(ctx context.Context, q *quota.Quota) error {
+	now := time.Now().UTC()
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = now
+	}
+	q.UpdatedAt = now
+	m := quotaToModel(q)
+
+	_, err := s.sdb.NewInsert(m).
+		OnConflict("(tenant_id) DO UPDATE").
+		Set("max_collections = EXCLUDED.max_collections").
+		Set("max_documents = EXCLUDED.max_documents").
+		Set("max_chunks = EXCLUDED.max_chunks").
+		Set("max_stored_bytes = EXCLUDED.max_stored_bytes").
+		Set("max_embedding_tokens_per_month = EXCLUDED.max_embedding_tokens_per_month").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: set quota: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) DeleteQuota(ctx context.Context, tenantID string) error {
+	res, err := s.sdb.NewDelete((*quotaModel)(nil)).
+		Where("tenant_id = ?", tenantID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: delete quota: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("weave: delete quota rows affected: %w", err)
+	}
+	if n == 0 {
+		return weave.ErrQuotaNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListQuotas(ctx context.Context) ([]*quota.Quota, error) {
+	var models []quotaModel
+	if err := s.sdb.NewSelect(&models).OrderExpr("tenant_id ASC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("weave: list quotas: %w", err)
+	}
+
+	result := make([]*quota.Quota, len(models))
+	for i := range models {
+		result[i] = quotaFromModel(&models[i])
+	}
+	return result, nil
+}
+
+func (s *Store) GetUsage(ctx context.Context, tenantID, period string) (*quota.Usage, error) {
+	u := &quota.Usage{TenantID: tenantID, Period: period}
+	err := s.sdb.QueryRow(ctx, `
+SELECT
+    (SELECT COUNT(*) FROM weave_collections WHERE tenant_id = ?1),
+    (SELECT COUNT(*) FROM weave_documents WHERE tenant_id = ?1),
+    (SELECT COUNT(*) FROM weave_chunks WHERE tenant_id = ?1),
+    (SELECT COALESCE(SUM(content_length), 0) FROM weave_documents WHERE tenant_id = ?1),
+    (SELECT COALESCE(SUM(tokens), 0) FROM weave_tenant_tokens WHERE tenant_id = ?1 AND period = ?2)
+`, tenantID, period).Scan(&u.Collections, &u.Documents, &u.Chunks, &u.StoredBytes, &u.EmbeddingTokens)
+	if err != nil {
+		return nil, fmt.Errorf("weave: get usage: %w", err)
+	}
+	return u, nil
+}
+
+func (s *Store) AddEmbeddingTokens(ctx context.Context, tenantID, period string, tokens int64) error {
+	m := &tenantTokensModel{TenantID: tenantID, Period: period, Tokens: tokens}
+
+	_, err := s.sdb.NewInsert(m).
+		OnConflict("(tenant_id, period) DO UPDATE").
+		Set("tokens = weave_tenant_tokens.tokens + EXCLUDED.tokens").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("weave: add embedding tokens: %w", err)
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────
+// Usage operations
+// ──────────────────────────────────────────────────
+
+func (s *Store) RecordUsage(ctx context.Context, r *usage.Record) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	m := usageToModel(r)
+	if _, err := s.sdb.NewInsert(m).Exec(ctx); err != nil {
+		return fmt.Errorf("weave: record usage: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) QueryUsage(ctx context.Context, q *usage.Query) ([]*usage.Summary, error) {
+	period := "day"
+	if q.Granularity == usage.GranularityMonth {
+		period = "substr(day, 1, 7)"
+	}
+
+	var (
+		where []string
+		args  []any
+	)
+	add := func(clause string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(clause, fmt.Sprintf("?%d", len(args))))
+	}
+	if q.TenantID != "" {
+		add("tenant_id = %s", q.TenantID)
+	}
+	if q.AppID != "" {
+		add("app_id = %s", q.AppID)
+	}
+	if !q.CollectionID.IsNil() {
+		add("collection_id = %s", q.CollectionID.String())
+	}
+	if q.Operation != "" {
+		add("operation = %s", string(q.Operation))
+	}
+	if !q.From.IsZero() {
+		add("created_at >= %s", q.From.UTC())
+	}
+	if !q.To.IsZero() {
+		add("created_at < %s", q.To.UTC())
+	}
+
+	query := "SELECT " + period + ", tenant_id, app_id, collection_id, operation, model, SUM(tokens), COUNT(*) FROM weave_usage"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " GROUP BY " + period + ", tenant_id, app_id, collection_id, operation, model" +
+		" ORDER BY " + period + ", tenant_id, app_id, collection_id, operation, model"
+
+	rows, err := s.sdb.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("weave: query usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []*usage.Summary
+	for rows.Next() {
+		var (
+			sum   usage.Summary
+			colID string
+			op    string
+		)
+		if err := rows.Scan(&sum.Period, &sum.TenantID, &sum.AppID, &colID, &op, &sum.Model, &sum.Tokens, &sum.Requests); err != nil {
+			return nil, fmt.Errorf("weave: scan usage: %w", err)
+		}
+		if colID != "" {
+			sum.CollectionID, _ = id.ParseCollectionID(colID) //nolint:errcheck // DB rows always contain valid IDs
+		}
+		sum.Operation = usage.Operation(op)
+		result = append(result, &sum)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("weave: query usage: %w", err)
+	}
+	return result, nil
+}
+
+// deletedClause returns the WHERE clause selecting live rows, or
+// soft-deleted rows when trashed is true.
+func deletedClause(trashed bool) string {
+	if trashed {
+		return "deleted_at IS NOT NULL"
+	}
+	return "deleted_at IS NULL"
+}
+
 // isNoRows checks for the standard sql.ErrNoRows sentinel.
 func isNoRows(err error) bool {
 	return errors.Is(err, sql.ErrNoRows)
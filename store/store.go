@@ -1,5 +1,5 @@
 // Package store defines the composite metadata store interface for Weave.
-// It aggregates collection, document, and chunk store operations with
+// It aggregates collection, document, chunk, quota, and usage store operations with
 // lifecycle management (migrations, health checks, shutdown).
 package store
 
@@ -9,15 +9,19 @@ import (
 	"github.com/xraph/weave/chunk"
 	"github.com/xraph/weave/collection"
 	"github.com/xraph/weave/document"
+	"github.com/xraph/weave/quota"
+	"github.com/xraph/weave/usage"
 )
 
 // Store is the composite metadata store interface for Weave.
 // It embeds the subsystem store interfaces for collections, documents,
-// and chunks, plus lifecycle management methods.
+// chunks, quotas, and usage records, plus lifecycle management methods.
 type Store interface {
 	document.Store
 	collection.Store
 	chunk.Store
+	quota.Store
+	usage.Store
 
 	// Migrate runs any pending database migrations.
 	Migrate(ctx context.Context) error
@@ -0,0 +1,43 @@
+package tokenizer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ParseRanks reads a rank table in the .tiktoken format: one token per
+// line, written as the base64 encoding of its bytes followed by a space
+// and its rank.
+func ParseRanks(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		tok, rank, ok := bytes.Cut(text, []byte(" "))
+		if !ok {
+			return nil, fmt.Errorf("weave: tokenizer ranks line %d: missing rank", line)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(string(tok))
+		if err != nil {
+			return nil, fmt.Errorf("weave: tokenizer ranks line %d: %w", line, err)
+		}
+		n, err := strconv.Atoi(string(rank))
+		if err != nil {
+			return nil, fmt.Errorf("weave: tokenizer ranks line %d: %w", line, err)
+		}
+		ranks[string(decoded)] = n
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("weave: tokenizer ranks: %w", err)
+	}
+	return ranks, nil
+}
@@ -0,0 +1,127 @@
+package tokenizer
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+// Well-known encoding names.
+const (
+	Cl100kBase = "cl100k_base"
+	O200kBase  = "o200k_base"
+)
+
+var (
+	// ErrUnknownEncoding is returned for encoding or model names the
+	// package does not know.
+	ErrUnknownEncoding = errors.New("weave: unknown tokenizer encoding")
+	// ErrVocabUnavailable is returned by Get when the rank table for a known
+	// encoding is missing from tokenizer/vocab. Run make tokenizer-vocab, or
+	// use Load with a rank file.
+	ErrVocabUnavailable = errors.New("weave: tokenizer vocabulary not embedded")
+)
+
+// patterns holds the pre-tokenization regular expression of each encoding.
+var patterns = map[string]string{
+	Cl100kBase: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+`,
+	O200kBase: strings.Join([]string{
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?`,
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?`,
+		`\p{N}{1,3}`,
+		` ?[^\s\p{L}\p{N}]+[\r\n/]*`,
+		`\s*[\r\n]+`,
+		`\s+(?!\S)`,
+		`\s+`,
+	}, "|"),
+}
+
+// modelPrefixes maps model name prefixes to their encoding. Longer, more
+// specific prefixes come first.
+var modelPrefixes = []struct{ prefix, encoding string }{
+	{"gpt-4o", O200kBase},
+	{"gpt-4.1", O200kBase},
+	{"gpt-4.5", O200kBase},
+	{"gpt-5", O200kBase},
+	{"o1", O200kBase},
+	{"o3", O200kBase},
+	{"o4", O200kBase},
+	{"gpt-4", Cl100kBase},
+	{"gpt-3.5", Cl100kBase},
+	{"text-embedding-3", Cl100kBase},
+	{"text-embedding-ada-002", Cl100kBase},
+}
+
+var (
+	mu     sync.Mutex
+	loaded = map[string]*Encoding{}
+)
+
+// Get returns the named encoding, parsing its embedded rank table on first
+// use.
+func Get(name string) (*Encoding, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if enc, ok := loaded[name]; ok {
+		return enc, nil
+	}
+	if _, ok := patterns[name]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoding, name)
+	}
+	f, err := vocabFS.Open("vocab/" + name + ".tiktoken.gz")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrVocabUnavailable, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("weave: tokenizer %s: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("weave: tokenizer %s: %w", name, err)
+	}
+
+	enc, err := Load(name, zr)
+	if err != nil {
+		return nil, err
+	}
+	loaded[name] = enc
+	return enc, nil
+}
+
+// Load creates the named encoding from a .tiktoken rank file.
+func Load(name string, r io.Reader) (*Encoding, error) {
+	pattern, ok := patterns[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoding, name)
+	}
+	ranks, err := ParseRanks(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncoding(name, pattern, ranks)
+}
+
+// EncodingForModel returns the encoding name used by an OpenAI model.
+func EncodingForModel(model string) (string, bool) {
+	for _, m := range modelPrefixes {
+		if strings.HasPrefix(model, m.prefix) {
+			return m.encoding, true
+		}
+	}
+	return "", false
+}
+
+// ForModel returns the encoding used by an OpenAI model.
+func ForModel(model string) (*Encoding, error) {
+	name, ok := EncodingForModel(model)
+	if !ok {
+		return nil, fmt.Errorf("%w: model %q", ErrUnknownEncoding, model)
+	}
+	return Get(name)
+}
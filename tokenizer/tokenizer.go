@@ -0,0 +1,153 @@
+// Package tokenizer implements byte-level BPE tokenization compatible with
+// OpenAI's cl100k_base and o200k_base encodings, so chunk sizes, context
+// budgets and usage figures can be measured in real model tokens.
+package tokenizer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dlclark/regexp2"
+)
+
+// Encoding is a byte-pair encoding: a pre-tokenization pattern plus a table
+// of mergeable byte sequences and their ranks. An Encoding is safe for
+// concurrent use and satisfies assembler.TokenCounter.
+type Encoding struct {
+	name    string
+	pattern *regexp2.Regexp
+	ranks   map[string]int
+	decoder map[int]string
+}
+
+// NewEncoding creates an encoding from a pre-tokenization pattern and a rank
+// table. Every single byte must have a rank so any input can be encoded.
+func NewEncoding(name, pattern string, ranks map[string]int) (*Encoding, error) {
+	re, err := regexp2.Compile(pattern, regexp2.None)
+	if err != nil {
+		return nil, fmt.Errorf("weave: tokenizer %s: compile pattern: %w", name, err)
+	}
+	for b := range 256 {
+		if _, ok := ranks[string([]byte{byte(b)})]; !ok {
+			return nil, fmt.Errorf("weave: tokenizer %s: no rank for byte 0x%02x", name, b)
+		}
+	}
+
+	decoder := make(map[int]string, len(ranks))
+	for tok, rank := range ranks {
+		decoder[rank] = tok
+	}
+
+	return &Encoding{
+		name:    name,
+		pattern: re,
+		ranks:   ranks,
+		decoder: decoder,
+	}, nil
+}
+
+// Name returns the encoding name, e.g. "cl100k_base".
+func (e *Encoding) Name() string { return e.name }
+
+// Encode converts text into token ids. Special tokens such as
+// <|endoftext|> are treated as ordinary text.
+func (e *Encoding) Encode(text string) []int {
+	var tokens []int
+	e.pieces(text, func(piece string) {
+		if rank, ok := e.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			return
+		}
+		tokens = e.bytePairEncode(piece, tokens)
+	})
+	return tokens
+}
+
+// CountTokens returns the number of tokens in text.
+func (e *Encoding) CountTokens(text string) int {
+	n := 0
+	e.pieces(text, func(piece string) {
+		if _, ok := e.ranks[piece]; ok {
+			n++
+			return
+		}
+		n += len(bytePairMerge(piece, e.ranks)) - 1
+	})
+	return n
+}
+
+// Decode converts token ids back into text. Unknown ids are skipped.
+func (e *Encoding) Decode(tokens []int) string {
+	var b []byte
+	for _, t := range tokens {
+		b = append(b, e.decoder[t]...)
+	}
+	return string(b)
+}
+
+// pieces calls fn for every match of the pre-tokenization pattern.
+func (e *Encoding) pieces(text string, fn func(piece string)) {
+	m, err := e.pattern.FindStringMatch(text)
+	for m != nil && err == nil {
+		fn(m.String())
+		m, err = e.pattern.FindNextMatch(m)
+	}
+}
+
+// bytePairEncode appends the ranks of the BPE tokens of piece to tokens.
+func (e *Encoding) bytePairEncode(piece string, tokens []int) []int {
+	bounds := bytePairMerge(piece, e.ranks)
+	for i := 0; i < len(bounds)-1; i++ {
+		tokens = append(tokens, e.ranks[piece[bounds[i]:bounds[i+1]]])
+	}
+	return tokens
+}
+
+// bytePairMerge repeatedly merges the adjacent pair of byte sequences with
+// the lowest rank and returns the resulting token boundaries, including 0
+// and len(piece).
+func bytePairMerge(piece string, ranks map[string]int) []int {
+	type part struct{ start, rank int }
+
+	parts := make([]part, len(piece)+1)
+	for i := range parts {
+		parts[i] = part{start: i, rank: math.MaxInt}
+	}
+	rankAt := func(i int) int {
+		if i+2 < len(parts) {
+			if r, ok := ranks[piece[parts[i].start:parts[i+2].start]]; ok {
+				return r
+			}
+		}
+		return math.MaxInt
+	}
+	for i := 0; i < len(parts)-2; i++ {
+		parts[i].rank = rankAt(i)
+	}
+
+	for len(parts) > 2 {
+		minIdx, minRank := 0, math.MaxInt
+		for i := 0; i < len(parts)-2; i++ {
+			if parts[i].rank < minRank {
+				minIdx, minRank = i, parts[i].rank
+			}
+		}
+		if minRank == math.MaxInt {
+			break
+		}
+
+		// Merge parts[minIdx] and parts[minIdx+1], then rerank the pairs
+		// that now start at minIdx-1 and minIdx.
+		parts = append(parts[:minIdx+1], parts[minIdx+2:]...)
+		parts[minIdx].rank = rankAt(minIdx)
+		if minIdx > 0 {
+			parts[minIdx-1].rank = rankAt(minIdx - 1)
+		}
+	}
+
+	bounds := make([]int, len(parts))
+	for i, p := range parts {
+		bounds[i] = p.start
+	}
+	return bounds
+}
@@ -0,0 +1,127 @@
+package tokenizer_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xraph/weave/tokenizer"
+)
+
+// rankFile builds a .tiktoken rank table holding every single byte plus the
+// given merges, ranked in order after the bytes.
+func rankFile(merges ...string) string {
+	var b strings.Builder
+	rank := 0
+	for i := range 256 {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), rank)
+		rank++
+	}
+	for _, m := range merges {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(m)), rank)
+		rank++
+	}
+	return b.String()
+}
+
+func TestEncodeMergesLowestRankFirst(t *testing.T) {
+	for _, name := range []string{tokenizer.Cl100kBase, tokenizer.O200kBase} {
+		t.Run(name, func(t *testing.T) {
+			enc, err := tokenizer.Load(name, strings.NewReader(rankFile("he", "ll", "hell", " w", " wor", "or")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := enc.Encode("hello world")
+			// "hello" -> "he" "ll" -> "hell" + "o"; " world" -> " w" + "or"
+			// -> " wor" + "l" + "d".
+			want := []int{258, 'o', 260, 'l', 'd'}
+			if !slices.Equal(got, want) {
+				t.Fatalf("Encode = %v, want %v", got, want)
+			}
+			if n := enc.CountTokens("hello world"); n != len(want) {
+				t.Errorf("CountTokens = %d, want %d", n, len(want))
+			}
+		})
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	enc, err := tokenizer.Load(tokenizer.Cl100kBase, strings.NewReader(rankFile("th", "the", " the", "日")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		"",
+		"the theme of the day",
+		"東京は日本の首都です。",
+		"emoji 👨‍👩‍👧‍👦 and tabs\t\n\n  trailing   ",
+		"it's they'll I'M 12345",
+	} {
+		if got := enc.Decode(enc.Encode(text)); got != text {
+			t.Errorf("Decode(Encode(%q)) = %q", text, got)
+		}
+	}
+}
+
+func TestLoadRequiresAllBytes(t *testing.T) {
+	if _, err := tokenizer.Load(tokenizer.Cl100kBase, strings.NewReader("aGk= 0\n")); err == nil {
+		t.Fatal("expected error for rank table without single bytes")
+	}
+	if _, err := tokenizer.Load("p50k_base", strings.NewReader(rankFile())); !errors.Is(err, tokenizer.ErrUnknownEncoding) {
+		t.Fatalf("expected ErrUnknownEncoding, got %v", err)
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o-mini":            tokenizer.O200kBase,
+		"gpt-4-turbo":            tokenizer.Cl100kBase,
+		"text-embedding-3-small": tokenizer.Cl100kBase,
+		"o3-mini":                tokenizer.O200kBase,
+	}
+	for model, want := range tests {
+		if got, _ := tokenizer.EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+	if _, ok := tokenizer.EncodingForModel("claude"); ok {
+		t.Error("expected unknown model")
+	}
+}
+
+// TestEmbeddedParity checks the embedded encodings against token IDs
+// produced by OpenAI's tiktoken.
+func TestEmbeddedParity(t *testing.T) {
+	tests := []struct {
+		encoding string
+		text     string
+		want     []int
+	}{
+		{tokenizer.Cl100kBase, "hello world", []int{15339, 1917}},
+		{tokenizer.Cl100kBase, "tiktoken is great!", []int{83, 1609, 5963, 374, 2294, 0}},
+		{tokenizer.O200kBase, "hello world", []int{24912, 2375}},
+		{tokenizer.O200kBase, "tiktoken is great!", []int{83, 8251, 2488, 382, 2212, 0}},
+		{tokenizer.Cl100kBase, "Don't panic: 1234567 tokens, naïve café!", []int{8161, 956, 22743, 25, 220, 4513, 10961, 22, 11460, 11, 95980, 588, 53050, 0}},
+		{tokenizer.Cl100kBase, "  indented\n\n\tcode() {\n    return x;\n}", []int{220, 1280, 16243, 271, 44443, 368, 341, 262, 471, 865, 280, 92}},
+		{tokenizer.Cl100kBase, "東京は日本の首都です。👍🏽", []int{14276, 109, 47653, 15682, 9080, 22656, 16144, 61075, 72368, 38641, 1811, 9468, 239, 235, 9468, 237, 121}},
+		{tokenizer.O200kBase, "Don't panic: 1234567 tokens, naïve café!", []int{31559, 40130, 25, 220, 7633, 19354, 22, 20290, 11, 153475, 737, 30469, 0}},
+		{tokenizer.O200kBase, "  indented\n\n\tcode() {\n    return x;\n}", []int{220, 1383, 23537, 279, 86873, 416, 405, 271, 622, 1215, 307, 92}},
+		{tokenizer.O200kBase, "東京は日本の首都です。👍🏽", []int{108713, 5205, 9048, 3385, 15425, 12232, 15121, 788, 82514, 52622, 121}},
+	}
+	for _, tt := range tests {
+		enc, err := tokenizer.Get(tt.encoding)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.encoding, err)
+		}
+		if got := enc.Encode(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("%s Encode(%q) = %v, want %v", tt.encoding, tt.text, got, tt.want)
+		}
+		if got := enc.Decode(tt.want); got != tt.text {
+			t.Errorf("%s Decode(%v) = %q, want %q", tt.encoding, tt.want, got, tt.text)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package tokenizer
+
+import "embed"
+
+// vocabFS holds the rank tables as vocab/<encoding>.tiktoken.gz, the
+// gzipped .tiktoken files. make tokenizer-vocab regenerates them.
+//
+//go:embed vocab
+var vocabFS embed.FS
@@ -0,0 +1,13 @@
+package usage
+
+import "context"
+
+// Store defines the persistence contract for usage records.
+type Store interface {
+	// RecordUsage persists a usage record.
+	RecordUsage(ctx context.Context, r *Record) error
+
+	// QueryUsage aggregates usage records matching the query, ordered by
+	// period and then tenant, app, collection, operation, and model.
+	QueryUsage(ctx context.Context, q *Query) ([]*Summary, error)
+}
@@ -0,0 +1,80 @@
+// Package usage records embedding token consumption for chargeback.
+package usage
+
+import (
+	"time"
+
+	"github.com/xraph/weave/id"
+)
+
+// Operation identifies the engine operation that consumed tokens.
+type Operation string
+
+const (
+	OperationIngest  Operation = "ingest"
+	OperationQuery   Operation = "query"
+	OperationReindex Operation = "reindex"
+)
+
+// Record is a single embedding call's token consumption.
+type Record struct {
+	ID           id.UsageID      `json:"id" bun:"id,pk"`
+	TenantID     string          `json:"tenant_id" bun:"tenant_id,notnull"`
+	AppID        string          `json:"app_id" bun:"app_id,notnull"`
+	CollectionID id.CollectionID `json:"collection_id,omitempty" bun:"collection_id"`
+	Operation    Operation       `json:"operation" bun:"operation,notnull"`
+	Model        string          `json:"model" bun:"model,notnull"`
+	Tokens       int64           `json:"tokens" bun:"tokens,notnull"`
+	CreatedAt    time.Time       `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// Granularity is the time bucket size for aggregated usage.
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityMonth Granularity = "month"
+)
+
+// Valid reports whether g is a known granularity.
+func (g Granularity) Valid() bool {
+	return g == GranularityDay || g == GranularityMonth
+}
+
+// Period returns the bucket key containing t: "2006-01-02" for days and
+// "2006-01" for months.
+func (g Granularity) Period(t time.Time) string {
+	if g == GranularityMonth {
+		return t.UTC().Format("2006-01")
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+// Day returns the day key stored with each record and used for
+// aggregation.
+func Day(t time.Time) string { return GranularityDay.Period(t) }
+
+// Query selects and groups usage records. Empty fields match everything.
+type Query struct {
+	TenantID     string
+	AppID        string
+	CollectionID id.CollectionID
+	Operation    Operation
+	// From and To bound the record timestamps; To is exclusive.
+	From        time.Time
+	To          time.Time
+	Granularity Granularity
+}
+
+// Summary is the total usage for one period, tenant, app, collection,
+// operation, and model.
+type Summary struct {
+	Period       string          `json:"period"`
+	TenantID     string          `json:"tenant_id"`
+	AppID        string          `json:"app_id"`
+	CollectionID id.CollectionID `json:"collection_id,omitempty"`
+	Operation    Operation       `json:"operation"`
+	Model        string          `json:"model"`
+	Tokens       int64           `json:"tokens"`
+	Requests     int64           `json:"requests"`
+}